@@ -1,7 +1,11 @@
 SELECT
     username,
-    first_name,
-    last_name
+    COALESCE(first_name, ''),
+    COALESCE(last_name, ''),
+    COALESCE(email, ''),
+    email_verified,
+    enabled,
+    COALESCE(created_timestamp, 0)
 FROM
     user_entity
 WHERE
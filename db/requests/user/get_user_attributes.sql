@@ -0,0 +1,13 @@
+SELECT
+    user_id,
+    name,
+    COALESCE(value, '')
+FROM
+    user_attribute
+WHERE
+    user_id = ANY($1)
+    AND name = ANY($2)
+ORDER BY
+    user_id,
+    name,
+    value;
\ No newline at end of file
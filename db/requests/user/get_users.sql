@@ -0,0 +1,14 @@
+SELECT
+    id,
+    username,
+    COALESCE(first_name, ''),
+    COALESCE(last_name, ''),
+    COALESCE(email, ''),
+    email_verified,
+    enabled,
+    COALESCE(created_timestamp, 0)
+FROM
+    user_entity
+WHERE
+    id = ANY($1)
+    AND realm_id = $2;
\ No newline at end of file
@@ -0,0 +1,20 @@
+SELECT
+    id,
+    username,
+    COALESCE(first_name, ''),
+    COALESCE(last_name, '')
+FROM
+    user_entity
+WHERE
+    realm_id = $1
+    AND (
+        username LIKE $2 ESCAPE '\'
+        OR lower(email) LIKE $2 ESCAPE '\'
+        OR lower(first_name) LIKE $2 ESCAPE '\'
+        OR lower(last_name) LIKE $2 ESCAPE '\'
+    )
+    AND username > $3
+ORDER BY
+    username
+LIMIT
+    $4;
\ No newline at end of file
@@ -16,11 +16,14 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/valyala/fasthttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
 
 	"github.com/dnonakolesax/noted-auth/internal/configs"
 	"github.com/dnonakolesax/noted-auth/internal/consts"
 	authProto "github.com/dnonakolesax/noted-auth/internal/delivery/auth/v1/proto"
 	userProto "github.com/dnonakolesax/noted-auth/internal/delivery/user/v1/proto"
+	"github.com/dnonakolesax/noted-auth/internal/interceptors"
 	"github.com/dnonakolesax/noted-auth/internal/logger"
 	"github.com/dnonakolesax/noted-auth/internal/middlewares"
 	"github.com/dnonakolesax/noted-auth/internal/routing"
@@ -83,8 +86,11 @@ func (a *App) Run() {
 	router := routing.NewRouter()
 	p := fasthttpprom.NewPrometheus("")
 	p.Use(router.Router())
-	router.NewAPIGroup(a.configs.Service.BasePath, "1",
-		a.layers.authHTTP, a.layers.userHTTP, a.layers.sessionHTTP, a.layers.hcHTTP)
+	handlers := []routing.HTTPHandler{a.layers.authHTTP, a.layers.userHTTP, a.layers.sessionHTTP, a.layers.hcHTTP}
+	if a.layers.adminHTTP != nil {
+		handlers = append(handlers, a.layers.adminHTTP)
+	}
+	router.NewAPIGroup(a.configs.Service.BasePath, "1", handlers...)
 
 	wg := &sync.WaitGroup{}
 
@@ -103,14 +109,35 @@ func (a *App) Run() {
 		panic(fmt.Sprintf("error listening grpc net: %v", err))
 	}
 
-	grpcSrv := grpc.NewServer()
+	grpcOpts := interceptors.ServerOptions(a.metrics.GRPCServerMetrics, a.layers.grpcAuth, a.layers.serviceAuth,
+		a.loggers.GRPC)
+
+	if tlsCfg := a.grpcTLSConfig(); tlsCfg != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+
+	grpcSrv := grpc.NewServer(grpcOpts...)
 	userProto.RegisterUserServiceServer(grpcSrv, a.layers.userGRPC)
 	authProto.RegisterAuthServiceServer(grpcSrv, a.layers.authGRPC)
+	a.layers.hcGRPC.Register(grpcSrv)
+
+	if a.configs.Service.GRPCReflection {
+		reflection.Register(grpcSrv)
+	}
+
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	go a.layers.hcGRPC.Run(healthCtx)
+
+	certsCtx, stopCerts := context.WithCancel(context.Background())
+	defer stopCerts()
+	go a.watchCerts(certsCtx)
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		a.initLogger.Info("Starting GRPC server", slog.Int("Port", a.configs.Service.GRPCPort))
+		a.initLogger.Info("Starting GRPC server", slog.Int("Port", a.configs.Service.GRPCPort),
+			slog.Bool("tls", a.configs.TLS.GRPCEnabled), slog.Bool("mtls", a.configs.TLS.GRPCMutual))
 		err = grpcSrv.Serve(listener)
 
 		if err != nil {
@@ -146,8 +173,12 @@ func (a *App) Run() {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		a.initLogger.Info("Starting HTTP server", slog.Int("Port", a.configs.Service.Port))
-		httpErr := srv.ListenAndServe(":" + strconv.Itoa(a.configs.Service.Port))
+		a.initLogger.Info("Starting HTTP server", slog.Int("Port", a.configs.Service.Port),
+			slog.Bool("tls", a.configs.TLS.HTTPEnabled))
+		httpListener, httpErr := cfg.Listen(context.Background(), "tcp4", ":"+strconv.Itoa(a.configs.Service.Port))
+		if httpErr == nil {
+			httpErr = srv.Serve(a.wrapHTTPListener(httpListener))
+		}
 		if httpErr != nil {
 			a.initLogger.Error(fmt.Sprintf("Couldn't start server: %v", err))
 		}
@@ -188,7 +219,10 @@ func (a *App) Run() {
 	/*               GRPC SERVER STOP               */
 	/************************************************/
 
+	stopHealth()
+	a.layers.hcGRPC.Shutdown()
 	grpcSrv.Stop()
+	stopCerts()
 
 	/************************************************/
 	/*             METRICS SERVER STOP              */
@@ -203,6 +237,12 @@ func (a *App) Run() {
 			slog.String(consts.ErrorLoggerKey, err.Error()))
 	}
 
+	/************************************************/
+	/*               STATE STORE STOP               */
+	/************************************************/
+
+	a.layers.stateStore.Close()
+
 	a.components.pgsql.Conn.Disconnect()
 	_ = a.components.redis.Client.Close()
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/dnonakolesax/noted-auth/internal/certs"
 	"github.com/dnonakolesax/noted-auth/internal/consts"
 	dbredis "github.com/dnonakolesax/noted-auth/internal/db/redis"
 	dbsql "github.com/dnonakolesax/noted-auth/internal/db/sql"
@@ -16,6 +17,9 @@ type Components struct {
 	keycloak   *httpclient.HTTPClient
 	keycloak2  *httpclient.HTTPClient
 	keycloak2d *httpclient.HTTPClient
+	revoke     *httpclient.HTTPClient
+	admin      *httpclient.HTTPClient
+	certs      *certs.Store
 }
 
 func (a *App) SetupComponents() error {
@@ -93,12 +97,60 @@ func (a *App) SetupComponents() error {
 	}
 
 	a.initLogger.InfoContext(context.Background(), "Created HTTP client, keycloak pinged")
+
+	/************************************************/
+	/*              HTTP CLIENT SETUP               */
+	/************************************************/
+	a.initLogger.InfoContext(context.Background(), "Creating HTTP client for token revocation")
+	revokeClient, err := httpclient.NewWithRetry(a.configs.Keycloak.InterRealmAddress+a.configs.Keycloak.RevokeEndpoint,
+		a.configs.HTTPClient, a.metrics.TokenRevokeMetrics, a.health.Keycloak, a.loggers.HTTPc)
+
+	if err != nil {
+		a.initLogger.ErrorContext(context.Background(), "Error connecting to keycloak",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return err
+	}
+
+	a.initLogger.InfoContext(context.Background(), "Created HTTP client, keycloak pinged")
+
+	/************************************************/
+	/*              HTTP CLIENT SETUP               */
+	/************************************************/
+	var adminClient *httpclient.HTTPClient
+
+	if a.configs.Admin.Enabled {
+		// admin API без токена отвечает 401, а не 405, поэтому HEAD-проверки здесь нет
+		a.initLogger.InfoContext(context.Background(), "Creating HTTP client for keycloak admin API")
+		adminClient = httpclient.New(a.configs.Admin.Address+"/users", a.configs.HTTPClient, a.metrics.AdminMetrics,
+			a.health.Keycloak, a.loggers.HTTPc)
+	}
+
+	/************************************************/
+	/*              TLS CERTIFICATES                */
+	/************************************************/
+	var certStore *certs.Store
+
+	if a.configs.TLS.Enabled() {
+		a.initLogger.InfoContext(context.Background(), "Loading TLS certificates",
+			slog.String("source", a.configs.TLS.Source))
+		certStore, err = a.setupCerts()
+
+		if err != nil {
+			a.initLogger.ErrorContext(context.Background(), "Error loading TLS certificates",
+				slog.String(consts.ErrorLoggerKey, err.Error()))
+			return err
+		}
+	}
+
 	a.components = &Components{
 		pgsql:      psqlWorker,
 		redis:      redisClient,
 		keycloak:   httpClient,
 		keycloak2:  httpClient2,
 		keycloak2d: httpClient3,
+		revoke:     revokeClient,
+		admin:      adminClient,
+		certs:      certStore,
 	}
 	return nil
 }
@@ -5,27 +5,46 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/dnonakolesax/noted-auth/internal/configs"
 	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/cookies"
+	"github.com/dnonakolesax/noted-auth/internal/interceptors"
+	"github.com/dnonakolesax/noted-auth/internal/jwt"
+	"github.com/dnonakolesax/noted-auth/internal/kcadmin"
 	"github.com/dnonakolesax/noted-auth/internal/middlewares"
 
+	exchangeRepo "github.com/dnonakolesax/noted-auth/internal/repo/exchange"
+	introspectionRepo "github.com/dnonakolesax/noted-auth/internal/repo/introspection"
+	refreshRepo "github.com/dnonakolesax/noted-auth/internal/repo/refresh"
+	sessionRepo "github.com/dnonakolesax/noted-auth/internal/repo/session"
 	stateRepo "github.com/dnonakolesax/noted-auth/internal/repo/state"
 	userRepo "github.com/dnonakolesax/noted-auth/internal/repo/user"
+	"github.com/dnonakolesax/noted-auth/internal/seal"
 
 	"github.com/dnonakolesax/noted-auth/internal/usecase"
 
+	adminDelivery "github.com/dnonakolesax/noted-auth/internal/delivery/admin/v1"
 	authDelivery "github.com/dnonakolesax/noted-auth/internal/delivery/auth/v1"
+	authProto "github.com/dnonakolesax/noted-auth/internal/delivery/auth/v1/proto"
 	healthDelivery "github.com/dnonakolesax/noted-auth/internal/delivery/healthcheck/v1"
 	sessionDelivery "github.com/dnonakolesax/noted-auth/internal/delivery/session/v1"
 	userDelivery "github.com/dnonakolesax/noted-auth/internal/delivery/user/v1"
+	userProto "github.com/dnonakolesax/noted-auth/internal/delivery/user/v1/proto"
 )
 
 type Layers struct {
 	authHTTP    *authDelivery.Handler
 	hcHTTP      *healthDelivery.Handler
+	hcGRPC      *healthDelivery.GRPCHealth
 	sessionHTTP *sessionDelivery.Handler
 	userHTTP    *userDelivery.Handler
+	adminHTTP   *adminDelivery.Handler
 	userGRPC    *userDelivery.Server
 	authGRPC    *authDelivery.Server
+	grpcAuth    *interceptors.Auth
+	serviceAuth *interceptors.ServiceAuth
+
+	stateStore *stateRepo.Store
 
 	// authUsecase    usecase.AuthUsecase
 	// sessionUsecase usecase.SessionUsecase
@@ -43,9 +62,17 @@ func (a *App) SetupLayers() error {
 
 	stateRedisRepository := stateRepo.NewRedisStateRepo(a.components.redis, a.loggers.Repo)
 	stateInMemoryRepository := stateRepo.NewInMemStateRepo(a.loggers.Repo)
-	stateRepos := []usecase.StateRepo{stateInMemoryRepository, stateRedisRepository}
+	stateStore, err := stateRepo.NewStore(*a.configs.StateStore, a.loggers.Repo,
+		stateInMemoryRepository, stateRedisRepository)
+
+	if err != nil {
+		a.initLogger.ErrorContext(context.Background(), "Error creating state store",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return fmt.Errorf("error creating state store: %w", err)
+	}
+
 	userRepository, err := userRepo.NewUserRepo(a.components.pgsql, a.configs.Keycloak.RealmID,
-		a.configs.PSQL.RequestsPath, a.loggers.Repo)
+		a.configs.Service.UserAttributes, a.configs.PSQL.RequestsPath, a.loggers.Repo)
 
 	if err != nil {
 		a.initLogger.ErrorContext(context.Background(), "Error creating user repository",
@@ -53,43 +80,153 @@ func (a *App) SetupLayers() error {
 		return fmt.Errorf("error creating user repository %s", err.Error())
 	}
 
+	var introspectionCache usecase.IntrospectionCache
+	switch a.configs.IntrospectionCache.Backend {
+	case configs.IntrospectionCacheMemory:
+		introspectionCache = introspectionRepo.NewInMemIntrospectionRepo(a.configs.IntrospectionCache.Size,
+			a.loggers.Repo)
+	case configs.IntrospectionCacheRedis:
+		introspectionCache = introspectionRepo.NewRedisIntrospectionRepo(a.components.redis,
+			a.configs.IntrospectionCache.MaxTTL, a.loggers.Repo)
+	case configs.IntrospectionCacheNone:
+	default:
+		return fmt.Errorf("unknown introspection cache backend %q", a.configs.IntrospectionCache.Backend)
+	}
+
+	var webSessionStore usecase.WebSessionStore
+	switch a.configs.Session.Mode {
+	case configs.SessionModeServer:
+		sealer, serr := seal.NewSealer(a.configs.Session.Key, a.loggers.Repo, a.configs.UpdateChans.SessionKey)
+
+		if serr != nil {
+			a.initLogger.ErrorContext(context.Background(), "Error creating session sealer",
+				slog.String(consts.ErrorLoggerKey, serr.Error()))
+			return fmt.Errorf("error creating session sealer: %w", serr)
+		}
+		webSessionStore = sessionRepo.NewRedisSessionRepo(a.components.redis, sealer, a.loggers.Repo)
+	case configs.SessionModeCookies:
+	default:
+		return fmt.Errorf("unknown session mode %q", a.configs.Session.Mode)
+	}
+
+	cookieCodec := cookies.NewCodec(nil, true)
+	if a.configs.Cookies.Encryption {
+		cookieSealer, serr := seal.NewSealer(a.configs.Cookies.Keys, a.loggers.HTTP, a.configs.UpdateChans.CookieKeys)
+
+		if serr != nil {
+			a.initLogger.ErrorContext(context.Background(), "Error creating cookie sealer",
+				slog.String(consts.ErrorLoggerKey, serr.Error()))
+			return fmt.Errorf("error creating cookie sealer: %w", serr)
+		}
+		cookieCodec = cookies.NewCodec(cookieSealer, a.configs.Cookies.AcceptPlaintext)
+	}
+
+	var refreshRepository usecase.RefreshTokenRepo
+	if a.configs.Keycloak.RefreshReuseDetection {
+		refreshRepository = refreshRepo.NewRedisRefreshRepo(a.components.redis, a.loggers.Repo)
+	}
+
+	var exchangeRepository usecase.TokenExchangeCache
+	if a.configs.TokenExchange.Enabled {
+		exchangeRepository = exchangeRepo.NewInMemExchangeRepo(a.configs.TokenExchange.CacheSize, a.loggers.Repo)
+	}
+
 	/************************************************/
 	/*                USECASES INIT                 */
 	/************************************************/
 
-	stateUsecase := usecase.NewAuthUsecase(a.configs.Service.AuthTimeout, stateRepos,
-		*a.configs.Keycloak, a.components.keycloak, a.loggers.Service,
+	tokenVerifier := jwt.NewVerifier(jwt.VerifierConfig{
+		CertsURL:          a.configs.Keycloak.InterRealmAddress + a.configs.Keycloak.CertsEndpoint,
+		ClientID:          a.configs.Keycloak.ClientID,
+		Issuer:            a.configs.Keycloak.Issuer,
+		Audiences:         a.configs.Keycloak.Audiences,
+		AuthorizedParties: a.configs.Keycloak.AuthorizedParties,
+		ClockSkew:         a.configs.Keycloak.ClockSkew,
+		JWKSCacheTTL:      a.configs.Keycloak.JWKSCacheTTL,
+		JWKSMinRefresh:    a.configs.Keycloak.JWKSMinRefresh,
+		Timeout:           a.configs.Keycloak.TokenTimeout,
+	}, a.loggers.Service)
+
+	stateUsecase := usecase.NewAuthUsecase(a.configs.Service.AuthTimeout, stateStore,
+		*a.configs.Keycloak, a.components.keycloak, a.components.revoke, tokenVerifier, introspectionCache,
+		a.configs.IntrospectionCache.MaxTTL, a.metrics.IntrospectionCacheMetrics, refreshRepository,
+		a.metrics.SecurityMetrics, *a.configs.TokenExchange, exchangeRepository, a.loggers.Service,
 		a.configs.UpdateChans.KCClientSecret)
-	userUsecase := usecase.NewUserUsecase(userRepository, a.loggers.Service)
-	sessionUsecase := usecase.NewSessionUsecase(a.components.keycloak2, a.components.keycloak2d, a.loggers.Service)
+	webSessionUsecase := usecase.NewWebSessionUsecase(webSessionStore, stateUsecase, a.configs.Session.IDLength,
+		a.configs.Session.TTL, a.loggers.Service)
+	userUsecase := usecase.NewUserUsecase(userRepository, *a.configs.Service, a.loggers.Service)
+	sessionUsecase := usecase.NewSessionUsecase(a.components.keycloak2, a.components.keycloak2d,
+		introspectionCache, a.configs.Session.ParseUserAgent, a.loggers.Service)
 
 	/************************************************/
 	/*                MIDDLEWARES INIT              */
 	/************************************************/
 
-	authMW := middlewares.NewAuthMW(stateUsecase, a.loggers.HTTP)
+	authMW := middlewares.NewAuthMW(stateUsecase, webSessionUsecase, a.configs.Session.Mode, cookieCodec,
+		a.loggers.HTTP)
 
 	/************************************************/
 	/*              REST HANDLERS INIT              */
 	/************************************************/
 
 	authHandler := authDelivery.NewAuthHandler(a.configs.Service.AllowedRedirect, a.configs.Service.AllowedRedirect,
-		stateUsecase, a.loggers.HTTP)
-	userHandler := userDelivery.NewUserHandler(userUsecase, a.loggers.HTTP, authMW.AuthMiddleware)
-	sessionHandler := sessionDelivery.NewSessionHandler(sessionUsecase, a.loggers.HTTP, authMW.AuthMiddleware)
+		stateUsecase, webSessionUsecase, a.configs.Session.Mode, cookieCodec, a.metrics.SecurityMetrics, a.loggers.HTTP,
+		authMW.AuthMiddleware)
+	userHandler := userDelivery.NewUserHandler(userUsecase, a.loggers.HTTP, authMW.AuthMiddleware,
+		authMW.Require(a.configs.Authz.Users))
+	sessionHandler := sessionDelivery.NewSessionHandler(sessionUsecase, webSessionUsecase, a.configs.Session.Mode,
+		a.loggers.HTTP, authMW.AuthMiddleware, authMW.Require(a.configs.Authz.Sessions))
 	healthcheckHandler := healthDelivery.NewHealthCheckHandler(a.health.Redis, a.health.Postgres,
 		a.health.Keycloak, a.health.Vault, a.loggers.HTTP)
 
+	healthcheckGRPC := healthDelivery.NewGRPCHealth(a.health.Redis, a.health.Postgres, a.health.Keycloak,
+		a.health.Vault, authProto.AuthService_ServiceDesc.ServiceName, userProto.UserService_ServiceDesc.ServiceName,
+		a.configs.Service.GRPCHealthInterval, a.loggers.GRPC)
+
+	var adminHandler *adminDelivery.Handler
+	if a.configs.Admin.Enabled {
+		serviceAccount := kcadmin.NewServiceAccount(a.components.keycloak, a.configs.Admin.ClientID,
+			a.configs.Admin.ClientSecret, a.configs.Admin.TokenRefreshSkew, a.loggers.Service)
+		go serviceAccount.MonitorVault(a.configs.UpdateChans.AdminSecret)
+
+		adminUsecase := usecase.NewAdminUsecase(kcadmin.NewClient(a.components.admin, serviceAccount,
+			a.loggers.Service), introspectionCache, a.loggers.Service)
+		adminHandler = adminDelivery.NewAdminHandler(adminUsecase, a.loggers.HTTP, authMW.AuthMiddleware,
+			authMW.RequireRealmRole(a.configs.Admin.RealmRole))
+	}
+
 	userServer := userDelivery.NewUserServer(userUsecase, a.loggers.GRPC)
-	authServer := authDelivery.NewUserServer(stateUsecase, a.loggers.GRPC)
+	serviceAuth := interceptors.NewServiceAuth(a.configs.ServiceToken.CallerSecret, a.loggers.GRPC,
+		authProto.AuthService_GetServiceToken_FullMethodName, authProto.AuthService_ExchangeToken_FullMethodName,
+		authProto.AuthService_Revoke_FullMethodName, userProto.UserService_BatchGetUsers_FullMethodName,
+		userProto.UserService_SearchUsers_FullMethodName)
+	go serviceAuth.MonitorVault(a.configs.UpdateChans.CallerSecret)
+
+	// выключенный брокер передаётся как nil без типа, иначе сервер не поймёт, что его нет
+	var authServer *authDelivery.Server
+	if a.configs.ServiceToken.Enabled {
+		serviceTokenUsecase := usecase.NewServiceTokenUsecase(a.components.keycloak, a.configs.ServiceToken.ClientID,
+			a.configs.ServiceToken.ClientSecret, a.configs.ServiceToken.Audiences, a.configs.ServiceToken.RefreshSkew,
+			a.loggers.Service, a.configs.UpdateChans.BrokerSecret)
+		authServer = authDelivery.NewUserServer(stateUsecase, webSessionUsecase, a.configs.Session.Mode,
+			serviceTokenUsecase, a.loggers.GRPC)
+	} else {
+		authServer = authDelivery.NewUserServer(stateUsecase, webSessionUsecase, a.configs.Session.Mode, nil,
+			a.loggers.GRPC)
+	}
 
 	a.layers = &Layers{
 		authHTTP:    authHandler,
 		userHTTP:    userHandler,
 		sessionHTTP: sessionHandler,
+		adminHTTP:   adminHandler,
 		userGRPC:    userServer,
 		authGRPC:    authServer,
+		grpcAuth:    interceptors.NewAuth(stateUsecase, a.loggers.GRPC),
+		serviceAuth: serviceAuth,
 		hcHTTP:      healthcheckHandler,
+		hcGRPC:      healthcheckGRPC,
+		stateStore:  stateStore,
 	}
 	return nil
 }
@@ -11,6 +11,12 @@ type Metrics struct {
 	TokenGetMetrics      *metrics.HTTPRequestMetrics
 	SessionGetMetrics    *metrics.HTTPRequestMetrics
 	SessionDeleteMetrics *metrics.HTTPRequestMetrics
+	TokenRevokeMetrics   *metrics.HTTPRequestMetrics
+	AdminMetrics         *metrics.HTTPRequestMetrics
+
+	IntrospectionCacheMetrics *metrics.CacheMetrics
+	SecurityMetrics           *metrics.SecurityMetrics
+	GRPCServerMetrics         *metrics.GRPCServerMetrics
 
 	Reg *prometheus.Registry
 }
@@ -26,11 +32,23 @@ func (a *App) SetupMetrics() {
 	tokenRequestMetrics := metrics.NewHTTPRequestMetrics(reg, "keycloak_token_post")
 	sessionGetMetrics := metrics.NewHTTPRequestMetrics(reg, "keycloak_session_get")
 	sessionDeleteMetrics := metrics.NewHTTPRequestMetrics(reg, "keycloak_session_delete")
+	tokenRevokeMetrics := metrics.NewHTTPRequestMetrics(reg, "keycloak_token_revoke")
+	adminMetrics := metrics.NewHTTPRequestMetrics(reg, "keycloak_admin")
+	introspectionCacheMetrics := metrics.NewCacheMetrics(reg, "keycloak_introspection")
+	securityMetrics := metrics.NewSecurityMetrics(reg)
+	grpcServerMetrics := metrics.NewGRPCServerMetrics(reg)
 
 	a.metrics = &Metrics{
 		TokenGetMetrics:      tokenRequestMetrics,
 		SessionGetMetrics:    sessionGetMetrics,
 		SessionDeleteMetrics: sessionDeleteMetrics,
-		Reg:                  reg,
+		TokenRevokeMetrics:   tokenRevokeMetrics,
+		AdminMetrics:         adminMetrics,
+
+		IntrospectionCacheMetrics: introspectionCacheMetrics,
+		SecurityMetrics:           securityMetrics,
+		GRPCServerMetrics:         grpcServerMetrics,
+
+		Reg: reg,
 	}
 }
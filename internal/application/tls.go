@@ -0,0 +1,74 @@
+package application
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/dnonakolesax/noted-auth/internal/certs"
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+)
+
+func (a *App) setupCerts() (*certs.Store, error) {
+	cfg := a.configs.TLS
+
+	switch cfg.Source {
+	case configs.TLSSourceFile:
+		if cfg.GRPCMutual && cfg.ClientCAFile == "" {
+			return nil, errors.New("tls.grpc.mtls requires tls.client-ca-file")
+		}
+
+		return certs.NewFileStore(cfg.CertFile, cfg.KeyFile, cfg.ClientCAFile, a.loggers.Infra)
+	case configs.TLSSourceVault:
+		if cfg.GRPCMutual && cfg.ClientCA == "" {
+			return nil, errors.New("tls.grpc.mtls requires secret/tls:client-ca in vault")
+		}
+
+		store, err := certs.NewStore([]byte(cfg.Cert), []byte(cfg.Key), []byte(cfg.ClientCA), a.loggers.Infra)
+
+		if err != nil {
+			return nil, err
+		}
+
+		go store.MonitorVault(cfg.Cert, cfg.Key, cfg.ClientCA, a.configs.UpdateChans.TLSCert,
+			a.configs.UpdateChans.TLSKey, a.configs.UpdateChans.TLSClientCA)
+
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown tls source %q", cfg.Source)
+	}
+}
+
+// watchCerts перечитывает файлы сертификатов; для vault обновления приходят сами.
+func (a *App) watchCerts(ctx context.Context) {
+	if a.components.certs == nil || a.configs.TLS.Source != configs.TLSSourceFile {
+		return
+	}
+
+	a.components.certs.WatchFiles(ctx, a.configs.TLS.CertFile, a.configs.TLS.KeyFile, a.configs.TLS.ClientCAFile,
+		a.configs.TLS.ReloadInterval)
+}
+
+// grpcTLSConfig возвращает nil, если TLS для gRPC выключен.
+func (a *App) grpcTLSConfig() *tls.Config {
+	if !a.configs.TLS.GRPCEnabled {
+		return nil
+	}
+
+	if a.configs.TLS.GRPCMutual {
+		return a.components.certs.MutualConfig(a.configs.TLS.GRPCAllowedSANs)
+	}
+
+	return a.components.certs.ServerConfig()
+}
+
+// wrapHTTPListener оборачивает листенер HTTP сервера в TLS, если он включён.
+func (a *App) wrapHTTPListener(listener net.Listener) net.Listener {
+	if !a.configs.TLS.HTTPEnabled {
+		return listener
+	}
+
+	return tls.NewListener(listener, a.components.certs.ServerConfig())
+}
@@ -0,0 +1,282 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+)
+
+// Store держит текущий серверный сертификат и пул client CA. Листенеры берут их на каждом
+// рукопожатии, поэтому замена сертификата не требует перезапуска серверов.
+type Store struct {
+	cert   atomic.Pointer[tls.Certificate]
+	pool   atomic.Pointer[x509.CertPool]
+	logger *slog.Logger
+}
+
+func NewStore(certPEM []byte, keyPEM []byte, caPEM []byte, logger *slog.Logger) (*Store, error) {
+	s := &Store{logger: logger}
+
+	err := s.Reload(certPEM, keyPEM, caPEM)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Reload заменяет сертификат и, если caPEM не пуст, пул client CA. При ошибке остаются старые.
+func (s *Store) Reload(certPEM []byte, keyPEM []byte, caPEM []byte) error {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+
+	if err != nil {
+		return fmt.Errorf("failed to parse tls key pair: %w", err)
+	}
+
+	var pool *x509.CertPool
+
+	if len(caPEM) > 0 {
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return errors.New("failed to parse client ca: no certificates found")
+		}
+	}
+
+	s.cert.Store(&cert)
+	if pool != nil {
+		s.pool.Store(pool)
+	}
+
+	return nil
+}
+
+// readFiles читает PEM; caFile может быть пустым.
+func readFiles(certFile string, keyFile string, caFile string) ([]byte, []byte, []byte, error) {
+	certPEM, err := os.ReadFile(certFile)
+
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read tls cert: %w", err)
+	}
+
+	keyPEM, err := os.ReadFile(keyFile)
+
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read tls key: %w", err)
+	}
+
+	if caFile == "" {
+		return certPEM, keyPEM, nil, nil
+	}
+
+	caPEM, err := os.ReadFile(caFile)
+
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read client ca: %w", err)
+	}
+
+	return certPEM, keyPEM, caPEM, nil
+}
+
+// NewFileStore загружает сертификаты из файлов.
+func NewFileStore(certFile string, keyFile string, caFile string, logger *slog.Logger) (*Store, error) {
+	certPEM, keyPEM, caPEM, err := readFiles(certFile, keyFile, caFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStore(certPEM, keyPEM, caPEM, logger)
+}
+
+// WatchFiles раз в interval проверяет mtime файлов и перечитывает их при изменении.
+func (s *Store) WatchFiles(ctx context.Context, certFile string, keyFile string, caFile string,
+	interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := modTimes(certFile, keyFile, caFile)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := modTimes(certFile, keyFile, caFile)
+			if slices.Equal(current, last) {
+				continue
+			}
+
+			certPEM, keyPEM, caPEM, err := readFiles(certFile, keyFile, caFile)
+			if err == nil {
+				err = s.Reload(certPEM, keyPEM, caPEM)
+			}
+
+			if err != nil {
+				// cert и key могут обновляться не одновременно — попробуем на следующем тике
+				s.logger.Warn("Failed to reload tls certificates, keeping the old ones",
+					slog.String(consts.ErrorLoggerKey, err.Error()))
+				continue
+			}
+
+			last = current
+			s.logger.Info("TLS certificates reloaded from files")
+		}
+	}
+}
+
+func modTimes(files ...string) []time.Time {
+	times := make([]time.Time, 0, len(files))
+
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			times = append(times, time.Time{})
+			continue
+		}
+
+		times = append(times, info.ModTime())
+	}
+
+	return times
+}
+
+// MonitorVault применяет обновления PEM из vault. Сертификат и ключ приходят отдельными событиями,
+// поэтому пара применяется, только когда они сходятся; до этого работает старая.
+func (s *Store) MonitorVault(certPEM string, keyPEM string, caPEM string, certChan chan string,
+	keyChan chan string, caChan chan string) {
+	for {
+		select {
+		case value, ok := <-certChan:
+			if !ok {
+				return
+			}
+			certPEM = value
+		case value, ok := <-keyChan:
+			if !ok {
+				return
+			}
+			keyPEM = value
+		case value, ok := <-caChan:
+			if !ok {
+				return
+			}
+			caPEM = value
+		}
+
+		err := s.Reload([]byte(certPEM), []byte(keyPEM), []byte(caPEM))
+
+		if err != nil {
+			s.logger.Warn("Failed to apply tls certificates from vault, keeping the old ones",
+				slog.String(consts.ErrorLoggerKey, err.Error()))
+			continue
+		}
+
+		s.logger.Info("TLS certificates reloaded from vault")
+	}
+}
+
+func (s *Store) getCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return s.cert.Load(), nil
+}
+
+// ServerConfig — TLS без проверки клиента.
+func (s *Store) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: s.getCertificate,
+	}
+}
+
+// MutualConfig — mTLS: клиент обязан предъявить сертификат от client CA, а если allowedSANs
+// не пуст — ещё и с одним из перечисленных SAN. Цепочка проверяется вручную, чтобы пул CA
+// тоже можно было менять на лету.
+func (s *Store) MutualConfig(allowedSANs []string) *tls.Config {
+	cfg := s.ServerConfig()
+	cfg.ClientAuth = tls.RequireAnyClientCert
+	cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		return s.verifyClient(rawCerts, allowedSANs)
+	}
+
+	return cfg
+}
+
+func (s *Store) verifyClient(rawCerts [][]byte, allowedSANs []string) error {
+	pool := s.pool.Load()
+
+	if pool == nil {
+		return fmt.Errorf("%w: client ca is not configured", errorvals.ErrClientCertRejected)
+	}
+
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("%w: no client certificate", errorvals.ErrClientCertRejected)
+	}
+
+	leaf, err := x509.ParseCertificate(rawCerts[0])
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", errorvals.ErrClientCertRejected, err)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, raw := range rawCerts[1:] {
+		cert, perr := x509.ParseCertificate(raw)
+		if perr != nil {
+			return fmt.Errorf("%w: %w", errorvals.ErrClientCertRejected, perr)
+		}
+		intermediates.AddCert(cert)
+	}
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", errorvals.ErrClientCertRejected, err)
+	}
+
+	if len(allowedSANs) == 0 {
+		return nil
+	}
+
+	for _, san := range subjectAltNames(leaf) {
+		if slices.Contains(allowedSANs, san) {
+			return nil
+		}
+	}
+
+	s.logger.Warn("Client certificate SAN is not allowed", slog.Any("sans", subjectAltNames(leaf)))
+
+	return fmt.Errorf("%w: san is not allowed", errorvals.ErrClientCertRejected)
+}
+
+func subjectAltNames(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.URIs)+len(cert.IPAddresses))
+	sans = append(sans, cert.DNSNames...)
+
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	return sans
+}
@@ -0,0 +1,126 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue выпускает сертификат и возвращает его DER и PEM пары cert/key.
+func (ca *testCA) issue(t *testing.T, dnsName string, uri string) ([]byte, []byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{dnsName},
+	}
+	if uri != "" {
+		parsed, perr := url.Parse(uri)
+		require.NoError(t, perr)
+		tmpl.URIs = []*url.URL{parsed}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return der, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+}
+
+func TestStore_Reload_KeepsOldOnError(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	_, certPEM, keyPEM := ca.issue(t, "auth.noted", "")
+
+	s, err := NewStore(certPEM, keyPEM, ca.pem, testLogger())
+	require.NoError(t, err)
+	before := s.cert.Load()
+
+	// ключ от другого сертификата — как если бы из vault пришёл только новый cert
+	_, otherCert, _ := ca.issue(t, "auth.noted", "")
+	require.Error(t, s.Reload(otherCert, keyPEM, nil))
+	require.Same(t, before, s.cert.Load())
+
+	_, newCert, newKey := ca.issue(t, "auth.noted", "")
+	require.NoError(t, s.Reload(newCert, newKey, nil))
+	require.NotSame(t, before, s.cert.Load())
+	// пул client CA без caPEM не сбрасывается
+	require.NotNil(t, s.pool.Load())
+}
+
+func TestStore_VerifyClient(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	_, certPEM, keyPEM := ca.issue(t, "auth.noted", "")
+	s, err := NewStore(certPEM, keyPEM, ca.pem, testLogger())
+	require.NoError(t, err)
+
+	notes, _, _ := ca.issue(t, "notes.noted", "spiffe://noted/notes")
+	stranger, _, _ := ca.issue(t, "stranger.example", "")
+	foreign, _, _ := newTestCA(t).issue(t, "notes.noted", "")
+
+	require.NoError(t, s.verifyClient([][]byte{notes}, nil))
+	require.NoError(t, s.verifyClient([][]byte{notes}, []string{"spiffe://noted/notes"}))
+	require.NoError(t, s.verifyClient([][]byte{notes}, []string{"notes.noted"}))
+	require.ErrorIs(t, s.verifyClient([][]byte{stranger}, []string{"notes.noted"}), errorvals.ErrClientCertRejected)
+	require.ErrorIs(t, s.verifyClient([][]byte{foreign}, nil), errorvals.ErrClientCertRejected)
+	require.ErrorIs(t, s.verifyClient(nil, nil), errorvals.ErrClientCertRejected)
+}
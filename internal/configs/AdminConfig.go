@@ -0,0 +1,56 @@
+package configs
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dnonakolesax/viper"
+)
+
+const (
+	adminEnabledKey          = "admin.enabled"
+	adminAddressKey          = "admin.address"
+	adminClientIDKey         = "admin.client-id"
+	adminClientIDDefault     = "noted-auth-admin"
+	adminClientSecretKey     = "secret/keycloak-admin:clientsecret"
+	adminRealmRoleKey        = "admin.realm-role"
+	adminRealmRoleDefault    = "noted-support"
+	adminTokenRefreshSkewKey = "admin.token-refresh-skew"
+	adminTokenRefreshSkewDef = 30 * time.Second
+)
+
+type AdminConfig struct {
+	Enabled bool
+	// Address — admin API реалма: http://keycloak:8080/admin/realms/noted. По умолчанию выводится из realm.inter-url.
+	Address string
+	// ClientID и ClientSecret — клиент с service account и ролями realm-management.
+	ClientID     string
+	ClientSecret string
+	// RealmRole — роль в realm_access, без которой ручки /admin отвечают 403.
+	RealmRole string
+	// TokenRefreshSkew — за сколько до истечения токен service account запрашивается заново.
+	TokenRefreshSkew time.Duration
+}
+
+func (ac *AdminConfig) SetDefaults(v *viper.Viper) {
+	v.SetDefault(adminEnabledKey, false)
+	v.SetDefault(adminAddressKey, "")
+	v.SetDefault(adminClientIDKey, adminClientIDDefault)
+	v.SetDefault(adminClientSecretKey, "")
+	v.SetDefault(adminRealmRoleKey, adminRealmRoleDefault)
+	v.SetDefault(adminTokenRefreshSkewKey, adminTokenRefreshSkewDef)
+}
+
+func (ac *AdminConfig) Load(v *viper.Viper) {
+	ac.Enabled = v.GetBool(adminEnabledKey)
+	ac.Address = v.GetString(adminAddressKey)
+	if ac.Address == "" {
+		// .../realms/noted/protocol/openid-connect -> .../admin/realms/noted
+		realm := strings.TrimSuffix(v.GetString(realmInterAddressKey), oidcPathSuffix)
+		ac.Address = strings.Replace(realm, "/realms/", "/admin/realms/", 1)
+	}
+	ac.ClientID = v.GetString(adminClientIDKey)
+	ac.ClientSecret = v.GetString(adminClientSecretKey)
+	ac.RealmRole = v.GetString(adminRealmRoleKey)
+	ac.TokenRefreshSkew = v.GetDuration(adminTokenRefreshSkewKey)
+}
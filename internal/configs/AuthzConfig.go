@@ -0,0 +1,59 @@
+package configs
+
+import (
+	"strings"
+
+	"github.com/dnonakolesax/viper"
+)
+
+const (
+	authzUsersScopeKey         = "authz.users.scope"
+	authzUsersRealmRoleKey     = "authz.users.realm-role"
+	authzUsersClientRoleKey    = "authz.users.client-role"
+	authzSessionsScopeKey      = "authz.sessions.scope"
+	authzSessionsRealmRoleKey  = "authz.sessions.realm-role"
+	authzSessionsClientRoleKey = "authz.sessions.client-role"
+	authzRequirementDefault    = ""
+	authzClientRoleSeparator   = ":"
+)
+
+// AccessRule — требования к access token. Пустое поле не проверяется.
+type AccessRule struct {
+	Scope     string
+	RealmRole string
+	// Client и ClientRole задаются в конфиге одной строкой "client:role".
+	Client     string
+	ClientRole string
+}
+
+type AuthzConfig struct {
+	Users    AccessRule
+	Sessions AccessRule
+}
+
+func (ac *AuthzConfig) SetDefaults(v *viper.Viper) {
+	v.SetDefault(authzUsersScopeKey, authzRequirementDefault)
+	v.SetDefault(authzUsersRealmRoleKey, authzRequirementDefault)
+	v.SetDefault(authzUsersClientRoleKey, authzRequirementDefault)
+	v.SetDefault(authzSessionsScopeKey, authzRequirementDefault)
+	v.SetDefault(authzSessionsRealmRoleKey, authzRequirementDefault)
+	v.SetDefault(authzSessionsClientRoleKey, authzRequirementDefault)
+}
+
+func (ac *AuthzConfig) Load(v *viper.Viper) {
+	ac.Users = loadAccessRule(v, authzUsersScopeKey, authzUsersRealmRoleKey, authzUsersClientRoleKey)
+	ac.Sessions = loadAccessRule(v, authzSessionsScopeKey, authzSessionsRealmRoleKey, authzSessionsClientRoleKey)
+}
+
+func loadAccessRule(v *viper.Viper, scopeKey string, realmRoleKey string, clientRoleKey string) AccessRule {
+	rule := AccessRule{
+		Scope:     v.GetString(scopeKey),
+		RealmRole: v.GetString(realmRoleKey),
+	}
+
+	if client, role, found := strings.Cut(v.GetString(clientRoleKey), authzClientRoleSeparator); found {
+		rule.Client, rule.ClientRole = client, role
+	}
+
+	return rule
+}
@@ -0,0 +1,40 @@
+package configs
+
+import (
+	"time"
+
+	"github.com/dnonakolesax/viper"
+)
+
+const (
+	IntrospectionCacheNone   = "none"
+	IntrospectionCacheMemory = "memory"
+	IntrospectionCacheRedis  = "redis"
+)
+
+const (
+	introspectionCacheBackendKey     = "introspection-cache.backend"
+	introspectionCacheBackendDefault = IntrospectionCacheMemory
+	introspectionCacheMaxTTLKey      = "introspection-cache.max-ttl"
+	introspectionCacheMaxTTLDefault  = time.Minute
+	introspectionCacheSizeKey        = "introspection-cache.size"
+	introspectionCacheSizeDefault    = 10000
+)
+
+type IntrospectionCacheConfig struct {
+	Backend string
+	MaxTTL  time.Duration
+	Size    int
+}
+
+func (ic *IntrospectionCacheConfig) SetDefaults(v *viper.Viper) {
+	v.SetDefault(introspectionCacheBackendKey, introspectionCacheBackendDefault)
+	v.SetDefault(introspectionCacheMaxTTLKey, introspectionCacheMaxTTLDefault)
+	v.SetDefault(introspectionCacheSizeKey, introspectionCacheSizeDefault)
+}
+
+func (ic *IntrospectionCacheConfig) Load(v *viper.Viper) {
+	ic.Backend = v.GetString(introspectionCacheBackendKey)
+	ic.MaxTTL = v.GetDuration(introspectionCacheMaxTTLKey)
+	ic.Size = v.GetInt(introspectionCacheSizeKey)
+}
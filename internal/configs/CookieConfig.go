@@ -0,0 +1,35 @@
+package configs
+
+import (
+	"github.com/dnonakolesax/viper"
+)
+
+const (
+	cookiesEncryptionKey          = "cookies.encryption"
+	cookiesEncryptionDefault      = false
+	cookiesAcceptPlaintextKey     = "cookies.accept-plaintext"
+	cookiesAcceptPlaintextDefault = true
+	cookiesKeysKey                = "secret/cookies:keys"
+	cookiesKeysDefault            = ""
+)
+
+type CookieConfig struct {
+	// Encryption — шифровать cookie с токенами (режим session.mode: cookies).
+	Encryption bool
+	// AcceptPlaintext — принимать нешифрованные cookie, выданные до включения шифрования.
+	AcceptPlaintext bool
+	// Keys — набор ключей "kid1:key1,kid2:key2", шифрует первый.
+	Keys string
+}
+
+func (cc *CookieConfig) SetDefaults(v *viper.Viper) {
+	v.SetDefault(cookiesEncryptionKey, cookiesEncryptionDefault)
+	v.SetDefault(cookiesAcceptPlaintextKey, cookiesAcceptPlaintextDefault)
+	v.SetDefault(cookiesKeysKey, cookiesKeysDefault)
+}
+
+func (cc *CookieConfig) Load(v *viper.Viper) {
+	cc.Encryption = v.GetBool(cookiesEncryptionKey)
+	cc.AcceptPlaintext = v.GetBool(cookiesAcceptPlaintextKey)
+	cc.Keys = v.GetString(cookiesKeysKey)
+}
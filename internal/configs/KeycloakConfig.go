@@ -1,6 +1,7 @@
 package configs
 
 import (
+	"strings"
 	"time"
 
 	"github.com/dnonakolesax/viper"
@@ -17,6 +18,8 @@ const (
 	realmDefaultStateLength        = 32
 	realmCodeVerifierLengthKey     = "realm.code-verifier-length"
 	realmCodeVerifierLengthDefault = 128
+	realmNonceLengthKey            = "realm.nonce-length"
+	realmNonceLengthDefault        = 32
 	realmTokenTimeoutKey           = "realm.te-timeout"
 	realmTokenTimeoutDefault       = 10 * time.Second
 	realmIDKey                     = "realm.id"
@@ -27,8 +30,34 @@ const (
 	realmTokenEndpointDefault      = "/token"
 	realmLogoutEndpointKey         = "realm.logout-endpoint"
 	realmLogoutEndpointDefault     = "/logout"
+	realmRevokeEndpointKey         = "realm.revoke-endpoint"
+	realmRevokeEndpointDefault     = "/revoke"
 	realmSessionAddressKey         = "realm.session-address"
 	realmSessionAddressDefault     = "http://keycloak-ru:8080/realms/noted/account/sessions/devices/"
+	realmCertsEndpointKey          = "realm.certs-endpoint"
+	realmCertsEndpointDefault      = "/certs"
+	realmIssuerKey                 = "realm.issuer"
+	realmAudiencesKey              = "realm.audiences"
+	realmAuthorizedPartiesKey      = "realm.authorized-parties"
+	realmTokenVerificationKey      = "realm.token-verification"
+	realmIntrospectionFallbackKey  = "realm.introspection-fallback"
+	realmClockSkewKey              = "realm.clock-skew"
+	realmClockSkewDefault          = 30 * time.Second
+	realmJWKSCacheTTLKey           = "realm.jwks-cache-ttl"
+	realmJWKSCacheTTLDefault       = time.Hour
+	realmJWKSMinRefreshKey         = "realm.jwks-min-refresh"
+	realmJWKSMinRefreshDefault     = 10 * time.Second
+	realmRefreshReuseDetectionKey  = "realm.refresh-reuse-detection"
+	realmRefreshReuseGraceKey      = "realm.refresh-reuse-grace"
+	realmRefreshReuseGraceDefault  = 10 * time.Second
+)
+
+// oidcPathSuffix отрезается от base-url, чтобы получить issuer реалма по умолчанию.
+const oidcPathSuffix = "/protocol/openid-connect"
+
+const (
+	TokenVerificationLocal         = "local"
+	TokenVerificationIntrospection = "introspection"
 )
 
 type KeycloakConfig struct {
@@ -39,13 +68,28 @@ type KeycloakConfig struct {
 	InterRealmAddress     string
 	StateLength           uint
 	CodeVerifierLength    uint
+	NonceLength           uint
 	TokenTimeout          time.Duration
 	RealmID               string
 	PostLogoutRedirectURI string
 	AuthEndpoint          string
 	TokenEndpoint         string
 	LogoutEndpoint        string
+	RevokeEndpoint        string
 	SessionAddress        string
+	CertsEndpoint         string
+	Issuer                string
+	Audiences             []string
+	AuthorizedParties     []string
+	TokenVerification     string
+	IntrospectionFallback bool
+	ClockSkew             time.Duration
+	JWKSCacheTTL          time.Duration
+	JWKSMinRefresh        time.Duration
+	RefreshReuseDetection bool
+	// RefreshReuseGrace — сколько после обмена refresh token его повтор ещё не считается кражей
+	// (параллельные запросы одного клиента).
+	RefreshReuseGrace time.Duration
 }
 
 func (kc *KeycloakConfig) Load(v *viper.Viper) {
@@ -57,13 +101,28 @@ func (kc *KeycloakConfig) Load(v *viper.Viper) {
 	kc.InterRealmAddress = v.GetString(realmInterAddressKey)
 	kc.StateLength = v.GetUint(realmStateLengthKey)
 	kc.CodeVerifierLength = v.GetUint(realmCodeVerifierLengthKey)
+	kc.NonceLength = v.GetUint(realmNonceLengthKey)
 	kc.TokenTimeout = v.GetDuration(realmTokenTimeoutKey)
 	kc.RealmID = v.GetString(realmIDKey)
 	kc.PostLogoutRedirectURI = v.GetString(realmPostLogoutURIKey)
 	kc.AuthEndpoint = v.GetString(realmAuthEndpointKey)
 	kc.TokenEndpoint = v.GetString(realmTokenEndpointKey)
 	kc.LogoutEndpoint = v.GetString(realmLogoutEndpointKey)
+	kc.RevokeEndpoint = v.GetString(realmRevokeEndpointKey)
 	kc.SessionAddress = v.GetString(realmSessionAddressKey)
+	kc.CertsEndpoint = v.GetString(realmCertsEndpointKey)
+	v.SetDefault(realmIssuerKey, strings.TrimSuffix(kc.RealmAddress, oidcPathSuffix))
+	kc.Issuer = v.GetString(realmIssuerKey)
+	kc.Audiences = v.GetStringSlice(realmAudiencesKey)
+	v.SetDefault(realmAuthorizedPartiesKey, []string{kc.ClientID})
+	kc.AuthorizedParties = v.GetStringSlice(realmAuthorizedPartiesKey)
+	kc.TokenVerification = v.GetString(realmTokenVerificationKey)
+	kc.IntrospectionFallback = v.GetBool(realmIntrospectionFallbackKey)
+	kc.ClockSkew = v.GetDuration(realmClockSkewKey)
+	kc.JWKSCacheTTL = v.GetDuration(realmJWKSCacheTTLKey)
+	kc.JWKSMinRefresh = v.GetDuration(realmJWKSMinRefreshKey)
+	kc.RefreshReuseDetection = v.GetBool(realmRefreshReuseDetectionKey)
+	kc.RefreshReuseGrace = v.GetDuration(realmRefreshReuseGraceKey)
 }
 
 func (kc *KeycloakConfig) SetDefaults(v *viper.Viper) {
@@ -74,11 +133,24 @@ func (kc *KeycloakConfig) SetDefaults(v *viper.Viper) {
 	v.SetDefault(realmInterAddressKey, nil)
 	v.SetDefault(realmStateLengthKey, realmDefaultStateLength)
 	v.SetDefault(realmCodeVerifierLengthKey, realmCodeVerifierLengthDefault)
+	v.SetDefault(realmNonceLengthKey, realmNonceLengthDefault)
 	v.SetDefault(realmTokenTimeoutKey, realmTokenTimeoutDefault)
 	v.SetDefault(realmIDKey, nil)
 	v.SetDefault(realmPostLogoutURIKey, nil)
 	v.SetDefault(realmAuthEndpointKey, realmAuthEndpointDefault)
 	v.SetDefault(realmTokenEndpointKey, realmTokenEndpointDefault)
 	v.SetDefault(realmLogoutEndpointKey, realmLogoutEndpointDefault)
+	v.SetDefault(realmRevokeEndpointKey, realmRevokeEndpointDefault)
 	v.SetDefault(realmSessionAddressKey, realmSessionAddressDefault)
+	v.SetDefault(realmCertsEndpointKey, realmCertsEndpointDefault)
+	v.SetDefault(realmIssuerKey, nil)
+	v.SetDefault(realmAudiencesKey, []string{})
+	v.SetDefault(realmAuthorizedPartiesKey, nil)
+	v.SetDefault(realmTokenVerificationKey, TokenVerificationLocal)
+	v.SetDefault(realmIntrospectionFallbackKey, false)
+	v.SetDefault(realmClockSkewKey, realmClockSkewDefault)
+	v.SetDefault(realmJWKSCacheTTLKey, realmJWKSCacheTTLDefault)
+	v.SetDefault(realmJWKSMinRefreshKey, realmJWKSMinRefreshDefault)
+	v.SetDefault(realmRefreshReuseDetectionKey, true)
+	v.SetDefault(realmRefreshReuseGraceKey, realmRefreshReuseGraceDefault)
 }
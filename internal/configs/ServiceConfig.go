@@ -7,19 +7,32 @@ import (
 )
 
 const (
-	servicePortKey                = "service.port"
-	servicePortDefault            = 8800
-	serviceAuthTimeoutKey         = "service.auth-timeout"
-	serviceAuthTimeoutDefault     = 5 * time.Minute
-	serviceBasePathKey            = "service.base-path"
-	serviceBasePathDefault        = "/iam"
-	serviceAllowedRedirectKey     = "service.allowed-redirect"
-	serviceMetricsPortKey         = "service.metrics-port"
-	serviceMetricsPortDefault     = 8801
-	serviceGRPCPortKey            = "service.grpc-port"
-	serviceGRPCPortDefault        = 8802
-	serviceMetricsEndpointKey     = "service.metrics-endpoint"
-	serviceMetricsEndpointDefault = "/metrics"
+	servicePortKey                    = "service.port"
+	servicePortDefault                = 8800
+	serviceAuthTimeoutKey             = "service.auth-timeout"
+	serviceAuthTimeoutDefault         = 5 * time.Minute
+	serviceBasePathKey                = "service.base-path"
+	serviceBasePathDefault            = "/iam"
+	serviceAllowedRedirectKey         = "service.allowed-redirect"
+	serviceMetricsPortKey             = "service.metrics-port"
+	serviceMetricsPortDefault         = 8801
+	serviceGRPCPortKey                = "service.grpc-port"
+	serviceGRPCPortDefault            = 8802
+	serviceMetricsEndpointKey         = "service.metrics-endpoint"
+	serviceMetricsEndpointDefault     = "/metrics"
+	serviceGRPCReflectionKey          = "service.grpc-reflection"
+	serviceGRPCReflectionDefault      = false
+	serviceGRPCHealthIntervalKey      = "service.grpc-health-interval"
+	serviceGRPCHealthIntervalDefault  = 5 * time.Second
+	serviceUsersBatchLimitKey         = "service.users-batch-limit"
+	serviceUsersBatchLimitDefault     = 100
+	serviceUsersSearchLimitKey        = "service.users-search-limit"
+	serviceUsersSearchLimitDefault    = 20
+	serviceUsersSearchMaxLimitKey     = "service.users-search-max-limit"
+	serviceUsersSearchMaxLimitDefault = 100
+	serviceUsersSearchMinQueryKey     = "service.users-search-min-query"
+	serviceUsersSearchMinQueryDefault = 2
+	serviceUserAttributesKey          = "service.user-attributes"
 )
 
 const (
@@ -47,6 +60,19 @@ type ServiceConfig struct {
 	MetricsPort     int
 	GRPCPort        int
 	MetricsEndpoint string
+	// GRPCReflection — регистрировать grpc.reflection (для grpcurl).
+	GRPCReflection bool
+	// GRPCHealthInterval — как часто grpc.health.v1 перечитывает флаги живости зависимостей.
+	GRPCHealthInterval time.Duration
+	// UsersBatchLimit — максимум id в одном запросе /users/batch и BatchGetUsers.
+	UsersBatchLimit int
+	// UsersSearchLimit — размер страницы /users/search, если limit не задан; больше UsersSearchMaxLimit не отдаётся.
+	UsersSearchLimit    int
+	UsersSearchMaxLimit int
+	// UsersSearchMinQuery — минимальная длина префикса, чтобы поиском нельзя было перебрать всех пользователей.
+	UsersSearchMinQuery int
+	// UserAttributes — разрешённые к выдаче атрибуты из user_attribute (аватар, локаль, часовой пояс).
+	UserAttributes []string
 }
 
 type LoggerConfig struct {
@@ -67,6 +93,13 @@ func (sc *ServiceConfig) SetDefaults(v *viper.Viper) {
 	v.SetDefault(serviceMetricsPortKey, serviceMetricsPortDefault)
 	v.SetDefault(serviceGRPCPortKey, serviceGRPCPortDefault)
 	v.SetDefault(serviceMetricsEndpointKey, serviceMetricsEndpointDefault)
+	v.SetDefault(serviceGRPCReflectionKey, serviceGRPCReflectionDefault)
+	v.SetDefault(serviceGRPCHealthIntervalKey, serviceGRPCHealthIntervalDefault)
+	v.SetDefault(serviceUsersBatchLimitKey, serviceUsersBatchLimitDefault)
+	v.SetDefault(serviceUsersSearchLimitKey, serviceUsersSearchLimitDefault)
+	v.SetDefault(serviceUsersSearchMaxLimitKey, serviceUsersSearchMaxLimitDefault)
+	v.SetDefault(serviceUsersSearchMinQueryKey, serviceUsersSearchMinQueryDefault)
+	v.SetDefault(serviceUserAttributesKey, []string{"picture", "locale", "zoneinfo"})
 }
 
 func (sc *ServiceConfig) Load(v *viper.Viper) {
@@ -77,6 +110,13 @@ func (sc *ServiceConfig) Load(v *viper.Viper) {
 	sc.MetricsPort = v.GetInt(serviceMetricsPortKey)
 	sc.GRPCPort = v.GetInt(serviceGRPCPortKey)
 	sc.MetricsEndpoint = v.GetString(serviceMetricsEndpointKey)
+	sc.GRPCReflection = v.GetBool(serviceGRPCReflectionKey)
+	sc.GRPCHealthInterval = v.GetDuration(serviceGRPCHealthIntervalKey)
+	sc.UsersBatchLimit = v.GetInt(serviceUsersBatchLimitKey)
+	sc.UsersSearchLimit = v.GetInt(serviceUsersSearchLimitKey)
+	sc.UsersSearchMaxLimit = v.GetInt(serviceUsersSearchMaxLimitKey)
+	sc.UsersSearchMinQuery = v.GetInt(serviceUsersSearchMinQueryKey)
+	sc.UserAttributes = v.GetStringSlice(serviceUserAttributesKey)
 }
 
 func (lc *LoggerConfig) SetDefaults(v *viper.Viper) {
@@ -0,0 +1,50 @@
+package configs
+
+import (
+	"time"
+
+	"github.com/dnonakolesax/viper"
+)
+
+const (
+	serviceTokenEnabledKey      = "service-token.enabled"
+	serviceTokenClientIDKey     = "service-token.client-id"
+	serviceTokenClientIDDefault = "noted-auth-broker"
+	serviceTokenClientSecretKey = "secret/service-token:clientsecret"
+	serviceTokenCallerSecretKey = "secret/service-token:callersecret"
+	serviceTokenAudiencesKey    = "service-token.audiences"
+	serviceTokenRefreshSkewKey  = "service-token.refresh-skew"
+	serviceTokenRefreshSkewDef  = 30 * time.Second
+)
+
+type ServiceTokenConfig struct {
+	Enabled bool
+	// ClientID и ClientSecret — клиент keycloak с service account, от имени которого выдаются токены.
+	ClientID     string
+	ClientSecret string
+	// CallerSecret — общий секрет для вызывающих без mTLS; пустой — пускать только по клиентскому сертификату.
+	CallerSecret string
+	// Audiences — сервис (aud) -> client scope keycloak, который добавляет его в aud: client_credentials
+	// параметр audience не учитывает.
+	Audiences map[string]string
+	// RefreshSkew — за сколько до истечения закэшированный токен перестаёт выдаваться.
+	RefreshSkew time.Duration
+}
+
+func (sc *ServiceTokenConfig) SetDefaults(v *viper.Viper) {
+	v.SetDefault(serviceTokenEnabledKey, false)
+	v.SetDefault(serviceTokenClientIDKey, serviceTokenClientIDDefault)
+	v.SetDefault(serviceTokenClientSecretKey, "")
+	v.SetDefault(serviceTokenCallerSecretKey, "")
+	v.SetDefault(serviceTokenAudiencesKey, map[string]string{})
+	v.SetDefault(serviceTokenRefreshSkewKey, serviceTokenRefreshSkewDef)
+}
+
+func (sc *ServiceTokenConfig) Load(v *viper.Viper) {
+	sc.Enabled = v.GetBool(serviceTokenEnabledKey)
+	sc.ClientID = v.GetString(serviceTokenClientIDKey)
+	sc.ClientSecret = v.GetString(serviceTokenClientSecretKey)
+	sc.CallerSecret = v.GetString(serviceTokenCallerSecretKey)
+	sc.Audiences = v.GetStringMapString(serviceTokenAudiencesKey)
+	sc.RefreshSkew = v.GetDuration(serviceTokenRefreshSkewKey)
+}
@@ -0,0 +1,54 @@
+package configs
+
+import (
+	"time"
+
+	"github.com/dnonakolesax/viper"
+)
+
+const (
+	// SessionModeCookies — токены отдаются браузеру в cookie как есть.
+	SessionModeCookies = "cookies"
+	// SessionModeServer — токены хранятся в redis в зашифрованном виде, браузер получает только id сессии.
+	SessionModeServer = "server"
+)
+
+const (
+	sessionModeKey         = "session.mode"
+	sessionModeDefault     = SessionModeCookies
+	sessionIDLengthKey     = "session.id-length"
+	sessionIDLengthDefault = 32
+	sessionTTLKey          = "session.ttl"
+	sessionTTLDefault      = 24 * time.Hour
+	sessionKeyKey          = "secret/session:key"
+	sessionKeyDefault      = ""
+	sessionParseUAKey      = "session.parse-user-agent"
+	sessionParseUADefault  = true
+)
+
+type SessionConfig struct {
+	Mode     string
+	IDLength uint
+	// TTL — время жизни сессии, если keycloak не вернул refresh_expires_in (например, offline токены).
+	TTL time.Duration
+	// Key — ключ AES-256 в base64 или набор "kid1:key1,kid2:key2" для ротации, нужен только в режиме server.
+	Key string
+	// ParseUserAgent — уточнять браузер, ОС и класс текущего устройства в GET /session по User-Agent запроса.
+	ParseUserAgent bool
+}
+
+func (sc *SessionConfig) SetDefaults(v *viper.Viper) {
+	v.SetDefault(sessionModeKey, sessionModeDefault)
+	v.SetDefault(sessionIDLengthKey, sessionIDLengthDefault)
+	v.SetDefault(sessionTTLKey, sessionTTLDefault)
+	v.SetDefault(sessionKeyKey, sessionKeyDefault)
+	v.SetDefault(sessionParseUAKey, sessionParseUADefault)
+}
+
+func (sc *SessionConfig) Load(v *viper.Viper) {
+	sc.Mode = v.GetString(sessionModeKey)
+	sc.IDLength = v.GetUint(sessionIDLengthKey)
+	sc.TTL = v.GetDuration(sessionTTLKey)
+	sc.Key = v.GetString(sessionKeyKey)
+	sc.ParseUserAgent = v.GetBool(sessionParseUAKey)
+}
@@ -0,0 +1,58 @@
+package configs
+
+import (
+	"time"
+
+	"github.com/dnonakolesax/viper"
+)
+
+const (
+	// StateWriteAllSync — запись во все репозитории в рамках запроса, ошибка любого из них — ошибка записи.
+	StateWriteAllSync = "all-sync"
+	// StateWritePrimaryAsync — синхронная запись в основной репозиторий, в остальные — фоновыми воркерами.
+	StateWritePrimaryAsync = "primary-async"
+	// StateWriteQuorum — параллельная запись во все, успех при ответе большинства.
+	StateWriteQuorum = "quorum"
+
+	// StateReadFirstHit — чтение по порядку до первого найденного значения.
+	StateReadFirstHit = "first-hit"
+	// StateReadPrimaryOnly — чтение только из основного репозитория.
+	StateReadPrimaryOnly = "primary-only"
+)
+
+const (
+	stateStoreWritePolicyKey            = "state-store.write-policy"
+	stateStoreWritePolicyDefault        = StateWritePrimaryAsync
+	stateStoreReadPolicyKey             = "state-store.read-policy"
+	stateStoreReadPolicyDefault         = StateReadFirstHit
+	stateStoreWorkersKey                = "state-store.workers"
+	stateStoreWorkersDefault            = 4
+	stateStoreQueueSizeKey              = "state-store.queue-size"
+	stateStoreQueueSizeDefault          = 1024
+	stateStoreReplicationTimeoutKey     = "state-store.replication-timeout"
+	stateStoreReplicationTimeoutDefault = 5 * time.Second
+)
+
+type StateStoreConfig struct {
+	WritePolicy        string
+	ReadPolicy         string
+	Workers            int
+	QueueSize          int
+	ReplicationTimeout time.Duration
+}
+
+func (sc *StateStoreConfig) SetDefaults(v *viper.Viper) {
+	v.SetDefault(stateStoreWritePolicyKey, stateStoreWritePolicyDefault)
+	v.SetDefault(stateStoreReadPolicyKey, stateStoreReadPolicyDefault)
+	v.SetDefault(stateStoreWorkersKey, stateStoreWorkersDefault)
+	v.SetDefault(stateStoreQueueSizeKey, stateStoreQueueSizeDefault)
+	v.SetDefault(stateStoreReplicationTimeoutKey, stateStoreReplicationTimeoutDefault)
+}
+
+func (sc *StateStoreConfig) Load(v *viper.Viper) {
+	sc.WritePolicy = v.GetString(stateStoreWritePolicyKey)
+	sc.ReadPolicy = v.GetString(stateStoreReadPolicyKey)
+	sc.Workers = v.GetInt(stateStoreWorkersKey)
+	sc.QueueSize = v.GetInt(stateStoreQueueSizeKey)
+	sc.ReplicationTimeout = v.GetDuration(stateStoreReplicationTimeoutKey)
+}
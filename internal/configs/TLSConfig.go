@@ -0,0 +1,95 @@
+package configs
+
+import (
+	"time"
+
+	"github.com/dnonakolesax/viper"
+)
+
+const (
+	// TLSSourceFile — сертификаты читаются из файлов и перечитываются при изменении mtime.
+	TLSSourceFile = "file"
+	// TLSSourceVault — сертификаты в PEM лежат в vault (secret/tls:cert, secret/tls:key, secret/tls:client-ca).
+	TLSSourceVault = "vault"
+)
+
+const (
+	tlsSourceKey             = "tls.source"
+	tlsSourceDefault         = TLSSourceFile
+	tlsHTTPEnabledKey        = "tls.http.enabled"
+	tlsHTTPEnabledDefault    = false
+	tlsGRPCEnabledKey        = "tls.grpc.enabled"
+	tlsGRPCEnabledDefault    = false
+	tlsGRPCMutualKey         = "tls.grpc.mtls"
+	tlsGRPCMutualDefault     = false
+	tlsGRPCAllowedSANsKey    = "tls.grpc.allowed-sans"
+	tlsCertFileKey           = "tls.cert-file"
+	tlsCertFileDefault       = ""
+	tlsKeyFileKey            = "tls.key-file"
+	tlsKeyFileDefault        = ""
+	tlsClientCAFileKey       = "tls.client-ca-file"
+	tlsClientCAFileDefault   = ""
+	tlsReloadIntervalKey     = "tls.reload-interval"
+	tlsReloadIntervalDefault = 30 * time.Second
+	tlsVaultCertKey          = "secret/tls:cert"
+	tlsVaultCertDefault      = ""
+	tlsVaultKeyKey           = "secret/tls:key"
+	tlsVaultKeyDefault       = ""
+	tlsVaultClientCAKey      = "secret/tls:client-ca"
+	tlsVaultClientCADefault  = ""
+)
+
+type TLSConfig struct {
+	Source      string
+	HTTPEnabled bool
+	GRPCEnabled bool
+	// GRPCMutual — требовать от клиентов gRPC сертификат, подписанный client CA.
+	GRPCMutual bool
+	// GRPCAllowedSANs — DNS/URI/IP SAN клиентских сертификатов, которым разрешён вызов; пустой — любой от client CA.
+	GRPCAllowedSANs []string
+
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string
+	ReloadInterval time.Duration
+
+	// Cert, Key, ClientCA — PEM из vault (только для source: vault).
+	Cert     string
+	Key      string
+	ClientCA string
+}
+
+// Enabled — нужен ли хотя бы одному из листенеров сертификат.
+func (tc *TLSConfig) Enabled() bool {
+	return tc.HTTPEnabled || tc.GRPCEnabled
+}
+
+func (tc *TLSConfig) SetDefaults(v *viper.Viper) {
+	v.SetDefault(tlsSourceKey, tlsSourceDefault)
+	v.SetDefault(tlsHTTPEnabledKey, tlsHTTPEnabledDefault)
+	v.SetDefault(tlsGRPCEnabledKey, tlsGRPCEnabledDefault)
+	v.SetDefault(tlsGRPCMutualKey, tlsGRPCMutualDefault)
+	v.SetDefault(tlsGRPCAllowedSANsKey, []string{})
+	v.SetDefault(tlsCertFileKey, tlsCertFileDefault)
+	v.SetDefault(tlsKeyFileKey, tlsKeyFileDefault)
+	v.SetDefault(tlsClientCAFileKey, tlsClientCAFileDefault)
+	v.SetDefault(tlsReloadIntervalKey, tlsReloadIntervalDefault)
+	v.SetDefault(tlsVaultCertKey, tlsVaultCertDefault)
+	v.SetDefault(tlsVaultKeyKey, tlsVaultKeyDefault)
+	v.SetDefault(tlsVaultClientCAKey, tlsVaultClientCADefault)
+}
+
+func (tc *TLSConfig) Load(v *viper.Viper) {
+	tc.Source = v.GetString(tlsSourceKey)
+	tc.HTTPEnabled = v.GetBool(tlsHTTPEnabledKey)
+	tc.GRPCEnabled = v.GetBool(tlsGRPCEnabledKey)
+	tc.GRPCMutual = v.GetBool(tlsGRPCMutualKey)
+	tc.GRPCAllowedSANs = v.GetStringSlice(tlsGRPCAllowedSANsKey)
+	tc.CertFile = v.GetString(tlsCertFileKey)
+	tc.KeyFile = v.GetString(tlsKeyFileKey)
+	tc.ClientCAFile = v.GetString(tlsClientCAFileKey)
+	tc.ReloadInterval = v.GetDuration(tlsReloadIntervalKey)
+	tc.Cert = v.GetString(tlsVaultCertKey)
+	tc.Key = v.GetString(tlsVaultKeyKey)
+	tc.ClientCA = v.GetString(tlsVaultClientCAKey)
+}
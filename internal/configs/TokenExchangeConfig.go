@@ -0,0 +1,40 @@
+package configs
+
+import (
+	"time"
+
+	"github.com/dnonakolesax/viper"
+)
+
+const (
+	tokenExchangeEnabledKey         = "token-exchange.enabled"
+	tokenExchangeAudiencesKey       = "token-exchange.audiences"
+	tokenExchangeCacheSizeKey       = "token-exchange.cache-size"
+	tokenExchangeCacheSizeDefault   = 10000
+	tokenExchangeRefreshSkewKey     = "token-exchange.refresh-skew"
+	tokenExchangeRefreshSkewDefault = 10 * time.Second
+)
+
+type TokenExchangeConfig struct {
+	Enabled bool
+	// Audiences — клиенты keycloak, на которых можно обменять токен пользователя.
+	Audiences []string
+	// CacheSize — сколько обменянных токенов держать в памяти (LRU).
+	CacheSize int
+	// RefreshSkew — за сколько до истечения обменянный токен перестаёт отдаваться из кэша.
+	RefreshSkew time.Duration
+}
+
+func (tc *TokenExchangeConfig) SetDefaults(v *viper.Viper) {
+	v.SetDefault(tokenExchangeEnabledKey, false)
+	v.SetDefault(tokenExchangeAudiencesKey, []string{})
+	v.SetDefault(tokenExchangeCacheSizeKey, tokenExchangeCacheSizeDefault)
+	v.SetDefault(tokenExchangeRefreshSkewKey, tokenExchangeRefreshSkewDefault)
+}
+
+func (tc *TokenExchangeConfig) Load(v *viper.Viper) {
+	tc.Enabled = v.GetBool(tokenExchangeEnabledKey)
+	tc.Audiences = v.GetStringSlice(tokenExchangeAudiencesKey)
+	tc.CacheSize = v.GetInt(tokenExchangeCacheSizeKey)
+	tc.RefreshSkew = v.GetDuration(tokenExchangeRefreshSkewKey)
+}
@@ -21,6 +21,16 @@ type Config struct {
 
 	Keycloak *KeycloakConfig
 
+	IntrospectionCache *IntrospectionCacheConfig
+	StateStore         *StateStoreConfig
+	Session            *SessionConfig
+	Cookies            *CookieConfig
+	Authz              *AuthzConfig
+	TLS                *TLSConfig
+	Admin              *AdminConfig
+	ServiceToken       *ServiceTokenConfig
+	TokenExchange      *TokenExchangeConfig
+
 	Service *ServiceConfig
 	Logger  *LoggerConfig
 
@@ -33,12 +43,28 @@ type UpdateChans struct {
 	PSQLCredentials chan string
 	RedisPassword   chan string
 	KCClientSecret  chan string
+	SessionKey      chan string
+	CookieKeys      chan string
+	TLSCert         chan string
+	TLSKey          chan string
+	TLSClientCA     chan string
+	AdminSecret     chan string
+	BrokerSecret    chan string
+	CallerSecret    chan string
 }
 
 func ListenUpdates(updateChan chan viper.KVEntry, hc *atomic.Bool) *UpdateChans {
 	psqlChan := make(chan string)
 	redisChan := make(chan string)
 	kcChan := make(chan string)
+	sessionKeyChan := make(chan string)
+	cookieKeysChan := make(chan string)
+	tlsCertChan := make(chan string)
+	tlsKeyChan := make(chan string)
+	tlsClientCAChan := make(chan string)
+	adminSecretChan := make(chan string)
+	brokerSecretChan := make(chan string)
+	callerSecretChan := make(chan string)
 
 	go func() {
 		for value := range updateChan {
@@ -49,6 +75,22 @@ func ListenUpdates(updateChan chan viper.KVEntry, hc *atomic.Bool) *UpdateChans
 				redisChan <- value.Value
 			case realmClientSecretKey:
 				kcChan <- value.Value
+			case sessionKeyKey:
+				sessionKeyChan <- value.Value
+			case cookiesKeysKey:
+				cookieKeysChan <- value.Value
+			case tlsVaultCertKey:
+				tlsCertChan <- value.Value
+			case tlsVaultKeyKey:
+				tlsKeyChan <- value.Value
+			case tlsVaultClientCAKey:
+				tlsClientCAChan <- value.Value
+			case adminClientSecretKey:
+				adminSecretChan <- value.Value
+			case serviceTokenClientSecretKey:
+				brokerSecretChan <- value.Value
+			case serviceTokenCallerSecretKey:
+				callerSecretChan <- value.Value
 			}
 		}
 		hc.Store(false)
@@ -58,6 +100,14 @@ func ListenUpdates(updateChan chan viper.KVEntry, hc *atomic.Bool) *UpdateChans
 		PSQLCredentials: psqlChan,
 		RedisPassword:   redisChan,
 		KCClientSecret:  kcChan,
+		SessionKey:      sessionKeyChan,
+		CookieKeys:      cookieKeysChan,
+		TLSCert:         tlsCertChan,
+		TLSKey:          tlsKeyChan,
+		TLSClientCA:     tlsClientCAChan,
+		AdminSecret:     adminSecretChan,
+		BrokerSecret:    brokerSecretChan,
+		CallerSecret:    callerSecretChan,
 	}
 }
 
@@ -77,6 +127,15 @@ func SetupConfigs(initLogger *slog.Logger, configsDir string, hc *atomic.Bool) (
 	serverConfig := &HTTPServerConfig{}
 	httpClientConfig := &HTTPClientConfig{}
 	loggerConfig := &LoggerConfig{}
+	introspectionCacheConfig := &IntrospectionCacheConfig{}
+	stateStoreConfig := &StateStoreConfig{}
+	sessionConfig := &SessionConfig{}
+	cookieConfig := &CookieConfig{}
+	authzConfig := &AuthzConfig{}
+	tlsConfig := &TLSConfig{}
+	adminConfig := &AdminConfig{}
+	serviceTokenConfig := &ServiceTokenConfig{}
+	tokenExchangeConfig := &TokenExchangeConfig{}
 
 	vaultConfig := NewVaultConfig()
 	creds := &vault.Credentials{
@@ -93,7 +152,9 @@ func SetupConfigs(initLogger *slog.Logger, configsDir string, hc *atomic.Bool) (
 	hc.Store(true)
 
 	err = Load(configsDir, v, initLogger, vaultClient.Client, vaultClient.UpdateChan, kcConfig, psqlConfig,
-		redisConfig, appConfig, serverConfig, httpClientConfig, loggerConfig)
+		redisConfig, appConfig, serverConfig, httpClientConfig, loggerConfig, introspectionCacheConfig,
+		stateStoreConfig, sessionConfig, cookieConfig, authzConfig, tlsConfig, adminConfig,
+		serviceTokenConfig, tokenExchangeConfig)
 
 	if err != nil {
 		initLogger.ErrorContext(context.Background(), "Error loading config",
@@ -114,5 +175,15 @@ func SetupConfigs(initLogger *slog.Logger, configsDir string, hc *atomic.Bool) (
 		Logger:      loggerConfig,
 		Vault:       vaultConfig,
 		UpdateChans: updates,
+
+		IntrospectionCache: introspectionCacheConfig,
+		StateStore:         stateStoreConfig,
+		Session:            sessionConfig,
+		Cookies:            cookieConfig,
+		Authz:              authzConfig,
+		TLS:                tlsConfig,
+		Admin:              adminConfig,
+		ServiceToken:       serviceTokenConfig,
+		TokenExchange:      tokenExchangeConfig,
 	}, nil
 }
@@ -60,7 +60,28 @@ func Load(path string, v *viper.Viper, logger *slog.Logger, vaultClient *vault.C
 		VersionPeriod: time.Second * 0,
 		AlertChannel:  eventChan,
 	}
-	err = v.AddVault(vaultClient, &vaultWatchConf, postgresRolePath, RedisPasswordKey, realmClientSecretKey)
+	vaultKeys := []string{postgresRolePath, RedisPasswordKey, realmClientSecretKey}
+	// ключи шифрования сессий и cookie читаются из vault, только если соответствующий режим включён:
+	// при постепенном переходе секрета может ещё не быть
+	if v.GetString(sessionModeKey) == SessionModeServer {
+		vaultKeys = append(vaultKeys, sessionKeyKey)
+	}
+	if v.GetBool(cookiesEncryptionKey) {
+		vaultKeys = append(vaultKeys, cookiesKeysKey)
+	}
+	if v.GetString(tlsSourceKey) == TLSSourceVault && (v.GetBool(tlsHTTPEnabledKey) || v.GetBool(tlsGRPCEnabledKey)) {
+		vaultKeys = append(vaultKeys, tlsVaultCertKey, tlsVaultKeyKey)
+		if v.GetBool(tlsGRPCMutualKey) {
+			vaultKeys = append(vaultKeys, tlsVaultClientCAKey)
+		}
+	}
+	if v.GetBool(adminEnabledKey) {
+		vaultKeys = append(vaultKeys, adminClientSecretKey)
+	}
+	if v.GetBool(serviceTokenEnabledKey) {
+		vaultKeys = append(vaultKeys, serviceTokenClientSecretKey, serviceTokenCallerSecretKey)
+	}
+	err = v.AddVault(vaultClient, &vaultWatchConf, vaultKeys...)
 
 	if err != nil {
 		logger.Error("Failed to add vault", slog.String(consts.ErrorLoggerKey, err.Error()))
@@ -6,6 +6,8 @@ const (
 	ATCookieKey  = "NTD-DNAnAT"
 	RTCookieKey  = "NTD-DNART"
 	IDTCookieKey = "NTD-DNALT"
+	// SessionCookieKey — id серверной сессии (режим session.mode: server).
+	SessionCookieKey = "NTD-DNASID"
 )
 
 type ContextKey string
@@ -13,6 +15,8 @@ type ContextKey string
 const (
 	TraceContextKey ContextKey = "trace"
 	TraceLoggerKey  string     = "trace-id"
+	// PrincipalContextKey — principal.Principal аутентифицированного пользователя.
+	PrincipalContextKey ContextKey = "principal"
 )
 
 const (
@@ -28,7 +32,12 @@ const (
 )
 
 const (
-	CtxUserIDKey = "user_id"
+	CtxUserIDKey      = "user_id"
+	CtxAccessTokenKey = "access_token"
+	// CtxTokenClaimsKey — model.IntrospectDTO проверенного access token.
+	CtxTokenClaimsKey = "token_claims"
+	// CtxPrincipalKey — principal.Principal, переносится в context.Context через principal.Attach.
+	CtxPrincipalKey = "principal"
 )
 
 const (
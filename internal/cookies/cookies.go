@@ -1,16 +1,81 @@
 package cookies
 
 import (
+	"errors"
+
 	"github.com/valyala/fasthttp"
 
 	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
 	"github.com/dnonakolesax/noted-auth/internal/model"
 )
 
-func SetupAccessCookies(ctx *fasthttp.RequestCtx, tokenDTO model.TokenDTO) {
+type sealer interface {
+	Seal(plaintext []byte, additionalData []byte) ([]byte, error)
+	Open(sealed []byte, additionalData []byte) ([]byte, error)
+}
+
+// Codec шифрует значения cookie с токенами. Имя cookie привязывается к шифротексту,
+// чтобы значение нельзя было переложить в другую cookie. Без sealer значения передаются как есть.
+type Codec struct {
+	sealer          sealer
+	acceptPlaintext bool
+}
+
+// NewCodec: acceptPlaintext разрешает нешифрованные значения, выданные до включения шифрования.
+func NewCodec(sealer sealer, acceptPlaintext bool) *Codec {
+	return &Codec{sealer: sealer, acceptPlaintext: acceptPlaintext}
+}
+
+func (c *Codec) Encode(name string, value string) (string, error) {
+	if c.sealer == nil || value == "" {
+		return value, nil
+	}
+
+	sealed, err := c.sealer.Seal([]byte(value), []byte(name))
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(sealed), nil
+}
+
+func (c *Codec) Decode(name string, value []byte) (string, error) {
+	if c.sealer == nil || len(value) == 0 {
+		return string(value), nil
+	}
+
+	plaintext, err := c.sealer.Open(value, []byte(name))
+
+	if err == nil {
+		return string(plaintext), nil
+	}
+
+	if c.acceptPlaintext && errors.Is(err, errorvals.ErrUnknownSealKey) {
+		return string(value), nil
+	}
+
+	return "", err
+}
+
+func SetupAccessCookies(ctx *fasthttp.RequestCtx, tokenDTO model.TokenDTO, codec *Codec) error {
+	at, err := codec.Encode(consts.ATCookieKey, tokenDTO.AccessToken)
+	if err != nil {
+		return err
+	}
+	rt, err := codec.Encode(consts.RTCookieKey, tokenDTO.RefreshToken)
+	if err != nil {
+		return err
+	}
+	idt, err := codec.Encode(consts.IDTCookieKey, tokenDTO.IDToken)
+	if err != nil {
+		return err
+	}
+
 	atCookie := fasthttp.Cookie{}
 	atCookie.SetKey(consts.ATCookieKey)
-	atCookie.SetValue(tokenDTO.AccessToken)
+	atCookie.SetValue(at)
 	atCookie.SetMaxAge(tokenDTO.ExpiresIn)
 	atCookie.SetHTTPOnly(true)
 	atCookie.SetSecure(true)
@@ -19,7 +84,7 @@ func SetupAccessCookies(ctx *fasthttp.RequestCtx, tokenDTO model.TokenDTO) {
 
 	rtCookie := fasthttp.Cookie{}
 	rtCookie.SetKey(consts.RTCookieKey)
-	rtCookie.SetValue(tokenDTO.RefreshToken)
+	rtCookie.SetValue(rt)
 	rtCookie.SetMaxAge(tokenDTO.RefreshExp)
 	rtCookie.SetHTTPOnly(true)
 	rtCookie.SetSecure(true)
@@ -28,7 +93,7 @@ func SetupAccessCookies(ctx *fasthttp.RequestCtx, tokenDTO model.TokenDTO) {
 
 	idtCookie := fasthttp.Cookie{}
 	idtCookie.SetKey(consts.IDTCookieKey)
-	idtCookie.SetValue(tokenDTO.IDToken)
+	idtCookie.SetValue(idt)
 	idtCookie.SetHTTPOnly(true)
 	idtCookie.SetSecure(true)
 	idtCookie.SetSameSite(fasthttp.CookieSameSiteLaxMode)
@@ -37,9 +102,11 @@ func SetupAccessCookies(ctx *fasthttp.RequestCtx, tokenDTO model.TokenDTO) {
 	ctx.Response.Header.SetCookie(&atCookie)
 	ctx.Response.Header.SetCookie(&rtCookie)
 	ctx.Response.Header.SetCookie(&idtCookie)
-	ctx.Request.Header.SetCookie(consts.ATCookieKey, tokenDTO.AccessToken)
-	ctx.Request.Header.SetCookie(consts.RTCookieKey, tokenDTO.RefreshToken)
-	ctx.Request.Header.SetCookie(consts.IDTCookieKey, tokenDTO.IDToken)
+	ctx.Request.Header.SetCookie(consts.ATCookieKey, at)
+	ctx.Request.Header.SetCookie(consts.RTCookieKey, rt)
+	ctx.Request.Header.SetCookie(consts.IDTCookieKey, idt)
+
+	return nil
 }
 
 func EraseAccessCookies(ctx *fasthttp.RequestCtx) {
@@ -74,3 +141,32 @@ func EraseAccessCookies(ctx *fasthttp.RequestCtx) {
 	ctx.Response.Header.SetCookie(&rtCookie)
 	ctx.Response.Header.SetCookie(&idtCookie)
 }
+
+// SetupSessionCookie выставляет id серверной сессии. maxAge <= 0 — cookie живёт до закрытия браузера.
+func SetupSessionCookie(ctx *fasthttp.RequestCtx, sessionID string, maxAge int) {
+	sidCookie := fasthttp.Cookie{}
+	sidCookie.SetKey(consts.SessionCookieKey)
+	sidCookie.SetValue(sessionID)
+	if maxAge > 0 {
+		sidCookie.SetMaxAge(maxAge)
+	}
+	sidCookie.SetHTTPOnly(true)
+	sidCookie.SetSecure(true)
+	sidCookie.SetSameSite(fasthttp.CookieSameSiteLaxMode)
+	sidCookie.SetPath("/")
+
+	ctx.Response.Header.SetCookie(&sidCookie)
+}
+
+func EraseSessionCookie(ctx *fasthttp.RequestCtx) {
+	sidCookie := fasthttp.Cookie{}
+	sidCookie.SetKey(consts.SessionCookieKey)
+	sidCookie.SetValue("")
+	sidCookie.SetMaxAge(-1)
+	sidCookie.SetHTTPOnly(true)
+	sidCookie.SetSecure(true)
+	sidCookie.SetSameSite(fasthttp.CookieSameSiteLaxMode)
+	sidCookie.SetPath("/")
+
+	ctx.Response.Header.SetCookie(&sidCookie)
+}
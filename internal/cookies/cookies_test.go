@@ -0,0 +1,70 @@
+package cookies
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/seal"
+)
+
+func newTestCodec(t *testing.T, acceptPlaintext bool) *Codec {
+	t.Helper()
+
+	vaultCh := make(chan string)
+	t.Cleanup(func() { close(vaultCh) })
+
+	sealer, err := seal.NewSealer("k1:"+base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{3}, 32)),
+		slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})), vaultCh)
+	require.NoError(t, err)
+
+	return NewCodec(sealer, acceptPlaintext)
+}
+
+func TestSetupAccessCookies_Encrypted(t *testing.T) {
+	t.Parallel()
+
+	codec := newTestCodec(t, false)
+	ctx := &fasthttp.RequestCtx{}
+
+	require.NoError(t, SetupAccessCookies(ctx, model.TokenDTO{AccessToken: "at-jwt", RefreshToken: "rt-jwt",
+		IDToken: "idt-jwt", ExpiresIn: 60, RefreshExp: 600}, codec))
+
+	cookie := fasthttp.Cookie{}
+	cookie.SetKey(consts.ATCookieKey)
+	require.True(t, ctx.Response.Header.Cookie(&cookie))
+	require.True(t, strings.HasPrefix(string(cookie.Value()), "k1."))
+	require.NotContains(t, string(cookie.Value()), "at-jwt")
+
+	at, err := codec.Decode(consts.ATCookieKey, cookie.Value())
+	require.NoError(t, err)
+	require.Equal(t, "at-jwt", at)
+
+	// значение access token не подходит для cookie refresh token
+	_, err = codec.Decode(consts.RTCookieKey, cookie.Value())
+	require.ErrorIs(t, err, errorvals.ErrSealedDataInvalid)
+}
+
+func TestCodec_Decode_Plaintext(t *testing.T) {
+	t.Parallel()
+
+	_, err := newTestCodec(t, false).Decode(consts.ATCookieKey, []byte("eyJhbGciOiJSUzI1NiJ9.e30.sig"))
+	require.ErrorIs(t, err, errorvals.ErrUnknownSealKey)
+
+	at, err := newTestCodec(t, true).Decode(consts.ATCookieKey, []byte("eyJhbGciOiJSUzI1NiJ9.e30.sig"))
+	require.NoError(t, err)
+	require.Equal(t, "eyJhbGciOiJSUzI1NiJ9.e30.sig", at)
+
+	at, err = NewCodec(nil, false).Decode(consts.ATCookieKey, []byte("plain"))
+	require.NoError(t, err)
+	require.Equal(t, "plain", at)
+}
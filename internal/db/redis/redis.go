@@ -130,3 +130,81 @@ func (c *Client) Set(ctx context.Context, key string, value string, ttl time.Dur
 	}
 	return nil
 }
+
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	rctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	if c.ConnUpdating.Load() {
+		for c.ConnUpdating.Load() {
+		}
+	}
+	err := c.Client.Del(rctx, keys...).Err()
+
+	if err != nil {
+		c.Alive.Store(false)
+		c.logger.ErrorContext(ctx, "Failed to delete keys from redis", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return err
+	}
+	return nil
+}
+
+// SAddWithTTL добавляет member в множество key и продлевает время жизни множества до ttl.
+func (c *Client) SAddWithTTL(ctx context.Context, key string, member string, ttl time.Duration) error {
+	rctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	if c.ConnUpdating.Load() {
+		for c.ConnUpdating.Load() {
+		}
+	}
+	_, err := c.Client.TxPipelined(rctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(rctx, key, member)
+		pipe.Expire(rctx, key, ttl)
+		return nil
+	})
+
+	if err != nil {
+		c.Alive.Store(false)
+		c.logger.ErrorContext(ctx, "Failed to add set member to redis", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (c *Client) SMembers(ctx context.Context, key string) ([]string, error) {
+	rctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	if c.ConnUpdating.Load() {
+		for c.ConnUpdating.Load() {
+		}
+	}
+	members, err := c.Client.SMembers(rctx, key).Result()
+
+	if err != nil {
+		c.Alive.Store(false)
+		c.logger.ErrorContext(ctx, "Failed to get set members from redis", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+	return members, nil
+}
+
+// RunScript выполняет lua-скрипт (EVALSHA с откатом на EVAL). Пустой ответ скрипта
+// возвращается как errorvals.ErrObjectNotFoundInRepoError.
+func (c *Client) RunScript(ctx context.Context, script *redis.Script, keys []string, args ...any) (any, error) {
+	rctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	if c.ConnUpdating.Load() {
+		for c.ConnUpdating.Load() {
+		}
+	}
+	val, err := script.Run(rctx, c.Client, keys, args...).Result()
+
+	if errors.Is(err, redis.Nil) {
+		return nil, errorvals.ErrObjectNotFoundInRepoError
+	} else if err != nil {
+		c.Alive.Store(false)
+		c.logger.ErrorContext(ctx, "Error running redis script", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	return val, nil
+}
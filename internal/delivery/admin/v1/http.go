@@ -0,0 +1,199 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/fasthttp/router"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
+)
+
+type usecase interface {
+	Sessions(ctx context.Context, userID string) (model.UserSessions, error)
+	SetEnabled(ctx context.Context, userID string, enabled bool) error
+	Logout(ctx context.Context, userID string) (model.SessionsRevoked, error)
+	SetRequiredActions(ctx context.Context, userID string, actions []string) error
+}
+
+type Handler struct {
+	adminUsecase usecase
+	logger       *slog.Logger
+	mw           func(h fasthttp.RequestHandler) fasthttp.RequestHandler
+	authz        func(h fasthttp.RequestHandler) fasthttp.RequestHandler
+}
+
+// NewAdminHandler — authzFunc должен проверять роль поддержки: ручки меняют чужих пользователей.
+func NewAdminHandler(adminUsecase usecase, logger *slog.Logger,
+	mwFunc func(h fasthttp.RequestHandler) fasthttp.RequestHandler,
+	authzFunc func(h fasthttp.RequestHandler) fasthttp.RequestHandler) *Handler {
+	return &Handler{
+		adminUsecase: adminUsecase,
+		logger:       logger,
+		mw:           mwFunc,
+		authz:        authzFunc,
+	}
+}
+
+// Sessions godoc
+// @Summary List user sessions
+// @Description Returns all sessions of the user, for support staff
+// @Tags admin
+// @Param id path string true "User ID"
+// @Produces json
+// @Success 200 {object} model.UserSessions
+// @Failure 403
+// @Failure 404
+// @Router /admin/users/{id}/sessions [get].
+func (ah *Handler) Sessions(ctx *fasthttp.RequestCtx) {
+	contex, userID := ah.request(ctx)
+
+	sessions, err := ah.adminUsecase.Sessions(contex, userID)
+
+	if err != nil {
+		ah.fail(contex, ctx, "could not get user sessions", err)
+		return
+	}
+
+	ah.respond(contex, ctx, sessions)
+}
+
+// Logout godoc
+// @Summary Log user out everywhere
+// @Description Ends all sessions of the user
+// @Tags admin
+// @Param id path string true "User ID"
+// @Produces json
+// @Success 200 {object} model.SessionsRevoked
+// @Failure 403
+// @Failure 404
+// @Router /admin/users/{id}/sessions [delete].
+func (ah *Handler) Logout(ctx *fasthttp.RequestCtx) {
+	contex, userID := ah.request(ctx)
+
+	revoked, err := ah.adminUsecase.Logout(contex, userID)
+
+	if err != nil {
+		ah.fail(contex, ctx, "could not log user out", err)
+		return
+	}
+
+	ah.respond(contex, ctx, revoked)
+}
+
+// SetEnabled godoc
+// @Summary Enable or disable user
+// @Description Disabling a user also ends all their sessions
+// @Tags admin
+// @Param id path string true "User ID"
+// @Param body body model.UserEnabledRequest true "Enabled flag"
+// @Success 204
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Router /admin/users/{id}/enabled [put].
+func (ah *Handler) SetEnabled(ctx *fasthttp.RequestCtx) {
+	contex, userID := ah.request(ctx)
+
+	var req model.UserEnabledRequest
+	err := req.UnmarshalJSON(ctx.PostBody())
+
+	if err != nil || req.Enabled == nil {
+		ah.logger.WarnContext(contex, "invalid enabled request")
+		ctx.Response.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = ah.adminUsecase.SetEnabled(contex, userID, *req.Enabled)
+
+	if err != nil {
+		ah.fail(contex, ctx, "could not change user enabled", err)
+		return
+	}
+
+	ctx.Response.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// SetRequiredActions godoc
+// @Summary Reset user required actions
+// @Description Replaces the user's required actions; an empty list clears them
+// @Tags admin
+// @Param id path string true "User ID"
+// @Param body body model.RequiredActionsRequest true "Required actions"
+// @Success 204
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Router /admin/users/{id}/required-actions [put].
+func (ah *Handler) SetRequiredActions(ctx *fasthttp.RequestCtx) {
+	contex, userID := ah.request(ctx)
+
+	var req model.RequiredActionsRequest
+	err := req.UnmarshalJSON(ctx.PostBody())
+
+	if err != nil {
+		ah.logger.WarnContext(contex, "invalid required actions request", slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.Response.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = ah.adminUsecase.SetRequiredActions(contex, userID, req.Actions)
+
+	if err != nil {
+		ah.fail(contex, ctx, "could not set required actions", err)
+		return
+	}
+
+	ctx.Response.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+func (ah *Handler) request(ctx *fasthttp.RequestCtx) (context.Context, string) {
+	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
+	contex := principal.Attach(context.WithValue(context.Background(), consts.TraceContextKey, trace), ctx)
+	userID, _ := ctx.UserValue("id").(string)
+
+	return contex, userID
+}
+
+func (ah *Handler) fail(contex context.Context, ctx *fasthttp.RequestCtx, msg string, err error) {
+	ah.logger.ErrorContext(contex, msg, slog.String(consts.ErrorLoggerKey, err.Error()))
+
+	switch {
+	case errors.Is(err, errorvals.ErrInvalidArgument):
+		ctx.Response.SetStatusCode(fasthttp.StatusBadRequest)
+	case errors.Is(err, errorvals.ErrObjectNotFoundInRepoError):
+		ctx.Response.SetStatusCode(fasthttp.StatusNotFound)
+	case errors.Is(err, errorvals.ErrUpstreamUnavailable):
+		ctx.Response.SetStatusCode(fasthttp.StatusServiceUnavailable)
+	default:
+		ctx.Response.SetStatusCode(fasthttp.StatusInternalServerError)
+	}
+}
+
+func (ah *Handler) respond(contex context.Context, ctx *fasthttp.RequestCtx, body json.Marshaler) {
+	bodyJSON, err := body.MarshalJSON()
+
+	if err != nil {
+		ah.logger.ErrorContext(contex, "could not marshal response", slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.Response.SetStatusCode(fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.Response.SetBody(bodyJSON)
+	ctx.Response.Header.SetContentType(consts.ApplicationJSONContentType)
+	ctx.Response.SetStatusCode(fasthttp.StatusOK)
+}
+
+func (ah *Handler) RegisterRoutes(apiGroup *router.Group) {
+	g := apiGroup.Group("/admin/users/{id}")
+	g.GET("/sessions", ah.mw(ah.authz(ah.Sessions)))
+	g.DELETE("/sessions", ah.mw(ah.authz(ah.Logout)))
+	g.PUT("/enabled", ah.mw(ah.authz(ah.SetEnabled)))
+	g.PUT("/required-actions", ah.mw(ah.authz(ah.SetRequiredActions)))
+}
@@ -2,42 +2,48 @@ package auth
 
 import (
 	"context"
-	"errors"
 	"log/slog"
 
-	"google.golang.org/grpc/metadata"
-
+	"github.com/dnonakolesax/noted-auth/internal/configs"
 	"github.com/dnonakolesax/noted-auth/internal/consts"
 	auth "github.com/dnonakolesax/noted-auth/internal/delivery/auth/v1/proto"
+	"github.com/dnonakolesax/noted-auth/internal/grpcerr"
+	"github.com/dnonakolesax/noted-auth/internal/model"
 )
 
+type serviceTokenUsecase interface {
+	Get(ctx context.Context, audience string, scopes []string) (model.ServiceToken, error)
+}
+
 type Server struct {
 	auth.UnimplementedAuthServiceServer
 
 	logger      *slog.Logger
 	authUsecase usecase
+	sessions    webSessionUsecase
+	sessionMode string
+	// serviceTokens — nil, если брокер токенов выключен.
+	serviceTokens serviceTokenUsecase
 }
 
-func NewUserServer(authUsecase usecase, logger *slog.Logger) *Server {
+func NewUserServer(authUsecase usecase, sessions webSessionUsecase, sessionMode string,
+	serviceTokens serviceTokenUsecase, logger *slog.Logger) *Server {
 	return &Server{
-		authUsecase: authUsecase,
-		logger:      logger,
+		authUsecase:   authUsecase,
+		sessions:      sessions,
+		sessionMode:   sessionMode,
+		serviceTokens: serviceTokens,
+		logger:        logger,
 	}
 }
 
 func (us *Server) AuthUserIDCtx(ctx context.Context, req *auth.UserTokens) (*auth.TokenData, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-
-	if !ok {
-		us.logger.ErrorContext(ctx, "Couldn't parse request metadata")
-		return nil, errors.New("couldn't parse request metadata")
+	// trace id кладёт в контекст интерсептор interceptors.UnaryTrace
+	if req.GetSession() != "" {
+		return us.authSession(ctx, req.GetSession())
 	}
-	traceID := md["trace_id"]
-	us.logger.DebugContext(ctx, "got request", slog.String("trace", traceID[0]))
 
-	trace := slog.String(consts.TraceLoggerKey, traceID[0])
-	contex := context.WithValue(context.Background(), consts.TraceContextKey, trace)
-	tokenData, err := us.authUsecase.GetUserID(contex, req.GetAuth(), req.GetRefresh())
+	tokenData, err := us.authUsecase.GetUserID(ctx, req.GetAuth(), req.GetRefresh())
 
 	if err != nil {
 		us.logger.ErrorContext(ctx, "Error getting user", slog.String(consts.ErrorLoggerKey, err.Error()))
@@ -59,3 +65,105 @@ func (us *Server) AuthUserIDCtx(ctx context.Context, req *auth.UserTokens) (*aut
 
 	return uinfo, nil
 }
+
+// authSession разрешает серверную сессию. Токены остаются на сервере и клиенту не возвращаются.
+func (us *Server) authSession(ctx context.Context, sessionID string) (*auth.TokenData, error) {
+	if us.sessionMode != configs.SessionModeServer {
+		us.logger.WarnContext(ctx, "Session passed, but server-side sessions are disabled")
+		return nil, grpcerr.InvalidArgument("Session", "server-side sessions are disabled")
+	}
+
+	session, err := us.sessions.Resolve(ctx, sessionID)
+
+	if err != nil {
+		us.logger.ErrorContext(ctx, "Error resolving session", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	return &auth.TokenData{ID: session.UserID}, nil
+}
+
+// Revoke отзывает токен (RFC 7009). Если передан Session, серверная сессия удаляется
+// и отзываются оба её токена, Token и TokenTypeHint при этом игнорируются. Вызывать могут
+// только сервисы Noted, это проверяет interceptors.ServiceAuth.
+func (us *Server) Revoke(ctx context.Context, req *auth.RevokeRequest) (*auth.RevokeResponse, error) {
+	if req.GetSession() != "" {
+		if us.sessionMode != configs.SessionModeServer {
+			us.logger.WarnContext(ctx, "Session passed, but server-side sessions are disabled")
+			return nil, grpcerr.InvalidArgument("Session", "server-side sessions are disabled")
+		}
+
+		tokens, err := us.sessions.Destroy(ctx, req.GetSession())
+
+		if err != nil {
+			us.logger.ErrorContext(ctx, "Error destroying session", slog.String(consts.ErrorLoggerKey, err.Error()))
+			return nil, err
+		}
+
+		if err = us.authUsecase.RevokeTokens(ctx, tokens.AccessToken, tokens.RefreshToken); err != nil {
+			us.logger.ErrorContext(ctx, "Error revoking session tokens",
+				slog.String(consts.ErrorLoggerKey, err.Error()))
+			return nil, err
+		}
+
+		return &auth.RevokeResponse{}, nil
+	}
+
+	if req.GetToken() == "" {
+		return nil, grpcerr.InvalidArgument("Token", "token is empty")
+	}
+
+	if err := us.authUsecase.Revoke(ctx, req.GetToken(), req.GetTokenTypeHint()); err != nil {
+		us.logger.ErrorContext(ctx, "Error revoking token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	return &auth.RevokeResponse{}, nil
+}
+
+// GetServiceToken выдаёт токен client_credentials для вызова сервиса Audience. Кто может его
+// вызывать, проверяет interceptors.ServiceAuth.
+func (us *Server) GetServiceToken(ctx context.Context, req *auth.ServiceTokenRequest) (*auth.ServiceToken, error) {
+	if us.serviceTokens == nil {
+		return us.UnimplementedAuthServiceServer.GetServiceToken(ctx, req)
+	}
+
+	token, err := us.serviceTokens.Get(ctx, req.GetAudience(), req.GetScopes())
+
+	if err != nil {
+		us.logger.ErrorContext(ctx, "Error getting service token", slog.String(consts.ErrorLoggerKey, err.Error()),
+			slog.String("audience", req.GetAudience()))
+		return nil, err
+	}
+
+	return &auth.ServiceToken{
+		AccessToken: token.AccessToken,
+		ExpiresIn:   int32(token.ExpiresIn), //nolint:gosec // expires_in keycloak заведомо меньше MaxInt32
+		TokenType:   token.TokenType,
+		Scope:       token.Scope,
+	}, nil
+}
+
+// ExchangeToken обменивает токен пользователя на токен для Audience (RFC 8693). Вызывать могут
+// только сервисы Noted, это проверяет interceptors.ServiceAuth.
+func (us *Server) ExchangeToken(ctx context.Context, req *auth.ExchangeTokenRequest) (*auth.ExchangedToken, error) {
+	if req.GetSubjectToken() == "" {
+		return nil, grpcerr.InvalidArgument("SubjectToken", "token is empty")
+	}
+
+	token, err := us.authUsecase.ExchangeToken(ctx, req.GetSubjectToken(), req.GetAudience())
+
+	if err != nil {
+		us.logger.ErrorContext(ctx, "Error exchanging token", slog.String(consts.ErrorLoggerKey, err.Error()),
+			slog.String("audience", req.GetAudience()))
+		return nil, err
+	}
+
+	return &auth.ExchangedToken{
+		AccessToken:     token.AccessToken,
+		IssuedTokenType: token.IssuedTokenType,
+		TokenType:       token.TokenType,
+		ExpiresIn:       int32(token.ExpiresIn), //nolint:gosec // expires_in keycloak заведомо меньше MaxInt32
+		Scope:           token.Scope,
+	}, nil
+}
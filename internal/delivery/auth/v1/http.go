@@ -9,32 +9,58 @@ import (
 	"github.com/fasthttp/router"
 	"github.com/valyala/fasthttp"
 
+	"github.com/dnonakolesax/noted-auth/internal/configs"
 	"github.com/dnonakolesax/noted-auth/internal/consts"
 	"github.com/dnonakolesax/noted-auth/internal/cookies"
 	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/metrics"
 	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
 )
 
 type usecase interface {
-	GetAuthLink(ctx context.Context, retunURL string) (string, error)
+	GetAuthLink(ctx context.Context, retunURL string, clientIP string, userAgent string) (string, error)
 	GetToken(ctx context.Context, state string, token string) (model.TokenDTO, error)
 	GetLogoutLink(ctx context.Context, idt string) string
 	GetUserID(ctx context.Context, at string, rt string) (model.TokenGRPCDTO, error)
+	Revoke(ctx context.Context, token string, hint string) error
+	RevokeOwned(ctx context.Context, owner string, token string, hint string) error
+	RevokeTokens(ctx context.Context, at string, rt string) error
+	ExchangeToken(ctx context.Context, subjectToken string, audience string) (model.ExchangedToken, error)
+}
+
+type webSessionUsecase interface {
+	Create(ctx context.Context, tokens model.TokenDTO) (string, error)
+	Resolve(ctx context.Context, id string) (model.WebSession, error)
+	Destroy(ctx context.Context, id string) (model.TokenDTO, error)
 }
 
 type Handler struct {
-	basicReturnURL string
-	requiredPrefix string
-	authUsecase    usecase
-	logger         *slog.Logger
+	basicReturnURL  string
+	requiredPrefix  string
+	authUsecase     usecase
+	sessions        webSessionUsecase
+	sessionMode     string
+	codec           *cookies.Codec
+	securityMetrics *metrics.SecurityMetrics
+	logger          *slog.Logger
+	mw              func(h fasthttp.RequestHandler) fasthttp.RequestHandler
 }
 
-func NewAuthHandler(basicReturnURL string, requiredPrefix string, authUsecase usecase, logger *slog.Logger) *Handler {
+// NewAuthHandler: sessions используется только в режиме configs.SessionModeServer, codec — только в режиме cookies.
+func NewAuthHandler(basicReturnURL string, requiredPrefix string, authUsecase usecase, sessions webSessionUsecase,
+	sessionMode string, codec *cookies.Codec, securityMetrics *metrics.SecurityMetrics,
+	logger *slog.Logger, mwFunc func(h fasthttp.RequestHandler) fasthttp.RequestHandler) *Handler {
 	return &Handler{
-		basicReturnURL: basicReturnURL,
-		requiredPrefix: requiredPrefix,
-		authUsecase:    authUsecase,
-		logger:         logger,
+		basicReturnURL:  basicReturnURL,
+		requiredPrefix:  requiredPrefix,
+		authUsecase:     authUsecase,
+		sessions:        sessions,
+		sessionMode:     sessionMode,
+		codec:           codec,
+		securityMetrics: securityMetrics,
+		logger:          logger,
+		mw:              mwFunc,
 	}
 }
 
@@ -65,7 +91,8 @@ func (ah *Handler) handleAuth(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	redirectLink, err := ah.authUsecase.GetAuthLink(contex, returnURLString)
+	redirectLink, err := ah.authUsecase.GetAuthLink(contex, returnURLString, ctx.RemoteIP().String(),
+		string(ctx.UserAgent()))
 
 	if err != nil {
 		ah.logger.ErrorContext(contex, "Error while getting auth link", slog.String(consts.ErrorLoggerKey, err.Error()))
@@ -83,7 +110,8 @@ func (ah *Handler) handleAuth(ctx *fasthttp.RequestCtx) {
 // @Param state query string true "State that was sent to keycloak"
 // @Param code query string true "Access code from keycloak"
 // @Success 301
-// @Failure 400
+// @Failure 400 "State has already been used"
+// @Failure 401 "ID token failed verification"
 // @Failure 500
 // @Router /openid-connect/token [get].
 func (ah *Handler) handleToken(ctx *fasthttp.RequestCtx) {
@@ -120,6 +148,18 @@ func (ah *Handler) handleToken(ctx *fasthttp.RequestCtx) {
 	ah.logger.DebugContext(contex, "dto", slog.String("id key", tokenDTO.IDToken))
 
 	if err != nil {
+		if errors.Is(err, errorvals.ErrStateReplayed) {
+			ah.securityMetrics.StateReplays.Inc()
+			ah.logger.WarnContext(contex, "State replay detected")
+			ctx.SetStatusCode(fasthttp.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, errorvals.ErrIDTokenInvalid) {
+			ah.securityMetrics.IDTokenRejections.Inc()
+			ah.logger.WarnContext(contex, "ID token rejected", slog.String(consts.ErrorLoggerKey, err.Error()))
+			ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			return
+		}
 		if errors.Is(err, errorvals.ErrObjectNotFoundInRepoError) {
 			ah.logger.WarnContext(contex, "State is not found in repo")
 			ctx.SetStatusCode(fasthttp.StatusRequestTimeout)
@@ -130,30 +170,224 @@ func (ah *Handler) handleToken(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	cookies.SetupAccessCookies(ctx, tokenDTO)
+	if ah.sessionMode == configs.SessionModeServer {
+		sessionID, serr := ah.sessions.Create(contex, tokenDTO)
+
+		if serr != nil {
+			ah.logger.ErrorContext(contex, "Error while creating session",
+				slog.String(consts.ErrorLoggerKey, serr.Error()))
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			return
+		}
+		cookies.SetupSessionCookie(ctx, sessionID, tokenDTO.RefreshExp)
+	} else if err = cookies.SetupAccessCookies(ctx, tokenDTO, ah.codec); err != nil {
+		ah.logger.ErrorContext(contex, "Error while setting up cookies", slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		return
+	}
 
 	ctx.Redirect(tokenDTO.ReturnURL, fasthttp.StatusFound)
 }
 
 // HandleLogout godoc
 // @Summary Handle logout from keycloak
-// @Description Return user to homepage
+// @Description Revokes user's tokens in keycloak and returns user to homepage
 // @Tags openid-connect
 // @Success 302
 // @Router /openid-connect/logout [get].
 func (ah *Handler) HandleLogout(ctx *fasthttp.RequestCtx) {
 	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
 	contex := context.WithValue(context.Background(), consts.TraceContextKey, trace)
-	idt := ctx.Request.Header.Cookie(consts.IDTCookieKey)
 
-	if idt == nil {
+	if ah.sessionMode == configs.SessionModeServer {
+		ah.logoutSession(contex, ctx)
+		return
+	}
+
+	idtCookie := ctx.Request.Header.Cookie(consts.IDTCookieKey)
+
+	if idtCookie == nil {
 		ah.logger.WarnContext(contex, "Id token is empty")
 		ctx.SetStatusCode(fasthttp.StatusBadRequest)
 		return
 	}
 	cookies.EraseAccessCookies(ctx)
 
-	ctx.Redirect(ah.authUsecase.GetLogoutLink(contex, string(idt)), fasthttp.StatusFound)
+	idt, err := ah.codec.Decode(consts.IDTCookieKey, idtCookie)
+
+	if err != nil {
+		// cookies уже стёрты, поэтому токены всё равно отзываем, а в keycloak уходим без id_token_hint
+		ah.logger.WarnContext(contex, "Failed to decode id token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		idt = consts.EmptyString
+	}
+
+	ah.revokeTokens(contex, ah.decodeCookie(contex, ctx, consts.ATCookieKey),
+		ah.decodeCookie(contex, ctx, consts.RTCookieKey))
+
+	ctx.Redirect(ah.authUsecase.GetLogoutLink(contex, idt), fasthttp.StatusFound)
+}
+
+func (ah *Handler) logoutSession(contex context.Context, ctx *fasthttp.RequestCtx) {
+	sid := ctx.Request.Header.Cookie(consts.SessionCookieKey)
+
+	if sid == nil {
+		ah.logger.WarnContext(contex, "Session id is empty")
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+	cookies.EraseSessionCookie(ctx)
+
+	tokens, err := ah.sessions.Destroy(contex, string(sid))
+
+	if err != nil {
+		ah.logger.WarnContext(contex, "Error while destroying session", slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	ah.revokeTokens(contex, tokens.AccessToken, tokens.RefreshToken)
+	ctx.Redirect(ah.authUsecase.GetLogoutLink(contex, tokens.IDToken), fasthttp.StatusFound)
+}
+
+// decodeCookie возвращает пустую строку, если cookie нет или она не расшифровывается.
+func (ah *Handler) decodeCookie(contex context.Context, ctx *fasthttp.RequestCtx, name string) string {
+	raw := ctx.Request.Header.Cookie(name)
+
+	if raw == nil {
+		return consts.EmptyString
+	}
+
+	value, err := ah.codec.Decode(name, raw)
+
+	if err != nil {
+		ah.logger.WarnContext(contex, "Failed to decode cookie", slog.String("cookie", name),
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return consts.EmptyString
+	}
+
+	return value
+}
+
+// revokeTokens не прерывает logout: сессия keycloak всё равно завершится по ссылке на logout.
+func (ah *Handler) revokeTokens(contex context.Context, at string, rt string) {
+	if err := ah.authUsecase.RevokeTokens(contex, at, rt); err != nil {
+		ah.logger.WarnContext(contex, "Failed to revoke tokens on logout",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+	}
+}
+
+// HandleRevoke godoc
+// @Summary Revoke token
+// @Description Revokes access or refresh token in keycloak (RFC 7009)
+// @Tags openid-connect injectable
+// @Accept x-www-form-urlencoded
+// @Param token formData string true "Token to revoke"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 500
+// @Failure 503
+// @Router /openid-connect/revoke [post].
+func (ah *Handler) handleRevoke(ctx *fasthttp.RequestCtx) {
+	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
+	contex := principal.Attach(context.WithValue(context.Background(), consts.TraceContextKey, trace), ctx)
+
+	token := ctx.PostArgs().Peek("token")
+
+	if len(token) == 0 {
+		ah.logger.WarnContext(contex, "Token to revoke is empty")
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	caller, ok := principal.FromContext(contex)
+
+	if !ok {
+		ah.logger.WarnContext(contex, "Revoke request without principal")
+		ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+		return
+	}
+
+	err := ah.authUsecase.RevokeOwned(contex, caller.Subject, string(token),
+		string(ctx.PostArgs().Peek("token_type_hint")))
+
+	if err != nil {
+		ah.logger.ErrorContext(contex, "Error while revoking token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		switch {
+		case errors.Is(err, errorvals.ErrInvalidArgument):
+			ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		case errors.Is(err, errorvals.ErrTokenNotOwned):
+			ctx.SetStatusCode(fasthttp.StatusForbidden)
+		case errors.Is(err, errorvals.ErrUpstreamUnavailable):
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		default:
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+}
+
+// HandleTokenExchange godoc
+// @Summary Exchange token
+// @Description Exchanges caller's own access token for a token narrowed to audience (RFC 8693)
+// @Tags openid-connect injectable
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param audience formData string true "Keycloak client of the downstream service"
+// @Success 200 {object} model.ExchangedToken
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 503
+// @Router /openid-connect/token-exchange [post].
+func (ah *Handler) handleTokenExchange(ctx *fasthttp.RequestCtx) {
+	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
+	contex := principal.Attach(context.WithValue(context.Background(), consts.TraceContextKey, trace), ctx)
+
+	// обменивается только собственный токен вызывающего: чужой subject_token превратился бы в токены
+	// downstream-сервисов от имени нашего конфиденциального клиента
+	subjectToken, _ := ctx.UserValue(consts.CtxAccessTokenKey).(string)
+
+	token, err := ah.authUsecase.ExchangeToken(contex, subjectToken, string(ctx.PostArgs().Peek("audience")))
+
+	if err != nil {
+		ah.logger.ErrorContext(contex, "Error while exchanging token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		switch {
+		case errors.Is(err, errorvals.ErrInvalidArgument):
+			ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		case errors.Is(err, errorvals.ErrTokenInvalid), errors.Is(err, errorvals.ErrTokenExpired):
+			ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+		case errors.Is(err, errorvals.ErrAudienceNotAllowed):
+			ctx.SetStatusCode(fasthttp.StatusForbidden)
+		case errors.Is(err, errorvals.ErrTokenExchangeDisabled):
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+		case errors.Is(err, errorvals.ErrUpstreamUnavailable), errors.Is(err, errorvals.ErrJWKSUnavailable):
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		default:
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		}
+		return
+	}
+
+	tokenJSON, err := token.MarshalJSON()
+
+	if err != nil {
+		ah.logger.ErrorContext(contex, "could not marshal exchanged token",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		return
+	}
+
+	// RFC 6749, раздел 5.1: ответ с токеном не кэшируется
+	ctx.Response.Header.Set(fasthttp.HeaderCacheControl, "no-store")
+	ctx.Response.SetBody(tokenJSON)
+	ctx.Response.Header.SetContentType(consts.ApplicationJSONContentType)
+	ctx.SetStatusCode(fasthttp.StatusOK)
 }
 
 func (ah *Handler) RegisterRoutes(apiGroup *router.Group) {
@@ -161,4 +395,6 @@ func (ah *Handler) RegisterRoutes(apiGroup *router.Group) {
 	group.GET("/auth", ah.handleAuth)
 	group.GET("/token", ah.handleToken)
 	group.GET("/logout", ah.HandleLogout)
+	group.POST("/revoke", ah.mw(ah.handleRevoke))
+	group.POST("/token-exchange", ah.mw(ah.handleTokenExchange))
 }
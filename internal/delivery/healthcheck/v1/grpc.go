@@ -0,0 +1,88 @@
+package healthcheck
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// GRPCHealth публикует статусы grpc.health.v1 по тем же флагам, что и HTTP /healthcheck/.
+// Пустое имя сервиса (общий статус сервера) требует всех зависимостей, как и HTTP-проверка.
+type GRPCHealth struct {
+	server   *health.Server
+	services map[string][]*atomic.Bool
+	statuses map[string]healthpb.HealthCheckResponse_ServingStatus
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewGRPCHealth: AuthService зависит от keycloak и redis, UserService — от postgres.
+func NewGRPCHealth(redisAlive *atomic.Bool, pSQLAlive *atomic.Bool, keycloakAlive *atomic.Bool,
+	vaultAlive *atomic.Bool, authService string, userService string, interval time.Duration,
+	logger *slog.Logger) *GRPCHealth {
+	gh := &GRPCHealth{
+		server: health.NewServer(),
+		services: map[string][]*atomic.Bool{
+			"":          {redisAlive, pSQLAlive, keycloakAlive, vaultAlive},
+			authService: {keycloakAlive, redisAlive},
+			userService: {pSQLAlive},
+		},
+		statuses: make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+		interval: interval,
+		logger:   logger,
+	}
+	gh.update()
+
+	return gh
+}
+
+// Register регистрирует grpc.health.v1 на сервере.
+func (gh *GRPCHealth) Register(s grpc.ServiceRegistrar) {
+	healthpb.RegisterHealthServer(s, gh.server)
+}
+
+// Run обновляет статусы раз в interval, пока не отменён ctx.
+func (gh *GRPCHealth) Run(ctx context.Context) {
+	ticker := time.NewTicker(gh.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			gh.update()
+		}
+	}
+}
+
+// Shutdown переводит все сервисы в NOT_SERVING, чтобы балансировщик увёл трафик до остановки сервера.
+func (gh *GRPCHealth) Shutdown() {
+	gh.server.Shutdown()
+}
+
+func (gh *GRPCHealth) update() {
+	for service, deps := range gh.services {
+		status := healthpb.HealthCheckResponse_SERVING
+
+		for _, alive := range deps {
+			if !alive.Load() {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+				break
+			}
+		}
+
+		if prev, ok := gh.statuses[service]; ok && prev != status {
+			gh.logger.Warn("grpc health status changed", slog.String("service", service),
+				slog.String("status", status.String()))
+		}
+
+		gh.statuses[service] = status
+		gh.server.SetServingStatus(service, status)
+	}
+}
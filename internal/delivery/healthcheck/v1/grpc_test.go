@@ -0,0 +1,97 @@
+package healthcheck
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	authService = "auth.AuthService"
+	userService = "user.UserService"
+)
+
+type deps struct {
+	redis, pSQL, keycloak, vault atomic.Bool
+}
+
+func newGRPCHealth(t *testing.T, interval time.Duration) (*GRPCHealth, *deps) {
+	t.Helper()
+
+	d := &deps{}
+	d.redis.Store(true)
+	d.pSQL.Store(true)
+	d.keycloak.Store(true)
+	d.vault.Store(true)
+
+	gh := NewGRPCHealth(&d.redis, &d.pSQL, &d.keycloak, &d.vault, authService, userService, interval,
+		slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})))
+
+	return gh, d
+}
+
+func status(t *testing.T, gh *GRPCHealth, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	resp, err := gh.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+
+	return resp.GetStatus()
+}
+
+func TestGRPCHealth_StatusesFollowDependencies(t *testing.T) {
+	t.Parallel()
+
+	gh, d := newGRPCHealth(t, time.Hour)
+
+	for _, service := range []string{"", authService, userService} {
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, gh, service), service)
+	}
+
+	// postgres нужен только UserService и общему статусу
+	d.pSQL.Store(false)
+	gh.update()
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, gh, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, gh, authService))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, gh, userService))
+
+	d.pSQL.Store(true)
+	d.keycloak.Store(false)
+	gh.update()
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, gh, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, gh, authService))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, gh, userService))
+}
+
+func TestGRPCHealth_RunPicksUpChanges(t *testing.T) {
+	t.Parallel()
+
+	gh, d := newGRPCHealth(t, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go gh.Run(ctx)
+
+	d.redis.Store(false)
+	require.Eventually(t, func() bool {
+		return status(t, gh, authService) == healthpb.HealthCheckResponse_NOT_SERVING
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, gh, userService))
+}
+
+func TestGRPCHealth_ShutdownNotServing(t *testing.T) {
+	t.Parallel()
+
+	gh, _ := newGRPCHealth(t, time.Hour)
+	gh.Shutdown()
+
+	for _, service := range []string{"", authService, userService} {
+		require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, gh, service), service)
+	}
+}
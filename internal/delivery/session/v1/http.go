@@ -2,31 +2,52 @@ package session
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/fasthttp/router"
 	"github.com/valyala/fasthttp"
 
+	"github.com/dnonakolesax/noted-auth/internal/configs"
 	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/cookies"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
 )
 
 type usecase interface {
-	Get(ctx context.Context, token string) ([]byte, error)
-	Delete(ctx context.Context, token string, id string) error
+	Get(ctx context.Context, token string, userAgent string) (model.Sessions, error)
+	Delete(ctx context.Context, token string, id string) (model.SessionsRevoked, error)
+	DeleteAll(ctx context.Context, token string, keepCurrent bool) (model.SessionsRevoked, error)
+	DeleteByClient(ctx context.Context, token string, clientID string) (model.SessionsRevoked, error)
+}
+
+type webSessionUsecase interface {
+	Destroy(ctx context.Context, id string) (model.TokenDTO, error)
 }
 
 type Handler struct {
 	sessionUsecase usecase
+	webSessions    webSessionUsecase
+	sessionMode    string
 	logger         *slog.Logger
 	mw             func(h fasthttp.RequestHandler) fasthttp.RequestHandler
+	authz          func(h fasthttp.RequestHandler) fasthttp.RequestHandler
 }
 
-func NewSessionHandler(sesionUsecase usecase, logger *slog.Logger,
-	mwFunc func(h fasthttp.RequestHandler) fasthttp.RequestHandler) *Handler {
+// NewSessionHandler — webSessions нужен, чтобы удалить серверную сессию, если отозвана текущая.
+func NewSessionHandler(sesionUsecase usecase, webSessions webSessionUsecase, sessionMode string,
+	logger *slog.Logger, mwFunc func(h fasthttp.RequestHandler) fasthttp.RequestHandler,
+	authzFunc func(h fasthttp.RequestHandler) fasthttp.RequestHandler) *Handler {
 	return &Handler{
 		sessionUsecase: sesionUsecase,
+		webSessions:    webSessions,
+		sessionMode:    sessionMode,
 		logger:         logger,
 		mw:             mwFunc,
+		authz:          authzFunc,
 	}
 }
 
@@ -35,78 +56,195 @@ func NewSessionHandler(sesionUsecase usecase, logger *slog.Logger,
 // @Description Returns all user's sessions
 // @Tags openid-connect injectable
 // @Produces json
-// @Success 200 {object} []model.Session
+// @Success 200 {object} model.Sessions
 // @Failure 400
+// @Failure 401
 // @Failure 500
+// @Failure 503
 // @Router /session [get].
 func (sh *Handler) Get(ctx *fasthttp.RequestCtx) {
 	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
-	contex := context.WithValue(context.Background(), consts.TraceContextKey, trace)
-	token := ctx.Request.Header.Cookie(consts.ATCookieKey)
+	contex := principal.Attach(context.WithValue(context.Background(), consts.TraceContextKey, trace), ctx)
+	token, ok := ctx.UserValue(consts.CtxAccessTokenKey).(string)
 
-	if token == nil {
+	if !ok || token == "" {
 		sh.logger.WarnContext(contex, "request sent without token")
 		ctx.Response.SetStatusCode(fasthttp.StatusUnauthorized)
 		return
 	}
 
-	sessions, err := sh.sessionUsecase.Get(contex, string(token))
+	sessions, err := sh.sessionUsecase.Get(contex, token, string(ctx.UserAgent()))
 
 	if err != nil {
 		sh.logger.ErrorContext(contex, "Error while getting sessions: ", "err", err.Error())
+		ctx.Response.SetStatusCode(statusCode(err))
+		return
+	}
+
+	sessionsJSON, err := sessions.MarshalJSON()
+
+	if err != nil {
+		sh.logger.ErrorContext(contex, "could not marshal sessions", slog.String(consts.ErrorLoggerKey, err.Error()))
 		ctx.Response.SetStatusCode(fasthttp.StatusInternalServerError)
 		return
 	}
 
-	ctx.Response.SetBody(sessions)
-	ctx.Response.Header.SetContentType("application/json")
+	ctx.Response.SetBody(sessionsJSON)
+	ctx.Response.Header.SetContentType(consts.ApplicationJSONContentType)
 	ctx.Response.SetStatusCode(fasthttp.StatusOK)
 }
 
 // Delete godoc
 // @Summary Delete session
-// @Description Deletes session by id, or all session if id is not passed
+// @Description Deletes session by id
 // @Tags openid-connect injectable
-// @Param id path string false "Session ID"
-// @Success 200
+// @Param id path string true "Session ID"
+// @Produces json
+// @Success 200 {object} model.SessionsRevoked
 // @Failure 400
-// @Failure 500
+// @Failure 401
+// @Failure 503
 // @Router /session/{id} [delete].
 func (sh *Handler) Delete(ctx *fasthttp.RequestCtx) {
+	sh.revoke(ctx, func(contex context.Context, token string) (model.SessionsRevoked, error) {
+		sessionID, _ := ctx.UserValue("id").(string)
+		return sh.sessionUsecase.Delete(contex, token, sessionID)
+	})
+}
+
+// DeleteAll godoc
+// @Summary Delete all sessions
+// @Description Logs out everywhere, including the current session
+// @Tags openid-connect injectable
+// @Produces json
+// @Success 200 {object} model.SessionsRevoked
+// @Failure 401
+// @Failure 503
+// @Router /session [delete].
+func (sh *Handler) DeleteAll(ctx *fasthttp.RequestCtx) {
+	sh.revoke(ctx, func(contex context.Context, token string) (model.SessionsRevoked, error) {
+		return sh.sessionUsecase.DeleteAll(contex, token, false)
+	})
+}
+
+// DeleteOthers godoc
+// @Summary Delete all sessions except the current one
+// @Description Logs out everywhere except this device
+// @Tags openid-connect injectable
+// @Produces json
+// @Success 200 {object} model.SessionsRevoked
+// @Failure 401
+// @Failure 503
+// @Router /session/others [delete].
+func (sh *Handler) DeleteOthers(ctx *fasthttp.RequestCtx) {
+	sh.revoke(ctx, func(contex context.Context, token string) (model.SessionsRevoked, error) {
+		return sh.sessionUsecase.DeleteAll(contex, token, true)
+	})
+}
+
+// DeleteByClient godoc
+// @Summary Delete sessions of a client
+// @Description Deletes every session the client takes part in; sessions that could not be deleted are listed in failed
+// @Tags openid-connect injectable
+// @Param client path string true "Client ID"
+// @Produces json
+// @Success 200 {object} model.SessionsRevoked
+// @Failure 400
+// @Failure 401
+// @Failure 503
+// @Router /session/client/{client} [delete].
+func (sh *Handler) DeleteByClient(ctx *fasthttp.RequestCtx) {
+	sh.revoke(ctx, func(contex context.Context, token string) (model.SessionsRevoked, error) {
+		clientID, _ := ctx.UserValue("client").(string)
+		return sh.sessionUsecase.DeleteByClient(contex, token, clientID)
+	})
+}
+
+// revoke — общая часть ручек удаления: токен из AuthMW, вызов usecase и итог в JSON.
+func (sh *Handler) revoke(ctx *fasthttp.RequestCtx,
+	do func(contex context.Context, token string) (model.SessionsRevoked, error)) {
 	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
-	contex := context.WithValue(context.Background(), consts.TraceContextKey, trace)
-	token := ctx.Request.Header.Cookie(consts.ATCookieKey)
+	contex := principal.Attach(context.WithValue(context.Background(), consts.TraceContextKey, trace), ctx)
+	token, ok := ctx.UserValue(consts.CtxAccessTokenKey).(string)
 
-	if token == nil {
+	if !ok || token == "" {
 		sh.logger.WarnContext(contex, "request sent without token")
 		ctx.Response.SetStatusCode(fasthttp.StatusUnauthorized)
 		return
 	}
 
-	sessionID := ctx.UserValue("id")
+	revoked, err := do(contex, token)
+
+	if err != nil {
+		sh.logger.ErrorContext(contex, "Error while deleting sessions", slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.Response.SetStatusCode(statusCode(err))
+		return
+	}
 
-	sidStr := ""
-	if sessionID != nil {
-		var ok bool
-		sidStr, ok = sessionID.(string)
-		if !ok {
-			sh.logger.ErrorContext(contex, "Error while casting sessionId to string", slog.Any("sessionID", sessionID))
-		}
+	if revoked.CurrentRevoked {
+		sh.endCurrentSession(contex, ctx)
 	}
 
-	err := sh.sessionUsecase.Delete(contex, string(token), sidStr)
+	revokedJSON, err := revoked.MarshalJSON()
 
 	if err != nil {
-		sh.logger.ErrorContext(contex, "Error while deleting session", slog.String(consts.ErrorLoggerKey, err.Error()))
-		ctx.Response.SetStatusCode(fasthttp.StatusUnauthorized)
+		sh.logger.ErrorContext(contex, "could not marshal revoked sessions",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.Response.SetStatusCode(fasthttp.StatusInternalServerError)
 		return
 	}
 
+	ctx.Response.SetBody(revokedJSON)
+	ctx.Response.Header.SetContentType(consts.ApplicationJSONContentType)
 	ctx.Response.SetStatusCode(fasthttp.StatusOK)
 }
 
+// endCurrentSession убирает локальные следы текущей сессии, которую keycloak уже завершил:
+// cookie с токенами или серверную сессию вместе с её cookie.
+func (sh *Handler) endCurrentSession(contex context.Context, ctx *fasthttp.RequestCtx) {
+	if sh.sessionMode != configs.SessionModeServer {
+		cookies.EraseAccessCookies(ctx)
+		return
+	}
+
+	sid := ctx.Request.Header.Cookie(consts.SessionCookieKey)
+	cookies.EraseSessionCookie(ctx)
+
+	if sid == nil {
+		return
+	}
+
+	if _, err := sh.webSessions.Destroy(contex, string(sid)); err != nil {
+		sh.logger.WarnContext(contex, "Failed to destroy revoked current session",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+	}
+}
+
+// statusCode: недоступность keycloak — не повод считать пользователя разлогиненным.
+func statusCode(err error) int {
+	var statusErr *httpclient.StatusError
+
+	switch {
+	case errors.Is(err, errorvals.ErrInvalidArgument):
+		return fasthttp.StatusBadRequest
+	case errors.Is(err, errorvals.ErrTokenInvalid), errors.Is(err, errorvals.ErrTokenExpired):
+		return fasthttp.StatusUnauthorized
+	case errors.As(err, &statusErr) && statusErr.Code == fasthttp.StatusUnauthorized:
+		return fasthttp.StatusUnauthorized
+	case errors.As(err, &statusErr) && statusErr.Code == fasthttp.StatusNotFound:
+		return fasthttp.StatusNotFound
+	case errors.Is(err, errorvals.ErrUpstreamUnavailable):
+		return fasthttp.StatusServiceUnavailable
+	default:
+		return fasthttp.StatusInternalServerError
+	}
+}
+
 func (sh *Handler) RegisterRoutes(apiGroup *router.Group) {
 	g := apiGroup.Group("/session")
-	g.GET("/", sh.mw(sh.Get))
-	g.DELETE("/{id}", sh.mw(sh.Delete))
+	g.GET("/", sh.mw(sh.authz(sh.Get)))
+	g.DELETE("/", sh.mw(sh.authz(sh.DeleteAll)))
+	g.DELETE("/others", sh.mw(sh.authz(sh.DeleteOthers)))
+	g.DELETE("/client/{client}", sh.mw(sh.authz(sh.DeleteByClient)))
+	g.DELETE("/{id}", sh.mw(sh.authz(sh.Delete)))
 }
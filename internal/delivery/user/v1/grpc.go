@@ -6,6 +6,8 @@ import (
 
 	"github.com/dnonakolesax/noted-auth/internal/consts"
 	"github.com/dnonakolesax/noted-auth/internal/delivery/user/v1/proto"
+	"github.com/dnonakolesax/noted-auth/internal/grpcerr"
+	"github.com/dnonakolesax/noted-auth/internal/model"
 )
 
 type Server struct {
@@ -23,26 +25,71 @@ func NewUserServer(userUsecase usecase, logger *slog.Logger) *Server {
 }
 
 func (us *Server) GetUserCtx(ctx context.Context, req *proto.UserId) (*proto.UserInfo, error) {
-	traceID, ok := ctx.Value("ReqId").(string)
-
-	if !ok {
-		us.logger.ErrorContext(ctx, "Couldn't cast trace id to string", slog.Any("traceID", traceID))
+	if req.GetUuid() == "" {
+		return nil, grpcerr.InvalidArgument("uuid", "uuid is empty")
 	}
 
-	trace := slog.String(consts.TraceLoggerKey, traceID)
-	contex := context.WithValue(context.Background(), consts.TraceContextKey, trace)
-	user, err := us.userUsecase.Get(contex, req.GetUuid())
+	// trace id кладёт в контекст интерсептор interceptors.UnaryTrace
+	user, err := us.userUsecase.Get(ctx, req.GetUuid())
 
 	if err != nil {
 		us.logger.ErrorContext(ctx, "Error getting user", slog.String(consts.ErrorLoggerKey, err.Error()))
 		return nil, err
 	}
 
-	uinfo := &proto.UserInfo{
-		Login:     user.Login,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
+	return toUserInfo(user), nil
+}
+
+func toUserInfo(user model.User) *proto.UserInfo {
+	return &proto.UserInfo{
+		Login:            user.Login,
+		FirstName:        user.FirstName,
+		LastName:         user.LastName,
+		Email:            user.Email,
+		EmailVerified:    user.EmailVerified,
+		Enabled:          user.Enabled,
+		CreatedTimestamp: user.CreatedTimestamp,
+		Attributes:       user.Attributes,
+	}
+}
+
+// BatchGetUsers и SearchUsers отдают справочник реалма целиком, поэтому вызывать их могут
+// только сервисы Noted — это проверяет interceptors.ServiceAuth.
+func (us *Server) BatchGetUsers(ctx context.Context, req *proto.UserIds) (*proto.UsersBatch, error) {
+	batch, err := us.userUsecase.GetBatch(ctx, req.GetUuids())
+
+	if err != nil {
+		us.logger.ErrorContext(ctx, "Error getting users batch", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	users := make(map[string]*proto.UserInfo, len(batch.Users))
+
+	for id, user := range batch.Users {
+		users[id] = toUserInfo(user)
+	}
+
+	return &proto.UsersBatch{Users: users, Missing: batch.Missing}, nil
+}
+
+func (us *Server) SearchUsers(ctx context.Context, req *proto.SearchUsersRequest) (*proto.UsersPage, error) {
+	page, err := us.userUsecase.Search(ctx, req.GetQuery(), int(req.GetLimit()), req.GetCursor())
+
+	if err != nil {
+		us.logger.ErrorContext(ctx, "Error searching users", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	users := make([]*proto.FoundUser, 0, len(page.Users))
+
+	for _, user := range page.Users {
+		users = append(users, &proto.FoundUser{
+			Uuid:      user.ID,
+			Login:     user.Login,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+		})
 	}
 
-	return uinfo, nil
+	return &proto.UsersPage{Users: users, NextCursor: page.NextCursor}, nil
 }
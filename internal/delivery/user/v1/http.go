@@ -2,32 +2,40 @@ package user
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/fasthttp/router"
 	"github.com/valyala/fasthttp"
 
 	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
 	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
 )
 
 type usecase interface {
 	Get(ctx context.Context, uuid string) (model.User, error)
 	GetByUsername(ctx context.Context, username string) (model.UserID, error)
+	GetBatch(ctx context.Context, uuids []string) (model.UsersBatch, error)
+	Search(ctx context.Context, query string, limit int, cursor string) (model.UsersPage, error)
 }
 
 type Handler struct {
 	userUsecase usecase
 	logger      *slog.Logger
 	mw          func(h fasthttp.RequestHandler) fasthttp.RequestHandler
+	authz       func(h fasthttp.RequestHandler) fasthttp.RequestHandler
 }
 
 func NewUserHandler(userUsecase usecase, logger *slog.Logger,
-	mwFunc func(h fasthttp.RequestHandler) fasthttp.RequestHandler) *Handler {
+	mwFunc func(h fasthttp.RequestHandler) fasthttp.RequestHandler,
+	authzFunc func(h fasthttp.RequestHandler) fasthttp.RequestHandler) *Handler {
 	return &Handler{
 		userUsecase: userUsecase,
 		logger:      logger,
 		mw:          mwFunc,
+		authz:       authzFunc,
 	}
 }
 
@@ -43,7 +51,7 @@ func NewUserHandler(userUsecase usecase, logger *slog.Logger,
 // @Router /users/{id} [get].
 func (uh *Handler) Get(ctx *fasthttp.RequestCtx) { //nolint:dupl // later
 	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
-	contex := context.WithValue(context.Background(), consts.TraceContextKey, trace)
+	contex := principal.Attach(context.WithValue(context.Background(), consts.TraceContextKey, trace), ctx)
 	userID := ctx.UserValue("id")
 
 	if userID == nil {
@@ -81,19 +89,122 @@ func (uh *Handler) Get(ctx *fasthttp.RequestCtx) { //nolint:dupl // later
 	ctx.Response.SetStatusCode(fasthttp.StatusOK)
 }
 
-func (uh *Handler) Self(ctx *fasthttp.RequestCtx) {
+// Batch godoc
+// @Summary Get users info by ids
+// @Description Returns users keyed by id; ids that do not exist are listed in missing
+// @Tags openid-connect
+// @Accept json
+// @Param request body model.UsersBatchRequest true "User IDs"
+// @Produces json
+// @Success 200 {object} model.UsersBatch
+// @Failure 400
+// @Failure 500
+// @Router /users/batch [post].
+func (uh *Handler) Batch(ctx *fasthttp.RequestCtx) {
 	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
-	contex := context.WithValue(context.Background(), consts.TraceContextKey, trace)
+	contex := principal.Attach(context.WithValue(context.Background(), consts.TraceContextKey, trace), ctx)
+
+	var req model.UsersBatchRequest
+	err := req.UnmarshalJSON(ctx.Request.Body())
 
-	id := ctx.UserValue(consts.CtxUserIDKey)
+	if err != nil {
+		uh.logger.WarnContext(contex, "could not unmarshal users batch request",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.Response.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
 
-	userID, ok := id.(string)
+	batch, err := uh.userUsecase.GetBatch(contex, req.IDs)
 
-	if !ok {
-		uh.logger.WarnContext(contex, "self: fail casting id to string", slog.Any("user_id", userID))
+	if err != nil {
+		uh.logger.WarnContext(contex, "could not get users", slog.String(consts.ErrorLoggerKey, err.Error()))
+		if errors.Is(err, errorvals.ErrInvalidArgument) {
+			ctx.Response.SetStatusCode(fasthttp.StatusBadRequest)
+			return
+		}
+		ctx.Response.SetStatusCode(fasthttp.StatusInternalServerError)
+		return
+	}
+
+	batchJSON, err := batch.MarshalJSON()
+
+	if err != nil {
+		uh.logger.ErrorContext(contex, "could not marshal users", slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.Response.SetStatusCode(fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.Response.SetBody(batchJSON)
+	ctx.Response.Header.Set(fasthttp.HeaderContentType, consts.ApplicationJSONContentType)
+	ctx.Response.SetStatusCode(fasthttp.StatusOK)
+}
+
+// Search godoc
+// @Summary Search users by prefix
+// @Description Case-insensitive prefix search by username, email, first and last name, ordered by username
+// @Tags openid-connect
+// @Param q query string true "Prefix"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Produces json
+// @Success 200 {object} model.UsersPage
+// @Failure 400
+// @Failure 500
+// @Router /users/search [get].
+func (uh *Handler) Search(ctx *fasthttp.RequestCtx) {
+	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
+	contex := principal.Attach(context.WithValue(context.Background(), consts.TraceContextKey, trace), ctx)
+	args := ctx.QueryArgs()
+
+	limit := 0
+	if args.Has("limit") {
+		var err error
+		limit, err = args.GetUint("limit")
+
+		if err != nil {
+			uh.logger.WarnContext(contex, "invalid search limit", slog.String(consts.ErrorLoggerKey, err.Error()))
+			ctx.Response.SetStatusCode(fasthttp.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := uh.userUsecase.Search(contex, string(args.Peek("q")), limit, string(args.Peek("cursor")))
+
+	if err != nil {
+		uh.logger.WarnContext(contex, "could not search users", slog.String(consts.ErrorLoggerKey, err.Error()))
+		if errors.Is(err, errorvals.ErrInvalidArgument) {
+			ctx.Response.SetStatusCode(fasthttp.StatusBadRequest)
+			return
+		}
+		ctx.Response.SetStatusCode(fasthttp.StatusInternalServerError)
+		return
+	}
+
+	pageJSON, err := page.MarshalJSON()
+
+	if err != nil {
+		uh.logger.ErrorContext(contex, "could not marshal users", slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.Response.SetStatusCode(fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.Response.SetBody(pageJSON)
+	ctx.Response.Header.Set(fasthttp.HeaderContentType, consts.ApplicationJSONContentType)
+	ctx.Response.SetStatusCode(fasthttp.StatusOK)
+}
+
+func (uh *Handler) Self(ctx *fasthttp.RequestCtx) {
+	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
+	contex := principal.Attach(context.WithValue(context.Background(), consts.TraceContextKey, trace), ctx)
+
+	p, ok := principal.FromContext(contex)
+
+	if !ok || p.Subject == "" {
+		uh.logger.WarnContext(contex, "self: no principal in context")
 		ctx.Response.SetStatusCode(fasthttp.StatusUnauthorized)
 		return
 	}
+	userID := p.Subject
 
 	user, err := uh.userUsecase.Get(contex, userID)
 
@@ -120,7 +231,7 @@ func (uh *Handler) Self(ctx *fasthttp.RequestCtx) {
 
 func (uh *Handler) GetByName(ctx *fasthttp.RequestCtx) { //nolint:dupl // later
 	trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
-	contex := context.WithValue(context.Background(), consts.TraceContextKey, trace)
+	contex := principal.Attach(context.WithValue(context.Background(), consts.TraceContextKey, trace), ctx)
 	userName := ctx.UserValue("name")
 
 	if userName == nil {
@@ -160,7 +271,9 @@ func (uh *Handler) GetByName(ctx *fasthttp.RequestCtx) { //nolint:dupl // later
 
 func (uh *Handler) RegisterRoutes(apiGroup *router.Group) {
 	group := apiGroup.Group("/users")
-	group.GET("/{id}", uh.mw(uh.Get))
-	group.GET("/name/{name}", uh.mw(uh.GetByName))
-	group.GET("/self", uh.mw(uh.Self))
+	group.GET("/{id}", uh.mw(uh.authz(uh.Get)))
+	group.GET("/name/{name}", uh.mw(uh.authz(uh.GetByName)))
+	group.GET("/self", uh.mw(uh.authz(uh.Self)))
+	group.POST("/batch", uh.mw(uh.authz(uh.Batch)))
+	group.GET("/search", uh.mw(uh.authz(uh.Search)))
 }
@@ -65,13 +65,19 @@ func (x *UserId) GetUuid() string {
 	return ""
 }
 
+// CreatedTimestamp - unix ms, Attributes - атрибуты keycloak из service.user-attributes
 type UserInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Login         string                 `protobuf:"bytes,1,opt,name=Login,proto3" json:"Login,omitempty"`
-	FirstName     string                 `protobuf:"bytes,2,opt,name=FirstName,proto3" json:"FirstName,omitempty"`
-	LastName      string                 `protobuf:"bytes,3,opt,name=LastName,proto3" json:"LastName,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Login            string                 `protobuf:"bytes,1,opt,name=Login,proto3" json:"Login,omitempty"`
+	FirstName        string                 `protobuf:"bytes,2,opt,name=FirstName,proto3" json:"FirstName,omitempty"`
+	LastName         string                 `protobuf:"bytes,3,opt,name=LastName,proto3" json:"LastName,omitempty"`
+	Email            string                 `protobuf:"bytes,4,opt,name=Email,proto3" json:"Email,omitempty"`
+	EmailVerified    bool                   `protobuf:"varint,5,opt,name=EmailVerified,proto3" json:"EmailVerified,omitempty"`
+	Enabled          bool                   `protobuf:"varint,6,opt,name=Enabled,proto3" json:"Enabled,omitempty"`
+	CreatedTimestamp int64                  `protobuf:"varint,7,opt,name=CreatedTimestamp,proto3" json:"CreatedTimestamp,omitempty"`
+	Attributes       map[string]string      `protobuf:"bytes,8,rep,name=Attributes,proto3" json:"Attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *UserInfo) Reset() {
@@ -125,6 +131,320 @@ func (x *UserInfo) GetLastName() string {
 	return ""
 }
 
+func (x *UserInfo) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserInfo) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+func (x *UserInfo) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *UserInfo) GetCreatedTimestamp() int64 {
+	if x != nil {
+		return x.CreatedTimestamp
+	}
+	return 0
+}
+
+func (x *UserInfo) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type UserIds struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuids         []string               `protobuf:"bytes,1,rep,name=uuids,proto3" json:"uuids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserIds) Reset() {
+	*x = UserIds{}
+	mi := &file_user_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserIds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserIds) ProtoMessage() {}
+
+func (x *UserIds) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserIds.ProtoReflect.Descriptor instead.
+func (*UserIds) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UserIds) GetUuids() []string {
+	if x != nil {
+		return x.Uuids
+	}
+	return nil
+}
+
+// missing - id, которых нет в реалме; отсутствие пользователя не является ошибкой батча
+type UsersBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         map[string]*UserInfo   `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Missing       []string               `protobuf:"bytes,2,rep,name=missing,proto3" json:"missing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsersBatch) Reset() {
+	*x = UsersBatch{}
+	mi := &file_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsersBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsersBatch) ProtoMessage() {}
+
+func (x *UsersBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsersBatch.ProtoReflect.Descriptor instead.
+func (*UsersBatch) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UsersBatch) GetUsers() map[string]*UserInfo {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *UsersBatch) GetMissing() []string {
+	if x != nil {
+		return x.Missing
+	}
+	return nil
+}
+
+// cursor - next_cursor предыдущей страницы, limit 0 - размер страницы по умолчанию
+type SearchUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersRequest) Reset() {
+	*x = SearchUsersRequest{}
+	mi := &file_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersRequest) ProtoMessage() {}
+
+func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
+func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SearchUsersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchUsersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type FoundUser struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Login         string                 `protobuf:"bytes,2,opt,name=Login,proto3" json:"Login,omitempty"`
+	FirstName     string                 `protobuf:"bytes,3,opt,name=FirstName,proto3" json:"FirstName,omitempty"`
+	LastName      string                 `protobuf:"bytes,4,opt,name=LastName,proto3" json:"LastName,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FoundUser) Reset() {
+	*x = FoundUser{}
+	mi := &file_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FoundUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FoundUser) ProtoMessage() {}
+
+func (x *FoundUser) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FoundUser.ProtoReflect.Descriptor instead.
+func (*FoundUser) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FoundUser) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *FoundUser) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *FoundUser) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *FoundUser) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+// next_cursor пуст на последней странице
+type UsersPage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*FoundUser           `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsersPage) Reset() {
+	*x = UsersPage{}
+	mi := &file_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsersPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsersPage) ProtoMessage() {}
+
+func (x *UsersPage) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsersPage.ProtoReflect.Descriptor instead.
+func (*UsersPage) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UsersPage) GetUsers() []*FoundUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *UsersPage) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 var File_user_proto protoreflect.FileDescriptor
 
 const file_user_proto_rawDesc = "" +
@@ -132,14 +452,49 @@ const file_user_proto_rawDesc = "" +
 	"\n" +
 	"user.proto\x12\x04user\"\x1c\n" +
 	"\x06UserId\x12\x12\n" +
-	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"Z\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"\xdb\x02\n" +
 	"\bUserInfo\x12\x14\n" +
 	"\x05Login\x18\x01 \x01(\tR\x05Login\x12\x1c\n" +
 	"\tFirstName\x18\x02 \x01(\tR\tFirstName\x12\x1a\n" +
-	"\bLastName\x18\x03 \x01(\tR\bLastName2;\n" +
+	"\bLastName\x18\x03 \x01(\tR\bLastName\x12\x14\n" +
+	"\x05Email\x18\x04 \x01(\tR\x05Email\x12$\n" +
+	"\rEmailVerified\x18\x05 \x01(\bR\rEmailVerified\x12\x18\n" +
+	"\aEnabled\x18\x06 \x01(\bR\aEnabled\x12*\n" +
+	"\x10CreatedTimestamp\x18\a \x01(\x03R\x10CreatedTimestamp\x12>\n" +
+	"\n" +
+	"Attributes\x18\b \x03(\v2\x1e.user.UserInfo.AttributesEntryR\n" +
+	"Attributes\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x1f\n" +
+	"\aUserIds\x12\x14\n" +
+	"\x05uuids\x18\x01 \x03(\tR\x05uuids\"\xa3\x01\n" +
+	"\n" +
+	"UsersBatch\x121\n" +
+	"\x05users\x18\x01 \x03(\v2\x1b.user.UsersBatch.UsersEntryR\x05users\x12\x18\n" +
+	"\amissing\x18\x02 \x03(\tR\amissing\x1aH\n" +
+	"\n" +
+	"UsersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12$\n" +
+	"\x05value\x18\x02 \x01(\v2\x0e.user.UserInfoR\x05value:\x028\x01\"X\n" +
+	"\x12SearchUsersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"o\n" +
+	"\tFoundUser\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x14\n" +
+	"\x05Login\x18\x02 \x01(\tR\x05Login\x12\x1c\n" +
+	"\tFirstName\x18\x03 \x01(\tR\tFirstName\x12\x1a\n" +
+	"\bLastName\x18\x04 \x01(\tR\bLastName\"S\n" +
+	"\tUsersPage\x12%\n" +
+	"\x05users\x18\x01 \x03(\v2\x0f.user.FoundUserR\x05users\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor2\xab\x01\n" +
 	"\vUserService\x12,\n" +
 	"\n" +
-	"GetUserCtx\x12\f.user.UserId\x1a\x0e.user.UserInfo\"\x00B\tZ\a./;userb\x06proto3"
+	"GetUserCtx\x12\f.user.UserId\x1a\x0e.user.UserInfo\"\x00\x122\n" +
+	"\rBatchGetUsers\x12\r.user.UserIds\x1a\x10.user.UsersBatch\"\x00\x12:\n" +
+	"\vSearchUsers\x12\x18.user.SearchUsersRequest\x1a\x0f.user.UsersPage\"\x00B\tZ\a./;userb\x06proto3"
 
 var (
 	file_user_proto_rawDescOnce sync.Once
@@ -153,19 +508,34 @@ func file_user_proto_rawDescGZIP() []byte {
 	return file_user_proto_rawDescData
 }
 
-var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_user_proto_goTypes = []any{
-	(*UserId)(nil),   // 0: user.UserId
-	(*UserInfo)(nil), // 1: user.UserInfo
+	(*UserId)(nil),             // 0: user.UserId
+	(*UserInfo)(nil),           // 1: user.UserInfo
+	(*UserIds)(nil),            // 2: user.UserIds
+	(*UsersBatch)(nil),         // 3: user.UsersBatch
+	(*SearchUsersRequest)(nil), // 4: user.SearchUsersRequest
+	(*FoundUser)(nil),          // 5: user.FoundUser
+	(*UsersPage)(nil),          // 6: user.UsersPage
+	nil,                        // 7: user.UserInfo.AttributesEntry
+	nil,                        // 8: user.UsersBatch.UsersEntry
 }
 var file_user_proto_depIdxs = []int32{
-	0, // 0: user.UserService.GetUserCtx:input_type -> user.UserId
-	1, // 1: user.UserService.GetUserCtx:output_type -> user.UserInfo
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	7, // 0: user.UserInfo.Attributes:type_name -> user.UserInfo.AttributesEntry
+	8, // 1: user.UsersBatch.users:type_name -> user.UsersBatch.UsersEntry
+	5, // 2: user.UsersPage.users:type_name -> user.FoundUser
+	1, // 3: user.UsersBatch.UsersEntry.value:type_name -> user.UserInfo
+	0, // 4: user.UserService.GetUserCtx:input_type -> user.UserId
+	2, // 5: user.UserService.BatchGetUsers:input_type -> user.UserIds
+	4, // 6: user.UserService.SearchUsers:input_type -> user.SearchUsersRequest
+	1, // 7: user.UserService.GetUserCtx:output_type -> user.UserInfo
+	3, // 8: user.UserService.BatchGetUsers:output_type -> user.UsersBatch
+	6, // 9: user.UserService.SearchUsers:output_type -> user.UsersPage
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_user_proto_init() }
@@ -179,7 +549,7 @@ func file_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_proto_rawDesc), len(file_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,14 +19,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_GetUserCtx_FullMethodName = "/user.UserService/GetUserCtx"
+	UserService_GetUserCtx_FullMethodName    = "/user.UserService/GetUserCtx"
+	UserService_BatchGetUsers_FullMethodName = "/user.UserService/BatchGetUsers"
+	UserService_SearchUsers_FullMethodName   = "/user.UserService/SearchUsers"
 )
 
 // UserServiceClient is the client API for UserService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BatchGetUsers и SearchUsers доступны только сервисам Noted: клиентский сертификат mTLS
+// или общий секрет в метаданных x-service-secret.
 type UserServiceClient interface {
 	GetUserCtx(ctx context.Context, in *UserId, opts ...grpc.CallOption) (*UserInfo, error)
+	BatchGetUsers(ctx context.Context, in *UserIds, opts ...grpc.CallOption) (*UsersBatch, error)
+	SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*UsersPage, error)
 }
 
 type userServiceClient struct {
@@ -47,11 +54,36 @@ func (c *userServiceClient) GetUserCtx(ctx context.Context, in *UserId, opts ...
 	return out, nil
 }
 
+func (c *userServiceClient) BatchGetUsers(ctx context.Context, in *UserIds, opts ...grpc.CallOption) (*UsersBatch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UsersBatch)
+	err := c.cc.Invoke(ctx, UserService_BatchGetUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*UsersPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UsersPage)
+	err := c.cc.Invoke(ctx, UserService_SearchUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
+//
+// BatchGetUsers и SearchUsers доступны только сервисам Noted: клиентский сертификат mTLS
+// или общий секрет в метаданных x-service-secret.
 type UserServiceServer interface {
 	GetUserCtx(context.Context, *UserId) (*UserInfo, error)
+	BatchGetUsers(context.Context, *UserIds) (*UsersBatch, error)
+	SearchUsers(context.Context, *SearchUsersRequest) (*UsersPage, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -65,6 +97,12 @@ type UnimplementedUserServiceServer struct{}
 func (UnimplementedUserServiceServer) GetUserCtx(context.Context, *UserId) (*UserInfo, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserCtx not implemented")
 }
+func (UnimplementedUserServiceServer) BatchGetUsers(context.Context, *UserIds) (*UsersBatch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetUsers not implemented")
+}
+func (UnimplementedUserServiceServer) SearchUsers(context.Context, *SearchUsersRequest) (*UsersPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchUsers not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -104,6 +142,42 @@ func _UserService_GetUserCtx_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_BatchGetUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIds)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).BatchGetUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_BatchGetUsers_FullMethodName,
+	}
+	handler := func(cntxt context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).BatchGetUsers(cntxt, req.(*UserIds))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SearchUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SearchUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SearchUsers_FullMethodName,
+	}
+	handler := func(cntxt context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SearchUsers(cntxt, req.(*SearchUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +189,14 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserCtx",
 			Handler:    _UserService_GetUserCtx_Handler,
 		},
+		{
+			MethodName: "BatchGetUsers",
+			Handler:    _UserService_BatchGetUsers_Handler,
+		},
+		{
+			MethodName: "SearchUsers",
+			Handler:    _UserService_SearchUsers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user.proto",
@@ -9,3 +9,46 @@ import (
 // errors.Is для определения статуса http ответа
 
 var ErrObjectNotFoundInRepoError = errors.New("object not found in repo")
+
+// ErrStateReplayed — state уже был использован: повторный callback с тем же state.
+var ErrStateReplayed = errors.New("state already consumed")
+
+var (
+	ErrTokenExpired    = errors.New("token expired")
+	ErrTokenInvalid    = errors.New("token invalid")
+	ErrJWKSUnavailable = errors.New("jwks unavailable")
+	// ErrIDTokenInvalid — id token из ответа /token не прошёл проверку (подпись, aud, iss, azp, nonce).
+	ErrIDTokenInvalid = errors.New("id token invalid")
+	// ErrRefreshTokenReused — предъявлен уже обменянный refresh token, сессия отозвана.
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
+var (
+	// ErrSealedDataInvalid — зашифрованные данные повреждены или подделаны.
+	ErrSealedDataInvalid = errors.New("sealed data invalid")
+	// ErrUnknownSealKey — данные зашифрованы ключом, которого нет в наборе (или вообще не зашифрованы).
+	ErrUnknownSealKey = errors.New("unknown seal key")
+)
+
+var (
+	// ErrUpstreamUnavailable — keycloak не ответил или ответил 5xx.
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrInvalidArgument — запрос не прошёл валидацию.
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrTooManyRows     = errors.New("too many rows")
+)
+
+// ErrClientCertRejected — клиентский сертификат mTLS не подписан client CA или его SAN не в списке разрешённых.
+var ErrClientCertRejected = errors.New("client certificate rejected")
+
+var (
+	// ErrCallerUnauthenticated — сервис не предъявил ни клиентский сертификат mTLS, ни верный общий секрет.
+	ErrCallerUnauthenticated = errors.New("caller unauthenticated")
+	// ErrAudienceNotAllowed — токен запрошен для сервиса не из service-token.audiences
+	// (или token-exchange.audiences для обмена токена).
+	ErrAudienceNotAllowed = errors.New("audience not allowed")
+	// ErrTokenNotOwned — пользователь пытается отозвать чужой токен.
+	ErrTokenNotOwned = errors.New("token not owned by caller")
+	// ErrTokenExchangeDisabled — обмен токена выключен (token-exchange.enabled).
+	ErrTokenExchangeDisabled = errors.New("token exchange disabled")
+)
@@ -0,0 +1,87 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+)
+
+// errorDomain — домен в errdetails.ErrorInfo.
+const errorDomain = "auth.noted"
+
+type mapping struct {
+	target error
+	code   codes.Code
+	reason string
+}
+
+// mappings проверяются по порядку, первая подходящая побеждает.
+//
+//nolint:gochecknoglobals // таблица соответствий
+var mappings = []mapping{
+	{errorvals.ErrObjectNotFoundInRepoError, codes.NotFound, "NOT_FOUND"},
+	{errorvals.ErrInvalidArgument, codes.InvalidArgument, "INVALID_ARGUMENT"},
+	{errorvals.ErrTokenExpired, codes.Unauthenticated, "TOKEN_EXPIRED"},
+	{errorvals.ErrTokenInvalid, codes.Unauthenticated, "TOKEN_INVALID"},
+	{errorvals.ErrRefreshTokenReused, codes.Unauthenticated, "REFRESH_TOKEN_REUSED"},
+	{errorvals.ErrSealedDataInvalid, codes.Unauthenticated, "SESSION_INVALID"},
+	{errorvals.ErrUnknownSealKey, codes.Unauthenticated, "SESSION_INVALID"},
+	{errorvals.ErrCallerUnauthenticated, codes.Unauthenticated, "CALLER_UNAUTHENTICATED"},
+	{errorvals.ErrAudienceNotAllowed, codes.PermissionDenied, "AUDIENCE_NOT_ALLOWED"},
+	{errorvals.ErrTokenNotOwned, codes.PermissionDenied, "TOKEN_NOT_OWNED"},
+	{errorvals.ErrTokenExchangeDisabled, codes.Unimplemented, "TOKEN_EXCHANGE_DISABLED"},
+	{errorvals.ErrUpstreamUnavailable, codes.Unavailable, "KEYCLOAK_UNAVAILABLE"},
+	{errorvals.ErrJWKSUnavailable, codes.Unavailable, "KEYCLOAK_UNAVAILABLE"},
+	{context.DeadlineExceeded, codes.DeadlineExceeded, "DEADLINE_EXCEEDED"},
+	{context.Canceled, codes.Canceled, "CANCELED"},
+}
+
+// FromError переводит ошибку из errorvals в status с errdetails.ErrorInfo. Ошибки, уже
+// являющиеся status, возвращаются как есть; неизвестные — codes.Internal без текста ошибки,
+// чтобы не раскрывать внутреннюю логику клиенту.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	for _, m := range mappings {
+		if errors.Is(err, m.target) {
+			return withInfo(status.New(m.code, m.target.Error()), m.reason)
+		}
+	}
+
+	return withInfo(status.New(codes.Internal, "internal error"), "INTERNAL")
+}
+
+// InvalidArgument — ошибка валидации поля запроса с errdetails.BadRequest.
+func InvalidArgument(field string, description string) error {
+	st := status.New(codes.InvalidArgument, field+": "+description)
+	detailed, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}},
+	})
+
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+func withInfo(st *status.Status, reason string) error {
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain})
+
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
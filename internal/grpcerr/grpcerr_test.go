@@ -0,0 +1,65 @@
+package grpcerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+)
+
+func TestFromError_Codes(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		err  error
+		code codes.Code
+	}{
+		{fmt.Errorf("%w: user 1", errorvals.ErrObjectNotFoundInRepoError), codes.NotFound},
+		{fmt.Errorf("%w: token is not active", errorvals.ErrTokenInvalid), codes.Unauthenticated},
+		{errorvals.ErrTokenExpired, codes.Unauthenticated},
+		{fmt.Errorf("%w: resp status code: 503", errorvals.ErrUpstreamUnavailable), codes.Unavailable},
+		{errorvals.ErrInvalidArgument, codes.InvalidArgument},
+		{errorvals.ErrCallerUnauthenticated, codes.Unauthenticated},
+		{fmt.Errorf("%w: billing", errorvals.ErrAudienceNotAllowed), codes.PermissionDenied},
+		{errorvals.ErrTokenNotOwned, codes.PermissionDenied},
+		{errorvals.ErrTokenExchangeDisabled, codes.Unimplemented},
+		{errors.New("pgx: conn closed"), codes.Internal},
+		{status.Error(codes.PermissionDenied, "nope"), codes.PermissionDenied},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.code, status.Code(FromError(c.err)), c.err.Error())
+	}
+
+	require.NoError(t, FromError(nil))
+}
+
+func TestFromError_DetailsDoNotLeakInternals(t *testing.T) {
+	t.Parallel()
+
+	st := status.Convert(FromError(errors.New("pgx: password authentication failed")))
+	require.Equal(t, "internal error", st.Message())
+
+	st = status.Convert(FromError(fmt.Errorf("%w: user 1", errorvals.ErrObjectNotFoundInRepoError)))
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	require.Equal(t, "NOT_FOUND", info.GetReason())
+}
+
+func TestInvalidArgument(t *testing.T) {
+	t.Parallel()
+
+	st := status.Convert(InvalidArgument("uuid", "uuid is empty"))
+	require.Equal(t, codes.InvalidArgument, st.Code())
+
+	br, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Equal(t, "uuid", br.GetFieldViolations()[0].GetField())
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/dnonakolesax/noted-auth/internal/configs"
 	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
 	"github.com/dnonakolesax/noted-auth/internal/metrics"
 )
 
@@ -42,10 +43,26 @@ type HTTPRequestParams struct {
 	body      string
 	token     string
 	pathParam string
+	// noPath — запрос на сам endpoint, без "/" и pathParam.
+	noPath bool
+	query  url.Values
+	// contentType — если пустой, выбирается по методу.
+	contentType string
 }
 
-func NewWithRetry(endpoint string, config *configs.HTTPClientConfig,
-	reqMetrics *metrics.HTTPRequestMetrics, alive *atomic.Bool, logger *slog.Logger) (*HTTPClient, error) {
+// StatusError — keycloak ответил 4xx; код нужен вызывающим, которые различают 401 и 404.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("resp status code: %d", e.Code)
+}
+
+// New создаёт клиент без проверки endpoint. Нужен там, где HEAD на endpoint не отвечает 405
+// (например, admin API keycloak отвечает 401 без токена).
+func New(endpoint string, config *configs.HTTPClientConfig,
+	reqMetrics *metrics.HTTPRequestMetrics, alive *atomic.Bool, logger *slog.Logger) *HTTPClient {
 	tr := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
@@ -57,7 +74,7 @@ func NewWithRetry(endpoint string, config *configs.HTTPClientConfig,
 		IdleConnTimeout:     config.IdleConnTimeout,
 	}
 
-	c := &HTTPClient{
+	return &HTTPClient{
 		c:        &http.Client{Transport: tr, Timeout: config.RequestTimeout},
 		endpoint: endpoint,
 		retries:  config.RetryPolicy,
@@ -65,6 +82,11 @@ func NewWithRetry(endpoint string, config *configs.HTTPClientConfig,
 		logger:   logger,
 		Alive:    alive,
 	}
+}
+
+func NewWithRetry(endpoint string, config *configs.HTTPClientConfig,
+	reqMetrics *metrics.HTTPRequestMetrics, alive *atomic.Bool, logger *slog.Logger) (*HTTPClient, error) {
+	c := New(endpoint, config, reqMetrics, alive, logger)
 
 	ctx, cancel := context.WithTimeout(context.Background(), config.RequestTimeout)
 	defer cancel()
@@ -109,6 +131,18 @@ func (hc *HTTPClient) Delete(ctx context.Context, token string, id string) (*htt
 	return hc.makeRequest(ctx, http.MethodDelete, HTTPRequestParams{token: token, pathParam: id})
 }
 
+// DeleteAll — DELETE на сам endpoint (коллекцию), а не на её элемент.
+func (hc *HTTPClient) DeleteAll(ctx context.Context, token string, query url.Values) (*http.Response, error) {
+	return hc.makeRequest(ctx, http.MethodDelete, HTTPRequestParams{token: token, noPath: true, query: query})
+}
+
+// Do выполняет запрос с JSON телом на endpoint/path; body может быть nil.
+func (hc *HTTPClient) Do(ctx context.Context, method string, token string, path string,
+	body []byte) (*http.Response, error) {
+	return hc.makeRequest(ctx, method, HTTPRequestParams{body: string(body), token: token, pathParam: path,
+		contentType: "application/json"})
+}
+
 func (hc *HTTPClient) makeRequest(ctx context.Context, method string,
 	params HTTPRequestParams) (*http.Response, error) {
 	var lastErr error
@@ -130,11 +164,20 @@ func (hc *HTTPClient) makeRequest(ctx context.Context, method string,
 	}
 
 	if resp == nil {
-		return nil, fmt.Errorf("request failed after %d attempts", hc.retries.MaxAttempts)
+		return nil, fmt.Errorf("%w: request failed after %d attempts", errorvals.ErrUpstreamUnavailable,
+			hc.retries.MaxAttempts)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		drainAndClose(resp.Body)
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("%w: resp status code: %d", errorvals.ErrUpstreamUnavailable, resp.StatusCode)
 	}
 
 	if resp.StatusCode >= http.StatusBadRequest {
-		return nil, fmt.Errorf("resp status code: %d", resp.StatusCode)
+		return nil, &StatusError{Code: resp.StatusCode}
 	}
 	return resp, lastErr
 }
@@ -178,18 +221,27 @@ func (hc *HTTPClient) executeRequestAttempt(ctx context.Context, method string,
 
 func (hc *HTTPClient) createRequest(ctx context.Context, method string,
 	params HTTPRequestParams) (*http.Request, error) {
-	req, err := http.NewRequestWithContext(ctx, method,
-		fmt.Sprintf("%s%s%s", hc.endpoint, HTTPPathDelimeter, params.pathParam),
-		strings.NewReader(params.body))
+	target := hc.endpoint
+	if !params.noPath {
+		target = fmt.Sprintf("%s%s%s", hc.endpoint, HTTPPathDelimeter, params.pathParam)
+	}
+	if len(params.query) > 0 {
+		target += "?" + params.query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, strings.NewReader(params.body))
 
 	if err != nil {
 		hc.logger.ErrorContext(ctx, "Error creating http-request", slog.String(consts.ErrorLoggerKey, err.Error()))
 		return nil, err
 	}
 
-	if method == "GET" || method == "DELETE" {
+	switch {
+	case params.contentType != "":
+		req.Header.Set(HTTPHeaderContentType, params.contentType)
+	case method == "GET" || method == "DELETE":
 		req.Header.Set(HTTPHeaderContentType, "application/json")
-	} else {
+	default:
 		req.Header.Set(HTTPHeaderContentType, HTTPHeaderContentTypeURLEncoded)
 	}
 	if params.token != consts.EmptyString {
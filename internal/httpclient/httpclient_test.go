@@ -227,6 +227,34 @@ func TestDelete_AppendsPathParam(t *testing.T) {
 	require.Equal(t, "Bearer tok", gotAuth)
 }
 
+func TestDeleteAll_HitsEndpointWithQuery(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	var gotQuery string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	hc, _ := newClientForServer(t, srv.URL+"/account/sessions",
+		configs.HTTPRetryPolicyConfig{MaxAttempts: 1, RetryOnStatus: map[int]bool{}})
+
+	resp, err := hc.DeleteAll(context.Background(), "tok", url.Values{"current": {"true"}})
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.Equal(t, "/account/sessions", gotPath)
+	require.Equal(t, "current=true", gotQuery)
+}
+
 func TestGet_ReturnsError_On4xx(t *testing.T) {
 	t.Parallel()
 
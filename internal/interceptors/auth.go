@@ -0,0 +1,89 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/grpcerr"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
+)
+
+const (
+	authorizationKey = "authorization"
+	bearerScheme     = "Bearer"
+)
+
+type TokenVerifier interface {
+	VerifyAccessToken(ctx context.Context, at string) (model.IntrospectDTO, error)
+}
+
+// Auth кладёт principal.Principal в контекст, если в метаданных передан authorization: Bearer.
+// Вызовы без токена (внутренние сервисы) проходят без principal.
+type Auth struct {
+	verifier TokenVerifier
+	logger   *slog.Logger
+}
+
+func NewAuth(verifier TokenVerifier, logger *slog.Logger) *Auth {
+	return &Auth{verifier: verifier, logger: logger}
+}
+
+func (a *Auth) Unary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authenticate(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (a *Auth) Stream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, wrapStream(ss, ctx))
+}
+
+func (a *Auth) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationKey)
+
+	if len(values) == 0 {
+		return ctx, nil
+	}
+
+	scheme, at, _ := strings.Cut(values[0], " ")
+	at = strings.TrimSpace(at)
+
+	if !strings.EqualFold(scheme, bearerScheme) || at == consts.EmptyString {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	intro, err := a.verifier.VerifyAccessToken(ctx, at)
+
+	if err != nil {
+		if errors.Is(err, errorvals.ErrTokenInvalid) || errors.Is(err, errorvals.ErrTokenExpired) {
+			a.logger.WarnContext(ctx, "grpc bearer token rejected", slog.String(consts.ErrorLoggerKey, err.Error()))
+			return nil, status.Error(codes.Unauthenticated, "the access token is invalid or expired")
+		}
+		a.logger.ErrorContext(ctx, "error verifying grpc bearer token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		// недоступность keycloak/JWKS — Unavailable, чтобы клиент повторил запрос
+		return nil, grpcerr.FromError(err)
+	}
+
+	return principal.WithPrincipal(ctx, principal.FromIntrospection(intro)), nil
+}
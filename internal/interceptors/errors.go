@@ -0,0 +1,25 @@
+package interceptors
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/dnonakolesax/noted-auth/internal/grpcerr"
+)
+
+// UnaryErrors переводит доменные ошибки обработчиков в status (grpcerr.FromError),
+// иначе клиент всегда получает codes.Unknown.
+func UnaryErrors(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+
+	if err != nil {
+		return nil, grpcerr.FromError(err)
+	}
+
+	return resp, nil
+}
+
+func StreamErrors(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return grpcerr.FromError(handler(srv, ss))
+}
@@ -0,0 +1,80 @@
+package interceptors
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"github.com/dnonakolesax/noted-auth/internal/metrics"
+)
+
+const (
+	grpcTypeUnary        = "unary"
+	grpcTypeClientStream = "client_stream"
+	grpcTypeServerStream = "server_stream"
+	grpcTypeBidiStream   = "bidi_stream"
+)
+
+// ServerOptions собирает цепочку: trace -> логирование -> метрики -> ошибки -> recovery -> auth -> service auth.
+// Recovery и перевод ошибок стоят внутри логирования и метрик, чтобы те видели итоговый код.
+// Стримы service auth не проверяет: сервисные методы только unary.
+func ServerOptions(grpcMetrics *metrics.GRPCServerMetrics, auth *Auth, service *ServiceAuth,
+	logger *slog.Logger) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			UnaryTrace,
+			UnaryLogging(logger),
+			UnaryMetrics(grpcMetrics),
+			UnaryErrors,
+			UnaryRecovery(logger),
+			auth.Unary,
+			service.Unary,
+		),
+		grpc.ChainStreamInterceptor(
+			StreamTrace,
+			StreamLogging(logger),
+			StreamMetrics(grpcMetrics),
+			StreamErrors,
+			StreamRecovery(logger),
+			auth.Stream,
+		),
+	}
+}
+
+// wrappedStream подменяет контекст стрима.
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context //nolint:containedctx // единственный способ подменить контекст стрима
+}
+
+func (ws *wrappedStream) Context() context.Context {
+	return ws.ctx
+}
+
+func wrapStream(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &wrappedStream{ServerStream: ss, ctx: ctx}
+}
+
+// splitMethod разбирает "/package.Service/Method".
+func splitMethod(fullMethod string) (string, string) {
+	service, method, found := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+
+	if !found {
+		return "unknown", "unknown"
+	}
+
+	return service, method
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return grpcTypeBidiStream
+	case info.IsClientStream:
+		return grpcTypeClientStream
+	default:
+		return grpcTypeServerStream
+	}
+}
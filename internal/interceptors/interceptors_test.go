@@ -0,0 +1,154 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/metrics"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+}
+
+var testInfo = &grpc.UnaryServerInfo{FullMethod: "/auth.AuthService/AuthUserIDCtx"} //nolint:gochecknoglobals // test
+
+func TestUnaryTrace(t *testing.T) {
+	t.Parallel()
+
+	var got string
+	handler := func(ctx context.Context, _ any) (any, error) {
+		got = TraceID(ctx)
+		return nil, nil //nolint:nilnil // test
+	}
+
+	// старый ключ trace_id
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("trace_id", "abc"))
+	_, err := UnaryTrace(ctx, nil, testInfo, handler)
+	require.NoError(t, err)
+	require.Equal(t, "abc", got)
+
+	// метаданных нет вовсе — раньше здесь была паника
+	_, err = UnaryTrace(context.Background(), nil, testInfo, handler)
+	require.NoError(t, err)
+	require.NotEmpty(t, got)
+	require.NotEqual(t, "abc", got)
+}
+
+func TestUnaryRecovery_PanicToInternal(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewGRPCServerMetrics(prometheus.NewRegistry())
+	chain := func(ctx context.Context, req any, handler grpc.UnaryHandler) (any, error) {
+		return UnaryMetrics(m)(ctx, req, testInfo, func(ctx context.Context, req any) (any, error) {
+			return UnaryRecovery(testLogger())(ctx, req, testInfo, handler)
+		})
+	}
+
+	_, err := chain(context.Background(), nil, func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+	require.Equal(t, codes.Internal, status.Code(err))
+
+	require.InDelta(t, 1,
+		testutil.ToFloat64(m.Handled.WithLabelValues("unary", "auth.AuthService", "AuthUserIDCtx", "Internal")), 0)
+	require.InDelta(t, 1,
+		testutil.ToFloat64(m.Started.WithLabelValues("unary", "auth.AuthService", "AuthUserIDCtx")), 0)
+}
+
+type verifierStub struct {
+	err error
+}
+
+func (v verifierStub) VerifyAccessToken(_ context.Context, _ string) (model.IntrospectDTO, error) {
+	return model.IntrospectDTO{Active: true, Subject: "user-1"}, v.err
+}
+
+func TestAuth_Unary(t *testing.T) {
+	t.Parallel()
+
+	var p principal.Principal
+	var ok bool
+	handler := func(ctx context.Context, _ any) (any, error) {
+		p, ok = principal.FromContext(ctx)
+		return nil, nil //nolint:nilnil // test
+	}
+	withAuth := func(value string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", value))
+	}
+
+	auth := NewAuth(verifierStub{}, testLogger())
+
+	_, err := auth.Unary(context.Background(), nil, testInfo, handler)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = auth.Unary(withAuth("Bearer at"), nil, testInfo, handler)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "user-1", p.Subject)
+
+	_, err = auth.Unary(withAuth("Basic dXNlcjpwYXNz"), nil, testInfo, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	auth = NewAuth(verifierStub{err: errorvals.ErrTokenInvalid}, testLogger())
+	_, err = auth.Unary(withAuth("Bearer at"), nil, testInfo, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	auth = NewAuth(verifierStub{err: errorvals.ErrJWKSUnavailable}, testLogger())
+	_, err = auth.Unary(withAuth("Bearer at"), nil, testInfo, handler)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestServiceAuth_Unary(t *testing.T) {
+	t.Parallel()
+
+	handler := func(_ context.Context, _ any) (any, error) {
+		return "ok", nil
+	}
+	guarded := &grpc.UnaryServerInfo{FullMethod: "/auth.AuthService/GetServiceToken"}
+	withSecret := func(secret string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-service-secret", secret))
+	}
+	withClientCert := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}},
+	}})
+
+	sa := NewServiceAuth("shared", testLogger(), guarded.FullMethod)
+
+	// остальные методы не проверяются
+	_, err := sa.Unary(context.Background(), nil, testInfo, handler)
+	require.NoError(t, err)
+
+	_, err = sa.Unary(context.Background(), nil, guarded, handler)
+	require.ErrorIs(t, err, errorvals.ErrCallerUnauthenticated)
+	_, err = sa.Unary(withSecret("wrong"), nil, guarded, handler)
+	require.ErrorIs(t, err, errorvals.ErrCallerUnauthenticated)
+
+	_, err = sa.Unary(withSecret("shared"), nil, guarded, handler)
+	require.NoError(t, err)
+	_, err = sa.Unary(withClientCert, nil, guarded, handler)
+	require.NoError(t, err)
+
+	// пустой секрет — вход только по mTLS
+	sa = NewServiceAuth("", testLogger(), guarded.FullMethod)
+	_, err = sa.Unary(withSecret(""), nil, guarded, handler)
+	require.ErrorIs(t, err, errorvals.ErrCallerUnauthenticated)
+}
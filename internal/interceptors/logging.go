@@ -0,0 +1,54 @@
+package interceptors
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+)
+
+// UnaryLogging пишет те же записи, что и CommonMiddleware для HTTP.
+func UnaryLogging(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := logStart(ctx, logger, info.FullMethod)
+		resp, err := handler(ctx, req)
+		logEnd(ctx, logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func StreamLogging(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := logStart(ss.Context(), logger, info.FullMethod)
+		err := handler(srv, ss)
+		logEnd(ss.Context(), logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logStart(ctx context.Context, logger *slog.Logger, method string) time.Time {
+	logger.InfoContext(ctx, "Received Request",
+		slog.String("method", method),
+		slog.String("requestId", TraceID(ctx)),
+	)
+	return time.Now()
+}
+
+func logEnd(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) {
+	attrs := []any{
+		slog.String("method", method),
+		slog.String("requestId", TraceID(ctx)),
+		slog.String("status", status.Code(err).String()),
+		slog.Int64("duration", time.Since(start).Milliseconds()),
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String(consts.ErrorLoggerKey, err.Error()))
+	}
+
+	logger.InfoContext(ctx, "Completed request", attrs...)
+}
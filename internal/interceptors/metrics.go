@@ -0,0 +1,40 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/dnonakolesax/noted-auth/internal/metrics"
+)
+
+func UnaryMetrics(m *metrics.GRPCServerMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		done := observe(m, grpcTypeUnary, info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+		return resp, err
+	}
+}
+
+func StreamMetrics(m *metrics.GRPCServerMetrics) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		done := observe(m, streamType(info), info.FullMethod)
+		err := handler(srv, ss)
+		done(err)
+		return err
+	}
+}
+
+func observe(m *metrics.GRPCServerMetrics, grpcType string, fullMethod string) func(err error) {
+	service, method := splitMethod(fullMethod)
+	m.Started.WithLabelValues(grpcType, service, method).Inc()
+	start := time.Now()
+
+	return func(err error) {
+		m.HandlingSeconds.WithLabelValues(grpcType, service, method).Observe(time.Since(start).Seconds())
+		m.Handled.WithLabelValues(grpcType, service, method, status.Code(err).String()).Inc()
+	}
+}
@@ -0,0 +1,72 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+)
+
+const serviceSecretKey = "x-service-secret"
+
+// ServiceAuth пускает к методам methods только сервисы Noted: с клиентским сертификатом mTLS
+// (его проверка — в certs.Store.MutualConfig) или с общим секретом в метаданных x-service-secret.
+// Остальные методы не проверяются.
+type ServiceAuth struct {
+	methods map[string]bool
+	secret  atomic.Pointer[string]
+	logger  *slog.Logger
+}
+
+// NewServiceAuth — пустой secret отключает вход по секрету, остаётся только mTLS.
+func NewServiceAuth(secret string, logger *slog.Logger, methods ...string) *ServiceAuth {
+	sa := &ServiceAuth{methods: make(map[string]bool, len(methods)), logger: logger}
+	sa.secret.Store(&secret)
+
+	for _, method := range methods {
+		sa.methods[method] = true
+	}
+
+	return sa
+}
+
+func (sa *ServiceAuth) MonitorVault(vaultChan chan string) {
+	for secret := range vaultChan {
+		sa.secret.Store(&secret)
+	}
+}
+
+func (sa *ServiceAuth) Unary(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (any, error) {
+	if sa.methods[info.FullMethod] && !sa.authorized(ctx) {
+		sa.logger.WarnContext(ctx, "service caller rejected", slog.String("method", info.FullMethod))
+		return nil, errorvals.ErrCallerUnauthenticated
+	}
+
+	return handler(ctx, req)
+}
+
+func (sa *ServiceAuth) authorized(ctx context.Context) bool {
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, isTLS := p.AuthInfo.(credentials.TLSInfo); isTLS && len(tlsInfo.State.PeerCertificates) > 0 {
+			return true
+		}
+	}
+
+	secret := *sa.secret.Load()
+	if secret == "" {
+		return false
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(serviceSecretKey)
+
+	return len(values) > 0 && subtle.ConstantTimeCompare([]byte(values[0]), []byte(secret)) == 1
+}
@@ -0,0 +1,61 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/rnd"
+)
+
+const traceIDSize = 16
+
+// traceMetadataKeys — ключи метаданных с trace id в порядке приоритета. trace_id передают
+// старые клиенты AuthService.
+//
+//nolint:gochecknoglobals // константный список
+var traceMetadataKeys = []string{strings.ToLower(consts.HTTPHeaderXRequestID), "trace_id"}
+
+// withTrace кладёт trace id в контекст так же, как HTTP-обработчики (consts.TraceContextKey, строка),
+// и возвращает его клиенту в заголовке x-request-id.
+func withTrace(ctx context.Context) context.Context {
+	traceID := incomingTraceID(ctx)
+
+	if traceID == consts.EmptyString {
+		traceID = base64.RawURLEncoding.EncodeToString(rnd.NotSafeGenRandomString(traceIDSize))
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(traceMetadataKeys[0], traceID))
+
+	return context.WithValue(ctx, consts.TraceContextKey, traceID)
+}
+
+func incomingTraceID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, key := range traceMetadataKeys {
+		if values := md.Get(key); len(values) > 0 && values[0] != consts.EmptyString {
+			return values[0]
+		}
+	}
+
+	return consts.EmptyString
+}
+
+// TraceID возвращает trace id, выставленный интерсептором.
+func TraceID(ctx context.Context) string {
+	traceID, _ := ctx.Value(consts.TraceContextKey).(string)
+	return traceID
+}
+
+func UnaryTrace(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(withTrace(ctx), req)
+}
+
+func StreamTrace(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, wrapStream(ss, withTrace(ss.Context())))
+}
@@ -0,0 +1,251 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+)
+
+const (
+	keyTypeRSA = "RSA"
+	keyTypeEC  = "EC"
+	keyUseEnc  = "enc"
+	curveP256  = "P-256"
+)
+
+type jsonWebKey struct {
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+	Alg     string `json:"alg"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type publicKey struct {
+	key crypto.PublicKey
+	alg string
+}
+
+// keySet кэширует ключи реалма и перезапрашивает их по истечении ttl
+// или при появлении неизвестного kid (ротация ключей в keycloak).
+type keySet struct {
+	certsURL        string
+	client          *http.Client
+	ttl             time.Duration
+	minRefreshDelay time.Duration
+	logger          *slog.Logger
+
+	mu        sync.RWMutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+
+	fetchMu sync.Mutex
+}
+
+func newKeySet(certsURL string, client *http.Client, ttl time.Duration, minRefreshDelay time.Duration,
+	logger *slog.Logger) *keySet {
+	return &keySet{
+		certsURL:        certsURL,
+		client:          client,
+		ttl:             ttl,
+		minRefreshDelay: minRefreshDelay,
+		logger:          logger,
+		keys:            make(map[string]publicKey),
+	}
+}
+
+func (ks *keySet) get(ctx context.Context, kid string) (publicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	stale := time.Since(ks.fetchedAt) > ks.ttl
+	ks.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+
+	err := ks.refresh(ctx, kid, !ok)
+
+	if err != nil && !ok {
+		return publicKey{}, err
+	}
+
+	if err != nil {
+		// Старый ключ всё ещё известен — продолжаем работать на закэшированном наборе.
+		ks.logger.WarnContext(ctx, "Failed to refresh JWKS, using cached keys",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return key, nil
+	}
+
+	ks.mu.RLock()
+	key, ok = ks.keys[kid]
+	ks.mu.RUnlock()
+
+	if !ok {
+		return publicKey{}, fmt.Errorf("%w: unknown key id %q", errorvals.ErrTokenInvalid, kid)
+	}
+
+	return key, nil
+}
+
+// refresh перезапрашивает JWKS. Если unknownKid == true, запрос ограничивается minRefreshDelay,
+// чтобы токены с выдуманным kid не превращались в DoS на keycloak.
+func (ks *keySet) refresh(ctx context.Context, kid string, unknownKid bool) error {
+	ks.fetchMu.Lock()
+	defer ks.fetchMu.Unlock()
+
+	ks.mu.RLock()
+	sinceFetch := time.Since(ks.fetchedAt)
+	_, loaded := ks.keys[kid]
+	ks.mu.RUnlock()
+
+	if unknownKid && loaded {
+		// Ключ загрузил запрос, за которым мы ждали мьютекс (ротация или холодный старт).
+		return nil
+	}
+
+	if unknownKid && sinceFetch < ks.minRefreshDelay {
+		return fmt.Errorf("%w: unknown key id, JWKS refreshed %s ago", errorvals.ErrTokenInvalid, sinceFetch)
+	}
+
+	if !unknownKid && sinceFetch <= ks.ttl {
+		// Кто-то успел обновить набор, пока мы ждали мьютекс.
+		return nil
+	}
+
+	keys, err := ks.fetch(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.fetchedAt = time.Now()
+	ks.mu.Unlock()
+
+	ks.logger.InfoContext(ctx, "JWKS refreshed", slog.Int("keys", len(keys)))
+
+	return nil
+}
+
+func (ks *keySet) fetch(ctx context.Context) (map[string]publicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.certsURL, nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errorvals.ErrJWKSUnavailable, err)
+	}
+
+	resp, err := ks.client.Do(req)
+
+	if err != nil {
+		ks.logger.ErrorContext(ctx, "Error fetching JWKS", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, fmt.Errorf("%w: %w", errorvals.ErrJWKSUnavailable, err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		ks.logger.ErrorContext(ctx, "Unexpected JWKS response status", slog.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("%w: status %d", errorvals.ErrJWKSUnavailable, resp.StatusCode)
+	}
+
+	var set jsonWebKeySet
+	err = json.NewDecoder(resp.Body).Decode(&set)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: error decoding JWKS: %w", errorvals.ErrJWKSUnavailable, err)
+	}
+
+	keys := make(map[string]publicKey, len(set.Keys))
+
+	for _, jwk := range set.Keys {
+		if jwk.Use == keyUseEnc {
+			continue
+		}
+
+		key, parseErr := jwk.publicKey()
+
+		if parseErr != nil {
+			ks.logger.WarnContext(ctx, "Skipping unsupported JWK", slog.String("kid", jwk.KeyID),
+				slog.String(consts.ErrorLoggerKey, parseErr.Error()))
+			continue
+		}
+
+		keys[jwk.KeyID] = publicKey{key: key, alg: jwk.Alg}
+	}
+
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.KeyType {
+	case keyTypeRSA:
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding modulus: %w", err)
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() > int64(^uint32(0)>>1) {
+			return nil, errors.New("rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case keyTypeEC:
+		if jwk.Curve != curveP256 {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Curve)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding x: %w", err)
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding y: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !key.Curve.IsOnCurve(x, y) { //nolint:staticcheck // ecdh не даёт нужного нам ecdsa.PublicKey
+			return nil, errors.New("point is not on curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.KeyType)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	bts, err := base64.RawURLEncoding.DecodeString(s)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(bts), nil
+}
@@ -10,6 +10,7 @@ import (
 
 const partsInJWT = 3
 
+// ExtractSubject НЕ ПРОВЕРЯЕТ ПОДПИСЬ. Для аутентификации использовать Verifier.
 func ExtractSubject(token string) (string, error) {
 	parts := strings.Split(token, ".")
 
@@ -36,3 +37,22 @@ func ExtractSubject(token string) (string, error) {
 
 	return body.Subject, nil
 }
+
+// ExtractClaims НЕ ПРОВЕРЯЕТ ПОДПИСЬ. Нужна для токенов, которые не проверить по JWKS
+// (refresh token keycloak подписан секретом реалма).
+func ExtractClaims(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != partsInJWT {
+		return Claims{}, errors.New("invalid JWT: not 3 parts")
+	}
+
+	var claims Claims
+	err := decodeSegment(parts[1], &claims)
+
+	if err != nil {
+		return Claims{}, fmt.Errorf("error decoding jwt body: %w", err)
+	}
+
+	return claims, nil
+}
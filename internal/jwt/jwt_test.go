@@ -71,3 +71,18 @@ func TestExtractSubject_SubMissing_ReturnsEmptyStringNoError(t *testing.T) {
 	require.NoError(t, err)
 	require.Empty(t, sub)
 }
+
+func TestExtractClaims_OK(t *testing.T) {
+	t.Parallel()
+
+	token := jwtWithPayloadJSON(t, `{"sub":"user-1","sid":"sid-1","exp":42}`)
+
+	claims, err := ExtractClaims(token)
+	require.NoError(t, err)
+	require.Equal(t, "user-1", claims.Subject)
+	require.Equal(t, "sid-1", claims.SessionID)
+	require.Equal(t, int64(42), claims.ExpiresAt)
+
+	_, err = ExtractClaims("not-a-jwt")
+	require.Error(t, err)
+}
@@ -0,0 +1,264 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgPS256 = "PS256"
+	AlgES256 = "ES256"
+)
+
+const es256SignatureSize = 64
+
+type VerifierConfig struct {
+	CertsURL          string
+	ClientID          string
+	Issuer            string
+	Audiences         []string
+	AuthorizedParties []string
+	ClockSkew         time.Duration
+	JWKSCacheTTL      time.Duration
+	JWKSMinRefresh    time.Duration
+	Timeout           time.Duration
+}
+
+// Audience — claim aud, который по RFC 7519 может быть как строкой, так и массивом строк.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  Audience `json:"aud"`
+	AZP       string   `json:"azp"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	IssuedAt  int64    `json:"iat"`
+	SessionID string   `json:"sid"`
+	// SessionState — id сессии в токенах старых версий keycloak, где нет sid.
+	SessionState string `json:"session_state"`
+	Username     string `json:"preferred_username"`
+	Nonce        string `json:"nonce"`
+	// Scope, RealmAccess и ResourceAccess есть только в access token.
+	Scope          string                  `json:"scope"`
+	RealmAccess    model.Access            `json:"realm_access"`
+	ResourceAccess map[string]model.Access `json:"resource_access"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verifier проверяет подпись и стандартные claims токенов keycloak локально,
+// по ключам из JWKS реалма, без похода в /token/introspect.
+type Verifier struct {
+	keys   *keySet
+	config VerifierConfig
+	logger *slog.Logger
+}
+
+func NewVerifier(config VerifierConfig, logger *slog.Logger) *Verifier {
+	client := &http.Client{Timeout: config.Timeout}
+	return &Verifier{
+		keys:   newKeySet(config.CertsURL, client, config.JWKSCacheTTL, config.JWKSMinRefresh, logger),
+		config: config,
+		logger: logger,
+	}
+}
+
+// Verify проверяет access token. При истёкшем токене с валидной подписью возвращает
+// claims вместе с errorvals.ErrTokenExpired, чтобы вызывающий мог обновить токены.
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	var claims Claims
+
+	err := v.verify(ctx, token, &claims)
+
+	if err != nil {
+		return claims, err
+	}
+
+	return claims, v.validate(&claims, v.config.Audiences, v.config.AuthorizedParties)
+}
+
+// VerifyIDToken проверяет id token, полученный при обмене кода: подпись, iss, aud и azp
+// (оба должны быть нашим client id) и nonce из записи state. Любая ошибка оборачивается
+// в errorvals.ErrIDTokenInvalid.
+func (v *Verifier) VerifyIDToken(ctx context.Context, token string, nonce string) (Claims, error) {
+	var claims Claims
+
+	err := v.verify(ctx, token, &claims)
+
+	if err == nil {
+		clientID := []string{v.config.ClientID}
+		err = v.validate(&claims, clientID, clientID)
+	}
+
+	if err == nil && (nonce == "" || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1) {
+		err = fmt.Errorf("%w: nonce mismatch", errorvals.ErrTokenInvalid)
+	}
+
+	if err != nil {
+		return claims, fmt.Errorf("%w: %w", errorvals.ErrIDTokenInvalid, err)
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) verify(ctx context.Context, token string, claims any) error {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != partsInJWT {
+		return fmt.Errorf("%w: not %d parts", errorvals.ErrTokenInvalid, partsInJWT)
+	}
+
+	var hdr header
+	err := decodeSegment(parts[0], &hdr)
+
+	if err != nil {
+		return fmt.Errorf("%w: error decoding header: %w", errorvals.ErrTokenInvalid, err)
+	}
+
+	key, err := v.keys.get(ctx, hdr.Kid)
+
+	if err != nil {
+		return err
+	}
+
+	if key.alg != "" && key.alg != hdr.Alg {
+		return fmt.Errorf("%w: alg %q does not match key alg %q", errorvals.ErrTokenInvalid, hdr.Alg, key.alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+
+	if err != nil {
+		return fmt.Errorf("%w: error decoding signature: %w", errorvals.ErrTokenInvalid, err)
+	}
+
+	err = verifySignature(hdr.Alg, key.key, []byte(parts[0]+"."+parts[1]), signature)
+
+	if err != nil {
+		return err
+	}
+
+	err = decodeSegment(parts[1], claims)
+
+	if err != nil {
+		return fmt.Errorf("%w: error decoding body: %w", errorvals.ErrTokenInvalid, err)
+	}
+
+	return nil
+}
+
+func (v *Verifier) validate(claims *Claims, audiences []string, parties []string) error {
+	now := time.Now()
+
+	if claims.Issuer != v.config.Issuer {
+		return fmt.Errorf("%w: issuer %q mismatch", errorvals.ErrTokenInvalid, claims.Issuer)
+	}
+
+	if len(audiences) > 0 && !slices.ContainsFunc(claims.Audience, func(aud string) bool {
+		return slices.Contains(audiences, aud)
+	}) {
+		return fmt.Errorf("%w: audience %v mismatch", errorvals.ErrTokenInvalid, claims.Audience)
+	}
+
+	if len(parties) > 0 && !slices.Contains(parties, claims.AZP) {
+		return fmt.Errorf("%w: authorized party %q mismatch", errorvals.ErrTokenInvalid, claims.AZP)
+	}
+
+	if claims.NotBefore != 0 && now.Add(v.config.ClockSkew).Before(time.Unix(claims.NotBefore, 0)) {
+		return fmt.Errorf("%w: token is not valid yet", errorvals.ErrTokenInvalid)
+	}
+
+	if claims.ExpiresAt == 0 {
+		return fmt.Errorf("%w: exp claim is missing", errorvals.ErrTokenInvalid)
+	}
+
+	if now.Add(-v.config.ClockSkew).After(time.Unix(claims.ExpiresAt, 0)) {
+		return errorvals.ErrTokenExpired
+	}
+
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed []byte, signature []byte) error {
+	digest := sha256.Sum256(signed)
+
+	switch alg {
+	case AlgRS256, AlgPS256:
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: alg %s requires RSA key", errorvals.ErrTokenInvalid, alg)
+		}
+		var err error
+		if alg == AlgRS256 {
+			err = rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature)
+		} else {
+			err = rsa.VerifyPSS(rsaKey, crypto.SHA256, digest[:], signature, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: bad signature", errorvals.ErrTokenInvalid)
+		}
+		return nil
+	case AlgES256:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: alg %s requires EC key", errorvals.ErrTokenInvalid, alg)
+		}
+		if len(signature) != es256SignatureSize {
+			return fmt.Errorf("%w: bad signature length", errorvals.ErrTokenInvalid)
+		}
+		r := new(big.Int).SetBytes(signature[:es256SignatureSize/2])
+		s := new(big.Int).SetBytes(signature[es256SignatureSize/2:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return fmt.Errorf("%w: bad signature", errorvals.ErrTokenInvalid)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported alg %q", errorvals.ErrTokenInvalid, alg)
+	}
+}
+
+func decodeSegment(segment string, dst any) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(segment)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(decoded, dst)
+}
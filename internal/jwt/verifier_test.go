@@ -0,0 +1,396 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+)
+
+const testIssuer = "https://kc.example/realms/noted"
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+}
+
+/* ----------------------------- test JWKS server ----------------------------- */
+
+type testKey struct {
+	kid string
+	alg string
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newRSAKey(t *testing.T, kid string, alg string) testKey {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return testKey{kid: kid, alg: alg, rsa: k}
+}
+
+func newECKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return testKey{kid: kid, alg: AlgES256, ec: k}
+}
+
+func (k testKey) jwk() jsonWebKey {
+	b64 := base64.RawURLEncoding.EncodeToString
+	if k.rsa != nil {
+		return jsonWebKey{
+			KeyID:   k.kid,
+			KeyType: keyTypeRSA,
+			Alg:     k.alg,
+			Use:     "sig",
+			N:       b64(k.rsa.N.Bytes()),
+			E:       b64(big.NewInt(int64(k.rsa.E)).Bytes()),
+		}
+	}
+	return jsonWebKey{
+		KeyID:   k.kid,
+		KeyType: keyTypeEC,
+		Alg:     k.alg,
+		Use:     "sig",
+		Curve:   curveP256,
+		X:       b64(k.ec.X.FillBytes(make([]byte, 32))),
+		Y:       b64(k.ec.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func (k testKey) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+
+	hdr, err := json.Marshal(map[string]string{"alg": k.alg, "kid": k.kid, "typ": "JWT"})
+	require.NoError(t, err)
+	body, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(hdr) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	switch k.alg {
+	case AlgRS256:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+	case AlgPS256:
+		sig, err = rsa.SignPSS(rand.Reader, k.rsa, crypto.SHA256, digest[:], nil)
+	case AlgES256:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+type jwksServer struct {
+	mu      sync.Mutex
+	keys    []testKey
+	fetches atomic.Int32
+	delay   time.Duration
+	srv     *httptest.Server
+}
+
+func newJWKSServer(t *testing.T, keys ...testKey) *jwksServer {
+	t.Helper()
+	js := &jwksServer{keys: keys}
+	js.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		js.fetches.Add(1)
+		time.Sleep(js.delay)
+		js.mu.Lock()
+		defer js.mu.Unlock()
+		set := jsonWebKeySet{}
+		for _, k := range js.keys {
+			set.Keys = append(set.Keys, k.jwk())
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(js.srv.Close)
+	return js
+}
+
+func (js *jwksServer) rotate(keys ...testKey) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.keys = keys
+}
+
+func newTestVerifier(js *jwksServer, minRefresh time.Duration) *Verifier {
+	return NewVerifier(VerifierConfig{
+		CertsURL:          js.srv.URL,
+		Issuer:            testIssuer,
+		ClientID:          "noted-webpage",
+		AuthorizedParties: []string{"noted-webpage"},
+		ClockSkew:         time.Second,
+		JWKSCacheTTL:      time.Hour,
+		JWKSMinRefresh:    minRefresh,
+		Timeout:           time.Second,
+	}, testLogger())
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub": "user-123",
+		"iss": testIssuer,
+		"aud": "account",
+		"azp": "noted-webpage",
+		"exp": time.Now().Add(time.Minute).Unix(),
+		"iat": time.Now().Unix(),
+		"sid": "session-1",
+	}
+}
+
+/* ----------------------------- tests ----------------------------- */
+
+func TestVerifier_Verify_SupportedAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	keys := []testKey{
+		newRSAKey(t, "rs", AlgRS256),
+		newRSAKey(t, "ps", AlgPS256),
+		newECKey(t, "es"),
+	}
+	js := newJWKSServer(t, keys...)
+	v := newTestVerifier(js, time.Minute)
+
+	for _, k := range keys {
+		claims, err := v.Verify(context.Background(), k.sign(t, validClaims()))
+		require.NoError(t, err, k.alg)
+		require.Equal(t, "user-123", claims.Subject)
+		require.Equal(t, "session-1", claims.SessionID)
+	}
+
+	// JWKS запрошен один раз и закэширован
+	require.Equal(t, int32(1), js.fetches.Load())
+}
+
+func TestVerifier_Verify_ExpiredReturnsClaims(t *testing.T) {
+	t.Parallel()
+
+	k := newRSAKey(t, "rs", AlgRS256)
+	v := newTestVerifier(newJWKSServer(t, k), time.Minute)
+
+	c := validClaims()
+	c["exp"] = time.Now().Add(-time.Minute).Unix()
+
+	claims, err := v.Verify(context.Background(), k.sign(t, c))
+	require.ErrorIs(t, err, errorvals.ErrTokenExpired)
+	require.Equal(t, "user-123", claims.Subject)
+}
+
+func TestVerifier_Verify_RolesAndScope(t *testing.T) {
+	t.Parallel()
+
+	k := newRSAKey(t, "rs", AlgRS256)
+	v := newTestVerifier(newJWKSServer(t, k), time.Minute)
+
+	c := validClaims()
+	c["scope"] = "openid profile email"
+	c["realm_access"] = map[string]any{"roles": []string{"admin", "user"}}
+	c["resource_access"] = map[string]any{"noted-api": map[string]any{"roles": []string{"editor"}}}
+
+	claims, err := v.Verify(context.Background(), k.sign(t, c))
+	require.NoError(t, err)
+	require.Equal(t, "openid profile email", claims.Scope)
+	require.True(t, claims.RealmAccess.HasRole("admin"))
+	require.True(t, claims.ResourceAccess["noted-api"].HasRole("editor"))
+	require.False(t, claims.ResourceAccess["other"].HasRole("editor"))
+}
+
+func TestVerifier_Verify_ClaimMismatches(t *testing.T) {
+	t.Parallel()
+
+	k := newRSAKey(t, "rs", AlgRS256)
+	v := newTestVerifier(newJWKSServer(t, k), time.Minute)
+
+	cases := map[string]func(c map[string]any){
+		"issuer": func(c map[string]any) { c["iss"] = "https://evil.example/realms/noted" },
+		"azp":    func(c map[string]any) { c["azp"] = "other-client" },
+		"nbf":    func(c map[string]any) { c["nbf"] = time.Now().Add(time.Hour).Unix() },
+		"no exp": func(c map[string]any) { delete(c, "exp") },
+	}
+
+	for name, mutate := range cases {
+		c := validClaims()
+		mutate(c)
+		_, err := v.Verify(context.Background(), k.sign(t, c))
+		require.ErrorIs(t, err, errorvals.ErrTokenInvalid, name)
+	}
+}
+
+func TestVerifier_Verify_AudienceList(t *testing.T) {
+	t.Parallel()
+
+	k := newRSAKey(t, "rs", AlgRS256)
+	js := newJWKSServer(t, k)
+	v := newTestVerifier(js, time.Minute)
+	v.config.Audiences = []string{"noted-api"}
+
+	c := validClaims()
+	c["aud"] = []string{"account", "noted-api"}
+	_, err := v.Verify(context.Background(), k.sign(t, c))
+	require.NoError(t, err)
+
+	c["aud"] = "account"
+	_, err = v.Verify(context.Background(), k.sign(t, c))
+	require.ErrorIs(t, err, errorvals.ErrTokenInvalid)
+}
+
+func TestVerifier_Verify_BadSignature(t *testing.T) {
+	t.Parallel()
+
+	k := newRSAKey(t, "rs", AlgRS256)
+	v := newTestVerifier(newJWKSServer(t, k), time.Minute)
+
+	// подписан другим ключом с тем же kid
+	forged := newRSAKey(t, "rs", AlgRS256)
+	_, err := v.Verify(context.Background(), forged.sign(t, validClaims()))
+	require.ErrorIs(t, err, errorvals.ErrTokenInvalid)
+
+	// alg none не принимается
+	_, err = v.Verify(context.Background(), jwtWithPayloadJSON(t, `{"sub":"user-123"}`))
+	require.ErrorIs(t, err, errorvals.ErrTokenInvalid)
+}
+
+func TestVerifier_Verify_RefetchesOnKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	oldKey := newRSAKey(t, "old", AlgRS256)
+	js := newJWKSServer(t, oldKey)
+	v := newTestVerifier(js, 0)
+
+	_, err := v.Verify(context.Background(), oldKey.sign(t, validClaims()))
+	require.NoError(t, err)
+
+	newKey := newRSAKey(t, "new", AlgRS256)
+	js.rotate(newKey)
+
+	_, err = v.Verify(context.Background(), newKey.sign(t, validClaims()))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), js.fetches.Load())
+}
+
+func TestVerifier_Verify_UnknownKidIsRateLimited(t *testing.T) {
+	t.Parallel()
+
+	k := newRSAKey(t, "rs", AlgRS256)
+	js := newJWKSServer(t, k)
+	v := newTestVerifier(js, time.Hour)
+
+	_, err := v.Verify(context.Background(), k.sign(t, validClaims()))
+	require.NoError(t, err)
+
+	stranger := newRSAKey(t, "unknown", AlgRS256)
+	for range 3 {
+		_, err = v.Verify(context.Background(), stranger.sign(t, validClaims()))
+		require.ErrorIs(t, err, errorvals.ErrTokenInvalid)
+	}
+	require.Equal(t, int32(1), js.fetches.Load())
+}
+
+func TestVerifier_Verify_ConcurrentUnseenKidFetchedOnce(t *testing.T) {
+	t.Parallel()
+
+	k := newRSAKey(t, "rs", AlgRS256)
+	js := newJWKSServer(t, k)
+	js.delay = 50 * time.Millisecond
+	v := newTestVerifier(js, time.Hour)
+	token := k.sign(t, validClaims())
+
+	// холодный старт: пока первый запрос тянет JWKS, остальные ждут и должны получить уже загруженный ключ
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = v.Verify(context.Background(), token)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), js.fetches.Load())
+}
+
+func TestVerifier_Verify_JWKSUnavailable(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	v := NewVerifier(VerifierConfig{CertsURL: srv.URL, Issuer: testIssuer, Timeout: time.Second}, testLogger())
+
+	k := newRSAKey(t, "rs", AlgRS256)
+	_, err := v.Verify(context.Background(), k.sign(t, validClaims()))
+	require.ErrorIs(t, err, errorvals.ErrJWKSUnavailable)
+}
+
+func TestVerifier_VerifyIDToken(t *testing.T) {
+	t.Parallel()
+
+	k := newRSAKey(t, "rs", AlgRS256)
+	v := newTestVerifier(newJWKSServer(t, k), time.Minute)
+
+	idClaims := func() map[string]any {
+		c := validClaims()
+		c["aud"] = "noted-webpage"
+		c["nonce"] = "nonce-1"
+		return c
+	}
+
+	claims, err := v.VerifyIDToken(context.Background(), k.sign(t, idClaims()), "nonce-1")
+	require.NoError(t, err)
+	require.Equal(t, "nonce-1", claims.Nonce)
+
+	cases := map[string]struct {
+		mutate func(c map[string]any)
+		nonce  string
+	}{
+		"nonce mismatch": {mutate: func(map[string]any) {}, nonce: "nonce-2"},
+		"empty nonce":    {mutate: func(c map[string]any) { c["nonce"] = "" }, nonce: ""},
+		"foreign aud":    {mutate: func(c map[string]any) { c["aud"] = "account" }, nonce: "nonce-1"},
+		"foreign azp":    {mutate: func(c map[string]any) { c["azp"] = "other-client" }, nonce: "nonce-1"},
+		"foreign issuer": {mutate: func(c map[string]any) { c["iss"] = "https://evil.example" }, nonce: "nonce-1"},
+		"expired id token": {
+			mutate: func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+			nonce:  "nonce-1",
+		},
+	}
+
+	for name, tc := range cases {
+		c := idClaims()
+		tc.mutate(c)
+		_, err = v.VerifyIDToken(context.Background(), k.sign(t, c), tc.nonce)
+		require.ErrorIs(t, err, errorvals.ErrIDTokenInvalid, name)
+	}
+
+	forged := newRSAKey(t, "rs", AlgRS256)
+	_, err = v.VerifyIDToken(context.Background(), forged.sign(t, idClaims()), "nonce-1")
+	require.ErrorIs(t, err, errorvals.ErrIDTokenInvalid)
+}
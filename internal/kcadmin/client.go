@@ -0,0 +1,164 @@
+package kcadmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+// Client — обёртка над admin REST API keycloak (/admin/realms/{realm}/users).
+// Токен service account берётся из ServiceAccount; на 401 он сбрасывается и запрос повторяется один раз.
+type Client struct {
+	http   *httpclient.HTTPClient
+	tokens *ServiceAccount
+	logger *slog.Logger
+}
+
+// NewClient — client должен смотреть на .../admin/realms/{realm}/users.
+func NewClient(client *httpclient.HTTPClient, tokens *ServiceAccount, logger *slog.Logger) *Client {
+	return &Client{
+		http:   client,
+		tokens: tokens,
+		logger: logger,
+	}
+}
+
+func (c *Client) SetEnabled(ctx context.Context, userID string, enabled bool) error {
+	body, err := model.KeycloakUserEnabled{Enabled: enabled}.MarshalJSON()
+
+	if err != nil {
+		return err
+	}
+
+	return discard(c.do(ctx, http.MethodPut, url.PathEscape(userID), body))
+}
+
+func (c *Client) SetRequiredActions(ctx context.Context, userID string, actions []string) error {
+	// nil keycloak понимает как «не менять», поэтому пустой список передаётся явно
+	if actions == nil {
+		actions = []string{}
+	}
+	body, err := model.KeycloakUserRequiredActions{RequiredActions: actions}.MarshalJSON()
+
+	if err != nil {
+		return err
+	}
+
+	return discard(c.do(ctx, http.MethodPut, url.PathEscape(userID), body))
+}
+
+// Logout завершает все сессии пользователя.
+func (c *Client) Logout(ctx context.Context, userID string) error {
+	return discard(c.do(ctx, http.MethodPost, url.PathEscape(userID)+"/logout", nil))
+}
+
+// Sessions возвращает сессии пользователя; время переводится в unix секунды, клиенты сортируются по clientId.
+func (c *Client) Sessions(ctx context.Context, userID string) ([]model.Session, error) {
+	resp, err := c.do(ctx, http.MethodGet, url.PathEscape(userID)+"/sessions", nil)
+	defer func() {
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Error reading response body", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	var kcSessions model.KeycloakUserSessions
+	err = kcSessions.UnmarshalJSON(body)
+
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Error decoding user sessions", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	sessions := make([]model.Session, 0, len(kcSessions))
+
+	for _, kcSession := range kcSessions {
+		session := model.Session{
+			ID:         kcSession.ID,
+			IPAddress:  kcSession.IPAddress,
+			Started:    kcSession.Start / 1000,
+			LastAccess: kcSession.LastAccess / 1000,
+			Clients:    make([]model.SessionClient, 0, len(kcSession.Clients)),
+		}
+
+		for _, clientID := range kcSession.Clients {
+			session.Clients = append(session.Clients, model.SessionClient{ClientID: clientID})
+		}
+		slices.SortFunc(session.Clients, func(a, b model.SessionClient) int {
+			return strings.Compare(a.ClientID, b.ClientID)
+		})
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body []byte) (*http.Response, error) {
+	resp, err := c.attempt(ctx, method, path, body)
+
+	var statusErr *httpclient.StatusError
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusUnauthorized {
+		// токен мог быть отозван или ротирован секрет — берём новый и пробуем ещё раз
+		c.logger.WarnContext(ctx, "Service account token rejected, refreshing")
+		resp, err = c.attempt(ctx, method, path, body)
+	}
+
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", errorvals.ErrObjectNotFoundInRepoError, path)
+	}
+
+	return resp, err
+}
+
+func (c *Client) attempt(ctx context.Context, method string, path string, body []byte) (*http.Response, error) {
+	token, err := c.tokens.Token(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(ctx, method, token, path, body)
+
+	var statusErr *httpclient.StatusError
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusUnauthorized {
+		c.tokens.Invalidate(token)
+	}
+
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Keycloak admin request failed", slog.String(consts.ErrorLoggerKey, err.Error()),
+			slog.String("method", method), slog.String("path", path))
+	}
+
+	return resp, err
+}
+
+func discard(resp *http.Response, err error) error {
+	if resp != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	return err
+}
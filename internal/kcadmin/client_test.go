@@ -0,0 +1,162 @@
+package kcadmin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/metrics"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+}
+
+// keycloak — token endpoint и admin API. Токены выдаются по порядку: token-1, token-2...;
+// принимается только последний выданный.
+type keycloak struct {
+	mu       sync.Mutex
+	issued   int
+	requests []string
+	bodies   []string
+}
+
+func (kc *keycloak) log() ([]string, []string) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	return kc.requests, kc.bodies
+}
+
+func newKeycloak(t *testing.T) (*Client, *ServiceAccount, *keycloak) {
+	t.Helper()
+
+	kc := &keycloak{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		kc.mu.Lock()
+		defer kc.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case strings.HasPrefix(r.URL.Path, "/token"):
+			require.NoError(t, r.ParseForm())
+			require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			kc.issued++
+			_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":300}`, kc.issued)
+		case r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", kc.issued):
+			w.WriteHeader(http.StatusUnauthorized)
+		case strings.HasPrefix(r.URL.Path, "/admin/realms/noted/users/missing"):
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/admin/realms/noted/users/u1/sessions":
+			_, _ = w.Write([]byte(`[{"id":"s1","ipAddress":"10.0.0.1","start":1700000000000,` +
+				`"lastAccess":1700000100000,"clients":{"c2":"notes","c1":"noted-webpage"}}]`))
+		default:
+			body, _ := io.ReadAll(r.Body)
+			kc.requests = append(kc.requests, r.Method+" "+r.URL.Path)
+			kc.bodies = append(kc.bodies, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := &configs.HTTPClientConfig{
+		DialTimeout:     time.Second,
+		MaxIdleConns:    1,
+		IdleConnTimeout: time.Second,
+		RequestTimeout:  time.Second,
+		RetryPolicy:     configs.HTTPRetryPolicyConfig{MaxAttempts: 1, RetryOnStatus: map[int]bool{}},
+	}
+	tokenClient, err := httpclient.NewWithRetry(srv.URL+"/token", cfg,
+		metrics.NewHTTPRequestMetrics(prometheus.NewRegistry(), "token"), &atomic.Bool{}, testLogger())
+	require.NoError(t, err)
+	adminClient := httpclient.New(srv.URL+"/admin/realms/noted/users", cfg,
+		metrics.NewHTTPRequestMetrics(prometheus.NewRegistry(), "admin"), &atomic.Bool{}, testLogger())
+
+	sa := NewServiceAccount(tokenClient, "noted-auth-admin", "secret", 30*time.Second, testLogger())
+
+	return NewClient(adminClient, sa, testLogger()), sa, kc
+}
+
+func TestServiceAccount_CachesUntilSkew(t *testing.T) {
+	t.Parallel()
+
+	_, sa, _ := newKeycloak(t)
+	now := time.Now()
+	sa.now = func() time.Time { return now }
+
+	token, err := sa.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token)
+
+	// 300s жизни минус 30s skew
+	now = now.Add(269 * time.Second)
+	token, err = sa.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token)
+
+	now = now.Add(time.Second)
+	token, err = sa.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "token-2", token)
+}
+
+func TestClient_RefreshesRejectedToken(t *testing.T) {
+	t.Parallel()
+
+	c, sa, kc := newKeycloak(t)
+
+	_, err := sa.Token(context.Background())
+	require.NoError(t, err)
+	// keycloak выдал новый токен в обход кэша — закэшированный больше не принимается
+	kc.mu.Lock()
+	kc.issued++
+	kc.mu.Unlock()
+
+	require.NoError(t, c.SetEnabled(context.Background(), "u1", false))
+
+	requests, bodies := kc.log()
+	require.Equal(t, []string{"PUT /admin/realms/noted/users/u1"}, requests)
+	require.JSONEq(t, `{"enabled":false}`, bodies[0])
+}
+
+func TestClient_RequestsAndErrors(t *testing.T) {
+	t.Parallel()
+
+	c, _, kc := newKeycloak(t)
+
+	require.NoError(t, c.SetRequiredActions(context.Background(), "u1", nil))
+	require.NoError(t, c.Logout(context.Background(), "u1"))
+	requests, bodies := kc.log()
+	require.Equal(t, []string{"PUT /admin/realms/noted/users/u1", "POST /admin/realms/noted/users/u1/logout"},
+		requests)
+	require.JSONEq(t, `{"requiredActions":[]}`, bodies[0])
+
+	sessions, err := c.Sessions(context.Background(), "u1")
+	require.NoError(t, err)
+	require.Equal(t, []model.Session{{
+		ID:         "s1",
+		IPAddress:  "10.0.0.1",
+		Started:    1700000000,
+		LastAccess: 1700000100,
+		Clients:    []model.SessionClient{{ClientID: "noted-webpage"}, {ClientID: "notes"}},
+	}}, sessions)
+
+	_, err = c.Sessions(context.Background(), "missing")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+}
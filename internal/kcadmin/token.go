@@ -0,0 +1,120 @@
+package kcadmin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+// ServiceAccount выдаёт access token service account (grant client_credentials) и держит его,
+// пока до истечения не останется skew. Запрос нового токена идёт под мьютексом, так что
+// одновременные вызовы Token после истечения ходят в keycloak один раз.
+type ServiceAccount struct {
+	client   *httpclient.HTTPClient
+	clientID string
+	skew     time.Duration
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	secret  string
+	token   string
+	expires time.Time
+	now     func() time.Time
+}
+
+// NewServiceAccount — client должен смотреть на token endpoint реалма.
+func NewServiceAccount(client *httpclient.HTTPClient, clientID string, secret string, skew time.Duration,
+	logger *slog.Logger) *ServiceAccount {
+	return &ServiceAccount{
+		client:   client,
+		clientID: clientID,
+		secret:   secret,
+		skew:     skew,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+func (sa *ServiceAccount) Token(ctx context.Context) (string, error) {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	if sa.token != "" && sa.now().Before(sa.expires) {
+		return sa.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", sa.clientID)
+	form.Set("client_secret", sa.secret)
+
+	resp, err := sa.client.PostForm(ctx, form)
+	defer func() {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	if err != nil {
+		sa.logger.ErrorContext(ctx, "Failed to get service account token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return "", err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		sa.logger.ErrorContext(ctx, "Failed to read service account token response",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return "", err
+	}
+
+	var dto model.TokenDTO
+	err = easyjson.Unmarshal(body, &dto)
+
+	if err != nil {
+		sa.logger.ErrorContext(ctx, "Failed to unmarshal service account token response",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return "", err
+	}
+
+	if dto.AccessToken == "" {
+		return "", fmt.Errorf("%w: empty service account token", errorvals.ErrUpstreamUnavailable)
+	}
+
+	sa.token = dto.AccessToken
+	sa.expires = sa.now().Add(time.Duration(dto.ExpiresIn)*time.Second - sa.skew)
+	sa.logger.InfoContext(ctx, "Service account token refreshed", slog.Int("expires_in", dto.ExpiresIn))
+
+	return sa.token, nil
+}
+
+// Invalidate сбрасывает token, если keycloak его отклонил; следующий Token запросит новый.
+// Сравнение нужно, чтобы не сбросить уже обновлённый другим запросом токен.
+func (sa *ServiceAccount) Invalidate(token string) {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	if sa.token == token {
+		sa.token = ""
+	}
+}
+
+func (sa *ServiceAccount) MonitorVault(vaultChan chan string) {
+	for secret := range vaultChan {
+		sa.mu.Lock()
+		sa.secret = secret
+		sa.token = ""
+		sa.mu.Unlock()
+	}
+}
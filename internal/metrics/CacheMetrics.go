@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type CacheMetrics struct {
+	Hits   prometheus.Counter
+	Misses prometheus.Counter
+}
+
+func NewCacheMetrics(reg *prometheus.Registry, name string) *CacheMetrics {
+	hits := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: name + "_cache_hits",
+		Help: "The total number of " + name + " cache hits.",
+	})
+
+	misses := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: name + "_cache_misses",
+		Help: "The total number of " + name + " cache misses.",
+	})
+
+	reg.MustRegister(
+		hits,
+		misses,
+	)
+
+	return &CacheMetrics{
+		Hits:   hits,
+		Misses: misses,
+	}
+}
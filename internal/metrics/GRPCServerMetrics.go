@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// GRPCServerMetrics повторяет набор метрик go-grpc-prometheus, чтобы подходили готовые дашборды.
+type GRPCServerMetrics struct {
+	Started         *prometheus.CounterVec
+	Handled         *prometheus.CounterVec
+	HandlingSeconds *prometheus.HistogramVec
+}
+
+func NewGRPCServerMetrics(reg *prometheus.Registry) *GRPCServerMetrics {
+	started := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_started_total",
+		Help: "The total number of RPCs started on the server.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	handled := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "The total number of RPCs completed on the server, regardless of success or failure.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"})
+
+	handlingSeconds := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "A histogram of response latency (seconds) of gRPC that had been application-level handled by the server.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	reg.MustRegister(
+		started,
+		handled,
+		handlingSeconds,
+	)
+
+	return &GRPCServerMetrics{
+		Started:         started,
+		Handled:         handled,
+		HandlingSeconds: handlingSeconds,
+	}
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SecurityMetrics — счётчики подозрительных событий, на которые заводятся алерты.
+type SecurityMetrics struct {
+	StateReplays       prometheus.Counter
+	IDTokenRejections  prometheus.Counter
+	RefreshTokenReuses prometheus.Counter
+}
+
+func NewSecurityMetrics(reg *prometheus.Registry) *SecurityMetrics {
+	stateReplays := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "oauth_state_replays",
+		Help: "The total number of callbacks with an already consumed OAuth state.",
+	})
+
+	idTokenRejections := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "oidc_id_token_rejections",
+		Help: "The total number of ID tokens rejected after code exchange (signature, claims or nonce).",
+	})
+
+	refreshTokenReuses := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "oauth_refresh_token_reuses",
+		Help: "The total number of already rotated refresh tokens presented again (sessions revoked).",
+	})
+
+	reg.MustRegister(
+		stateReplays,
+		idTokenRejections,
+		refreshTokenReuses,
+	)
+
+	return &SecurityMetrics{
+		StateReplays:       stateReplays,
+		IDTokenRejections:  idTokenRejections,
+		RefreshTokenReuses: refreshTokenReuses,
+	}
+}
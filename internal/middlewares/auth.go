@@ -2,56 +2,204 @@ package middlewares
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/valyala/fasthttp"
 
+	"github.com/dnonakolesax/noted-auth/internal/configs"
 	"github.com/dnonakolesax/noted-auth/internal/consts"
 	"github.com/dnonakolesax/noted-auth/internal/cookies"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
 	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
 )
 
 type IntrospectUsecase interface {
 	GetUserID(ctx context.Context, at string, rt string) (model.TokenGRPCDTO, error)
+	VerifyAccessToken(ctx context.Context, at string) (model.IntrospectDTO, error)
+}
+
+type SessionResolver interface {
+	Resolve(ctx context.Context, id string) (model.WebSession, error)
 }
 
 type AuthMW struct {
-	usecase IntrospectUsecase
-	logger  *slog.Logger
+	usecase     IntrospectUsecase
+	sessions    SessionResolver
+	sessionMode string
+	codec       *cookies.Codec
+	logger      *slog.Logger
 }
 
-func NewAuthMW(usecase IntrospectUsecase, logger *slog.Logger) *AuthMW {
-	return &AuthMW{usecase: usecase, logger: logger}
+// NewAuthMW: sessions используется только в режиме configs.SessionModeServer, codec — только в режиме cookies.
+func NewAuthMW(usecase IntrospectUsecase, sessions SessionResolver, sessionMode string, codec *cookies.Codec,
+	logger *slog.Logger) *AuthMW {
+	return &AuthMW{usecase: usecase, sessions: sessions, sessionMode: sessionMode, codec: codec, logger: logger}
 }
 
+// AuthMiddleware кладёт в user values id пользователя (consts.CtxUserIDKey), principal.Principal
+// (consts.CtxPrincipalKey, в контекст переносится через principal.Attach)
+// и актуальный access token (consts.CtxAccessTokenKey). Authorization: Bearer имеет приоритет
+// над cookie; в этом режиме токены не обновляются и cookie не выставляются.
 func (am *AuthMW) AuthMiddleware(h fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return fasthttp.RequestHandler(func(ctx *fasthttp.RequestCtx) {
 		trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
 		contex := context.WithValue(context.Background(), consts.TraceContextKey, trace)
-		at := ctx.Request.Header.Cookie(consts.ATCookieKey)
-		if at == nil {
-			am.logger.WarnContext(contex, "no at passed")
-		}
-		rt := ctx.Request.Header.Cookie(consts.RTCookieKey)
-		if rt == nil {
-			am.logger.WarnContext(contex, "no rt passed")
-			ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+
+		if at, found := bearerToken(ctx); found {
+			if am.authBearer(contex, ctx, at) {
+				h(ctx)
+			}
 			return
 		}
-		dto, err := am.usecase.GetUserID(context.Background(), string(at), string(rt))
 
-		if err != nil {
-			am.logger.ErrorContext(contex, "error introspecting", slog.String(consts.ErrorLoggerKey, err.Error()))
-			ctx.SetStatusCode(fasthttp.StatusUnauthorized)
-			return
+		var ok bool
+		if am.sessionMode == configs.SessionModeServer {
+			ok = am.authSession(contex, ctx)
+		} else {
+			ok = am.authCookies(contex, ctx)
 		}
-		ctx.Request.SetUserValue(consts.CtxUserIDKey, dto.UserID)
-		if dto.AccessToken != "" && dto.RefreshToken != "" && dto.IDToken != "" {
-			cookies.SetupAccessCookies(ctx, dto.ToTokenDTO())
+
+		if !ok {
+			bearerChallenge(ctx, "", "", "")
+			return
 		}
-		am.logger.Debug(dto.AccessToken)
-		am.logger.Debug(dto.RefreshToken)
-		am.logger.Debug(dto.IDToken)
+		am.bindPrincipal(contex, ctx)
 		h(ctx)
 	})
 }
+
+// authBearer сам выставляет статус и WWW-Authenticate при отказе.
+func (am *AuthMW) authBearer(contex context.Context, ctx *fasthttp.RequestCtx, at string) bool {
+	if at == "" {
+		am.logger.WarnContext(contex, "empty bearer token passed")
+		bearerChallenge(ctx, BearerErrInvalidRequest, "empty bearer token", "")
+		return false
+	}
+
+	intro, err := am.usecase.VerifyAccessToken(contex, at)
+
+	if err != nil {
+		if errors.Is(err, errorvals.ErrTokenInvalid) || errors.Is(err, errorvals.ErrTokenExpired) {
+			am.logger.WarnContext(contex, "bearer token rejected", slog.String(consts.ErrorLoggerKey, err.Error()))
+			bearerChallenge(ctx, BearerErrInvalidToken, "the access token is invalid or expired", "")
+			return false
+		}
+		am.logger.ErrorContext(contex, "error verifying bearer token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		ctx.SetStatusCode(verifyFailureStatus(err))
+		return false
+	}
+	ctx.Request.SetUserValue(consts.CtxUserIDKey, intro.Subject)
+	ctx.Request.SetUserValue(consts.CtxAccessTokenKey, at)
+	ctx.Request.SetUserValue(consts.CtxTokenClaimsKey, intro)
+	ctx.Request.SetUserValue(consts.CtxPrincipalKey, principal.FromIntrospection(intro))
+	return true
+}
+
+// verifyFailureStatus — недоступность keycloak или JWKS отдаётся как 503, чтобы клиент мог повторить запрос.
+func verifyFailureStatus(err error) int {
+	if errors.Is(err, errorvals.ErrUpstreamUnavailable) || errors.Is(err, errorvals.ErrJWKSUnavailable) {
+		return fasthttp.StatusServiceUnavailable
+	}
+	return fasthttp.StatusInternalServerError
+}
+
+// bindPrincipal строит principal по claims access token, если их не удалось получить —
+// только по id пользователя.
+func (am *AuthMW) bindPrincipal(contex context.Context, ctx *fasthttp.RequestCtx) {
+	claims, err := am.accessClaims(contex, ctx)
+
+	if err != nil {
+		am.logger.WarnContext(contex, "failed to get access token claims for principal",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		userID, _ := ctx.UserValue(consts.CtxUserIDKey).(string)
+		ctx.Request.SetUserValue(consts.CtxPrincipalKey, principal.Principal{Subject: userID})
+		return
+	}
+	ctx.Request.SetUserValue(consts.CtxPrincipalKey, principal.FromIntrospection(claims))
+}
+
+func (am *AuthMW) authCookies(contex context.Context, ctx *fasthttp.RequestCtx) bool {
+	atCookie := ctx.Request.Header.Cookie(consts.ATCookieKey)
+	if atCookie == nil {
+		am.logger.WarnContext(contex, "no at passed")
+	}
+	rtCookie := ctx.Request.Header.Cookie(consts.RTCookieKey)
+	if rtCookie == nil {
+		am.logger.WarnContext(contex, "no rt passed")
+		return false
+	}
+
+	at, err := am.codec.Decode(consts.ATCookieKey, atCookie)
+	if err != nil {
+		// access token можно получить заново по refresh token
+		am.logger.WarnContext(contex, "failed to decode at", slog.String(consts.ErrorLoggerKey, err.Error()))
+		at = ""
+	}
+	rt, err := am.codec.Decode(consts.RTCookieKey, rtCookie)
+	if err != nil {
+		am.logger.WarnContext(contex, "failed to decode rt", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return false
+	}
+
+	dto, err := am.usecase.GetUserID(context.Background(), at, rt)
+
+	if err != nil {
+		am.logger.ErrorContext(contex, "error introspecting", slog.String(consts.ErrorLoggerKey, err.Error()))
+		if errors.Is(err, errorvals.ErrRefreshTokenReused) {
+			cookies.EraseAccessCookies(ctx)
+		}
+		return false
+	}
+	ctx.Request.SetUserValue(consts.CtxUserIDKey, dto.UserID)
+	ctx.Request.SetUserValue(consts.CtxAccessTokenKey, at)
+	setTokenClaims(ctx, dto.Claims)
+	if dto.AccessToken != "" && dto.RefreshToken != "" && dto.IDToken != "" {
+		if err = cookies.SetupAccessCookies(ctx, dto.ToTokenDTO(), am.codec); err != nil {
+			am.logger.ErrorContext(contex, "error setting up cookies", slog.String(consts.ErrorLoggerKey, err.Error()))
+			return false
+		}
+		ctx.Request.SetUserValue(consts.CtxAccessTokenKey, dto.AccessToken)
+	}
+	am.logger.Debug(dto.AccessToken)
+	am.logger.Debug(dto.RefreshToken)
+	am.logger.Debug(dto.IDToken)
+	return true
+}
+
+func (am *AuthMW) authSession(contex context.Context, ctx *fasthttp.RequestCtx) bool {
+	sid := ctx.Request.Header.Cookie(consts.SessionCookieKey)
+	if sid == nil {
+		am.logger.WarnContext(contex, "no session id passed")
+		return false
+	}
+	session, err := am.sessions.Resolve(contex, string(sid))
+
+	if err != nil {
+		if errors.Is(err, errorvals.ErrObjectNotFoundInRepoError) || errors.Is(err, errorvals.ErrSealedDataInvalid) ||
+			errors.Is(err, errorvals.ErrUnknownSealKey) || errors.Is(err, errorvals.ErrRefreshTokenReused) {
+			am.logger.WarnContext(contex, "session not found", slog.String(consts.ErrorLoggerKey, err.Error()))
+			cookies.EraseSessionCookie(ctx)
+			return false
+		}
+		am.logger.ErrorContext(contex, "error resolving session", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return false
+	}
+	ctx.Request.SetUserValue(consts.CtxUserIDKey, session.UserID)
+	ctx.Request.SetUserValue(consts.CtxAccessTokenKey, session.Tokens.AccessToken)
+	setTokenClaims(ctx, session.Claims)
+	if session.Refreshed {
+		// срок жизни сессии сдвинулся вместе с refresh token
+		cookies.SetupSessionCookie(ctx, string(sid), session.Tokens.RefreshExp)
+	}
+	return true
+}
+
+// setTokenClaims сохраняет claims, полученные при проверке токенов, чтобы authz и principal
+// не проверяли access token второй раз.
+func setTokenClaims(ctx *fasthttp.RequestCtx, claims model.IntrospectDTO) {
+	if claims.Active {
+		ctx.Request.SetUserValue(consts.CtxTokenClaimsKey, claims)
+	}
+}
@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+type accessCheck func(claims model.IntrospectDTO) bool
+
+// RequireRealmRole пропускает запрос, только если в realm_access токена есть role.
+// Оборачивает обработчик внутри AuthMiddleware: mw(am.RequireRealmRole("admin")(h)).
+func (am *AuthMW) RequireRealmRole(role string) func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return am.require("", func(claims model.IntrospectDTO) bool {
+		return claims.HasRealmRole(role)
+	})
+}
+
+// RequireClientRole пропускает запрос, только если в resource_access[client] токена есть role.
+func (am *AuthMW) RequireClientRole(client string,
+	role string) func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return am.require("", func(claims model.IntrospectDTO) bool {
+		return claims.HasClientRole(client, role)
+	})
+}
+
+// RequireScope пропускает запрос, только если у токена есть scope.
+func (am *AuthMW) RequireScope(scope string) func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return am.require(scope, func(claims model.IntrospectDTO) bool {
+		return claims.HasScope(scope)
+	})
+}
+
+// Require собирает проверки из правила конфига, пустое правило ничего не проверяет.
+func (am *AuthMW) Require(rule configs.AccessRule) func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+		if rule.ClientRole != "" {
+			h = am.RequireClientRole(rule.Client, rule.ClientRole)(h)
+		}
+		if rule.RealmRole != "" {
+			h = am.RequireRealmRole(rule.RealmRole)(h)
+		}
+		if rule.Scope != "" {
+			h = am.RequireScope(rule.Scope)(h)
+		}
+		return h
+	}
+}
+
+// require отвечает 403 insufficient_scope (RFC 6750), scope попадает в WWW-Authenticate.
+func (am *AuthMW) require(scope string, check accessCheck) func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return fasthttp.RequestHandler(func(ctx *fasthttp.RequestCtx) {
+			trace := string(ctx.Request.Header.Peek(consts.HTTPHeaderXRequestID))
+			contex := context.WithValue(context.Background(), consts.TraceContextKey, trace)
+
+			claims, err := am.accessClaims(contex, ctx)
+
+			if err != nil {
+				if errors.Is(err, errorvals.ErrTokenInvalid) || errors.Is(err, errorvals.ErrTokenExpired) {
+					am.logger.WarnContext(contex, "access token rejected", slog.String(consts.ErrorLoggerKey, err.Error()))
+					bearerChallenge(ctx, BearerErrInvalidToken, "the access token is invalid or expired", "")
+					return
+				}
+				am.logger.ErrorContext(contex, "error getting token claims", slog.String(consts.ErrorLoggerKey, err.Error()))
+				ctx.SetStatusCode(verifyFailureStatus(err))
+				return
+			}
+
+			if !check(claims) {
+				am.logger.WarnContext(contex, "access denied", slog.String("user", claims.Subject))
+				bearerChallenge(ctx, BearerErrInsufficientScope, "the access token lacks required privileges", scope)
+				return
+			}
+			h(ctx)
+		})
+	}
+}
+
+// accessClaims берёт claims, сохранённые AuthMiddleware. Если их получить не удалось
+// (обновлённый access token не разобрался), проверяется access token, выставленный AuthMiddleware.
+func (am *AuthMW) accessClaims(contex context.Context, ctx *fasthttp.RequestCtx) (model.IntrospectDTO, error) {
+	if claims, ok := ctx.UserValue(consts.CtxTokenClaimsKey).(model.IntrospectDTO); ok {
+		return claims, nil
+	}
+
+	at, _ := ctx.UserValue(consts.CtxAccessTokenKey).(string)
+	claims, err := am.usecase.VerifyAccessToken(contex, at)
+
+	if err != nil {
+		return model.IntrospectDTO{}, err
+	}
+	ctx.Request.SetUserValue(consts.CtxTokenClaimsKey, claims)
+
+	return claims, nil
+}
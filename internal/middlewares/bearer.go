@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	bearerScheme = "Bearer"
+	bearerRealm  = "noted"
+)
+
+// Коды ошибок WWW-Authenticate из RFC 6750.
+const (
+	BearerErrInvalidRequest    = "invalid_request"
+	BearerErrInvalidToken      = "invalid_token"
+	BearerErrInsufficientScope = "insufficient_scope"
+)
+
+// bearerToken достаёт токен из Authorization: Bearer. found == false, если схема другая
+// или заголовка нет: тогда запрос аутентифицируется по cookie.
+func bearerToken(ctx *fasthttp.RequestCtx) (string, bool) {
+	header := ctx.Request.Header.Peek(fasthttp.HeaderAuthorization)
+
+	if header == nil {
+		return "", false
+	}
+
+	scheme, token, _ := strings.Cut(string(header), " ")
+
+	if !strings.EqualFold(scheme, bearerScheme) {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
+
+// bearerChallenge выставляет WWW-Authenticate и статус по RFC 6750 (раздел 3.1).
+// Пустой errCode — запрос без токена, scope нужен только для insufficient_scope.
+func bearerChallenge(ctx *fasthttp.RequestCtx, errCode string, description string, scope string) {
+	var b strings.Builder
+
+	b.WriteString(bearerScheme + " realm=" + strconv.Quote(bearerRealm))
+
+	if errCode != "" {
+		b.WriteString(", error=" + strconv.Quote(errCode))
+	}
+
+	if description != "" {
+		b.WriteString(", error_description=" + strconv.Quote(description))
+	}
+
+	if scope != "" {
+		b.WriteString(", scope=" + strconv.Quote(scope))
+	}
+
+	ctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, b.String())
+
+	switch errCode {
+	case BearerErrInvalidRequest:
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+	case BearerErrInsufficientScope:
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+	default:
+		ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,42 @@
+package model
+
+// KeycloakUserSessions — ответ admin API GET /users/{id}/sessions, наружу не отдаётся.
+//
+//easyjson:json
+type KeycloakUserSessions []KeycloakUserSession
+
+type KeycloakUserSession struct { //nolint:recvcheck // autogen issues
+	ID        string `json:"id"`
+	IPAddress string `json:"ipAddress"`
+	// Start и LastAccess — unix миллисекунды, в отличие от account API.
+	Start      int64 `json:"start"`
+	LastAccess int64 `json:"lastAccess"`
+	// Clients — id клиента в keycloak -> clientId.
+	Clients map[string]string `json:"clients"`
+}
+
+// KeycloakUserEnabled и KeycloakUserRequiredActions — тела PUT /users/{id}: keycloak меняет
+// только переданные поля.
+type KeycloakUserEnabled struct { //nolint:recvcheck // autogen issues
+	Enabled bool `json:"enabled"`
+}
+
+type KeycloakUserRequiredActions struct { //nolint:recvcheck // autogen issues
+	RequiredActions []string `json:"requiredActions"`
+}
+
+// UserEnabledRequest — тело PUT /admin/users/{id}/enabled; без поля enabled запрос отклоняется.
+type UserEnabledRequest struct { //nolint:recvcheck // autogen issues
+	Enabled *bool `json:"enabled"`
+}
+
+// RequiredActionsRequest — тело PUT /admin/users/{id}/required-actions, пустой список снимает все действия.
+type RequiredActionsRequest struct { //nolint:recvcheck // autogen issues
+	Actions []string `json:"actions"`
+}
+
+// UserSessions — ответ GET /admin/users/{id}/sessions. Время — unix секунды.
+type UserSessions struct { //nolint:recvcheck // autogen issues
+	UserID   string    `json:"user_id"`
+	Sessions []Session `json:"sessions"`
+}
@@ -0,0 +1,672 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package model
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel(in *jlexer.Lexer, out *UserSessions) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "user_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserID = string(in.String())
+			}
+		case "sessions":
+			if in.IsNull() {
+				in.Skip()
+				out.Sessions = nil
+			} else {
+				in.Delim('[')
+				if out.Sessions == nil {
+					if !in.IsDelim(']') {
+						out.Sessions = make([]Session, 0, 0)
+					} else {
+						out.Sessions = []Session{}
+					}
+				} else {
+					out.Sessions = (out.Sessions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 Session
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v1).UnmarshalEasyJSON(in)
+					}
+					out.Sessions = append(out.Sessions, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel(out *jwriter.Writer, in UserSessions) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"sessions\":"
+		out.RawString(prefix)
+		if in.Sessions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Sessions {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserSessions) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserSessions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserSessions) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserSessions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel(l, v)
+}
+func easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel1(in *jlexer.Lexer, out *UserEnabledRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "enabled":
+			if in.IsNull() {
+				in.Skip()
+				out.Enabled = nil
+			} else {
+				if out.Enabled == nil {
+					out.Enabled = new(bool)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Enabled = bool(in.Bool())
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel1(out *jwriter.Writer, in UserEnabledRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"enabled\":"
+		out.RawString(prefix[1:])
+		if in.Enabled == nil {
+			out.RawString("null")
+		} else {
+			out.Bool(bool(*in.Enabled))
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserEnabledRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserEnabledRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserEnabledRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserEnabledRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel1(l, v)
+}
+func easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel2(in *jlexer.Lexer, out *RequiredActionsRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "actions":
+			if in.IsNull() {
+				in.Skip()
+				out.Actions = nil
+			} else {
+				in.Delim('[')
+				if out.Actions == nil {
+					if !in.IsDelim(']') {
+						out.Actions = make([]string, 0, 4)
+					} else {
+						out.Actions = []string{}
+					}
+				} else {
+					out.Actions = (out.Actions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v4 = string(in.String())
+					}
+					out.Actions = append(out.Actions, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel2(out *jwriter.Writer, in RequiredActionsRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"actions\":"
+		out.RawString(prefix[1:])
+		if in.Actions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Actions {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RequiredActionsRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RequiredActionsRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RequiredActionsRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RequiredActionsRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel2(l, v)
+}
+func easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel3(in *jlexer.Lexer, out *KeycloakUserSessions) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(KeycloakUserSessions, 0, 1)
+			} else {
+				*out = KeycloakUserSessions{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v7 KeycloakUserSession
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(v7).UnmarshalEasyJSON(in)
+			}
+			*out = append(*out, v7)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel3(out *jwriter.Writer, in KeycloakUserSessions) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v8, v9 := range in {
+			if v8 > 0 {
+				out.RawByte(',')
+			}
+			(v9).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KeycloakUserSessions) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KeycloakUserSessions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KeycloakUserSessions) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KeycloakUserSessions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel3(l, v)
+}
+func easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel4(in *jlexer.Lexer, out *KeycloakUserSession) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "ipAddress":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IPAddress = string(in.String())
+			}
+		case "start":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Start = int64(in.Int64())
+			}
+		case "lastAccess":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LastAccess = int64(in.Int64())
+			}
+		case "clients":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.Clients = make(map[string]string)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v10 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v10 = string(in.String())
+					}
+					(out.Clients)[key] = v10
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel4(out *jwriter.Writer, in KeycloakUserSession) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"ipAddress\":"
+		out.RawString(prefix)
+		out.String(string(in.IPAddress))
+	}
+	{
+		const prefix string = ",\"start\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Start))
+	}
+	{
+		const prefix string = ",\"lastAccess\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastAccess))
+	}
+	{
+		const prefix string = ",\"clients\":"
+		out.RawString(prefix)
+		if in.Clients == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v11First := true
+			for v11Name, v11Value := range in.Clients {
+				if v11First {
+					v11First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v11Name))
+				out.RawByte(':')
+				out.String(string(v11Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KeycloakUserSession) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KeycloakUserSession) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KeycloakUserSession) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KeycloakUserSession) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel4(l, v)
+}
+func easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel5(in *jlexer.Lexer, out *KeycloakUserRequiredActions) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "requiredActions":
+			if in.IsNull() {
+				in.Skip()
+				out.RequiredActions = nil
+			} else {
+				in.Delim('[')
+				if out.RequiredActions == nil {
+					if !in.IsDelim(']') {
+						out.RequiredActions = make([]string, 0, 4)
+					} else {
+						out.RequiredActions = []string{}
+					}
+				} else {
+					out.RequiredActions = (out.RequiredActions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v12 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v12 = string(in.String())
+					}
+					out.RequiredActions = append(out.RequiredActions, v12)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel5(out *jwriter.Writer, in KeycloakUserRequiredActions) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"requiredActions\":"
+		out.RawString(prefix[1:])
+		if in.RequiredActions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v13, v14 := range in.RequiredActions {
+				if v13 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v14))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KeycloakUserRequiredActions) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KeycloakUserRequiredActions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KeycloakUserRequiredActions) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KeycloakUserRequiredActions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel5(l, v)
+}
+func easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel6(in *jlexer.Lexer, out *KeycloakUserEnabled) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "enabled":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Enabled = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel6(out *jwriter.Writer, in KeycloakUserEnabled) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"enabled\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Enabled))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KeycloakUserEnabled) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KeycloakUserEnabled) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9280440fEncodeGithubComDnonakolesaxNotedAuthInternalModel6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KeycloakUserEnabled) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KeycloakUserEnabled) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9280440fDecodeGithubComDnonakolesaxNotedAuthInternalModel6(l, v)
+}
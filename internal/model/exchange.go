@@ -0,0 +1,14 @@
+package model
+
+// TokenTypeAccessToken — тип токена RFC 8693 для subject_token и issued_token_type.
+const TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+// ExchangedToken — ответ обмена токена (RFC 8693, раздел 2.2.1). ExpiresIn — секунд до истечения
+// на момент выдачи: для закэшированного токена он меньше исходного.
+type ExchangedToken struct { //nolint:recvcheck // autogen issues
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int    `json:"expires_in"`
+	Scope           string `json:"scope,omitempty"`
+}
@@ -0,0 +1,128 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package model
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson9ca7b57DecodeGithubComDnonakolesaxNotedAuthInternalModel(in *jlexer.Lexer, out *ExchangedToken) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "access_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AccessToken = string(in.String())
+			}
+		case "issued_token_type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IssuedTokenType = string(in.String())
+			}
+		case "token_type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.TokenType = string(in.String())
+			}
+		case "expires_in":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ExpiresIn = int(in.Int())
+			}
+		case "scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9ca7b57EncodeGithubComDnonakolesaxNotedAuthInternalModel(out *jwriter.Writer, in ExchangedToken) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"access_token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.AccessToken))
+	}
+	{
+		const prefix string = ",\"issued_token_type\":"
+		out.RawString(prefix)
+		out.String(string(in.IssuedTokenType))
+	}
+	{
+		const prefix string = ",\"token_type\":"
+		out.RawString(prefix)
+		out.String(string(in.TokenType))
+	}
+	{
+		const prefix string = ",\"expires_in\":"
+		out.RawString(prefix)
+		out.Int(int(in.ExpiresIn))
+	}
+	if in.Scope != "" {
+		const prefix string = ",\"scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ExchangedToken) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9ca7b57EncodeGithubComDnonakolesaxNotedAuthInternalModel(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ExchangedToken) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9ca7b57EncodeGithubComDnonakolesaxNotedAuthInternalModel(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ExchangedToken) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9ca7b57DecodeGithubComDnonakolesaxNotedAuthInternalModel(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ExchangedToken) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9ca7b57DecodeGithubComDnonakolesaxNotedAuthInternalModel(l, v)
+}
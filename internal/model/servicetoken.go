@@ -0,0 +1,10 @@
+package model
+
+// ServiceToken — токен, выданный брокером другому сервису. ExpiresIn — секунд до истечения
+// на момент выдачи: для закэшированного токена он меньше исходного.
+type ServiceToken struct { //nolint:recvcheck // autogen issues
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+	Scope       string `json:"scope,omitempty"`
+}
@@ -0,0 +1,78 @@
+package model
+
+// SessionsVersion — версия формата ответа GET /session; меняется только при несовместимых изменениях.
+const SessionsVersion = 1
+
+// KeycloakDevices — ответ keycloak /account/sessions/devices, наружу не отдаётся.
+//
+//easyjson:json
+type KeycloakDevices []KeycloakDevice
+
+type KeycloakDevice struct { //nolint:recvcheck // autogen issues
+	ID         string            `json:"id"`
+	IPAddress  string            `json:"ipAddress"`
+	OS         string            `json:"os"`
+	OSVersion  string            `json:"osVersion"`
+	Browser    string            `json:"browser"`
+	Device     string            `json:"device"`
+	LastAccess int64             `json:"lastAccess"`
+	Mobile     bool              `json:"mobile"`
+	Sessions   []KeycloakSession `json:"sessions"`
+}
+
+type KeycloakSession struct { //nolint:recvcheck // autogen issues
+	ID         string           `json:"id"`
+	IPAddress  string           `json:"ipAddress"`
+	Started    int64            `json:"started"`
+	LastAccess int64            `json:"lastAccess"`
+	Expires    int64            `json:"expires"`
+	Browser    string           `json:"browser"`
+	Clients    []KeycloakClient `json:"clients"`
+}
+
+type KeycloakClient struct { //nolint:recvcheck // autogen issues
+	ClientID   string `json:"clientId"`
+	ClientName string `json:"clientName"`
+}
+
+// Sessions — ответ GET /session. Время — unix секунды.
+type Sessions struct { //nolint:recvcheck // autogen issues
+	Version int      `json:"version"`
+	Devices []Device `json:"devices"`
+}
+
+type Device struct { //nolint:recvcheck // autogen issues
+	ID             string `json:"id"`
+	IPAddress      string `json:"ip_address"`
+	OS             string `json:"os"`
+	OSVersion      string `json:"os_version"`
+	Browser        string `json:"browser"`
+	BrowserVersion string `json:"browser_version"`
+	// DeviceClass — desktop, mobile, tablet, bot или unknown.
+	DeviceClass string    `json:"device_class"`
+	LastAccess  int64     `json:"last_access"`
+	Current     bool      `json:"current"`
+	Sessions    []Session `json:"sessions"`
+}
+
+type Session struct { //nolint:recvcheck // autogen issues
+	ID         string          `json:"id"`
+	IPAddress  string          `json:"ip_address"`
+	Started    int64           `json:"started"`
+	LastAccess int64           `json:"last_access"`
+	Expires    int64           `json:"expires"`
+	Current    bool            `json:"current"`
+	Clients    []SessionClient `json:"clients"`
+}
+
+type SessionClient struct { //nolint:recvcheck // autogen issues
+	ClientID string `json:"client_id"`
+	Name     string `json:"name"`
+}
+
+// SessionsRevoked — итог удаления сессий: что удалено, что не удалось (только при удалении по клиенту).
+type SessionsRevoked struct { //nolint:recvcheck // autogen issues
+	Revoked        []string `json:"revoked"`
+	Failed         []string `json:"failed,omitempty"`
+	CurrentRevoked bool     `json:"current_revoked"`
+}
@@ -0,0 +1,1205 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package model
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel(in *jlexer.Lexer, out *SessionsRevoked) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "revoked":
+			if in.IsNull() {
+				in.Skip()
+				out.Revoked = nil
+			} else {
+				in.Delim('[')
+				if out.Revoked == nil {
+					if !in.IsDelim(']') {
+						out.Revoked = make([]string, 0, 4)
+					} else {
+						out.Revoked = []string{}
+					}
+				} else {
+					out.Revoked = (out.Revoked)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v1 = string(in.String())
+					}
+					out.Revoked = append(out.Revoked, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "failed":
+			if in.IsNull() {
+				in.Skip()
+				out.Failed = nil
+			} else {
+				in.Delim('[')
+				if out.Failed == nil {
+					if !in.IsDelim(']') {
+						out.Failed = make([]string, 0, 4)
+					} else {
+						out.Failed = []string{}
+					}
+				} else {
+					out.Failed = (out.Failed)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v2 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v2 = string(in.String())
+					}
+					out.Failed = append(out.Failed, v2)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "current_revoked":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CurrentRevoked = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel(out *jwriter.Writer, in SessionsRevoked) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"revoked\":"
+		out.RawString(prefix[1:])
+		if in.Revoked == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v3, v4 := range in.Revoked {
+				if v3 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v4))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Failed) != 0 {
+		const prefix string = ",\"failed\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v5, v6 := range in.Failed {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"current_revoked\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.CurrentRevoked))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SessionsRevoked) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SessionsRevoked) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SessionsRevoked) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SessionsRevoked) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel(l, v)
+}
+func easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel1(in *jlexer.Lexer, out *Sessions) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "version":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Version = int(in.Int())
+			}
+		case "devices":
+			if in.IsNull() {
+				in.Skip()
+				out.Devices = nil
+			} else {
+				in.Delim('[')
+				if out.Devices == nil {
+					if !in.IsDelim(']') {
+						out.Devices = make([]Device, 0, 0)
+					} else {
+						out.Devices = []Device{}
+					}
+				} else {
+					out.Devices = (out.Devices)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 Device
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v7).UnmarshalEasyJSON(in)
+					}
+					out.Devices = append(out.Devices, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel1(out *jwriter.Writer, in Sessions) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Version))
+	}
+	{
+		const prefix string = ",\"devices\":"
+		out.RawString(prefix)
+		if in.Devices == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Devices {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				(v9).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Sessions) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Sessions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Sessions) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Sessions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel1(l, v)
+}
+func easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel2(in *jlexer.Lexer, out *SessionClient) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "client_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientID = string(in.String())
+			}
+		case "name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel2(out *jwriter.Writer, in SessionClient) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"client_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ClientID))
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SessionClient) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SessionClient) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SessionClient) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SessionClient) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel2(l, v)
+}
+func easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel3(in *jlexer.Lexer, out *Session) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "ip_address":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IPAddress = string(in.String())
+			}
+		case "started":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Started = int64(in.Int64())
+			}
+		case "last_access":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LastAccess = int64(in.Int64())
+			}
+		case "expires":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Expires = int64(in.Int64())
+			}
+		case "current":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Current = bool(in.Bool())
+			}
+		case "clients":
+			if in.IsNull() {
+				in.Skip()
+				out.Clients = nil
+			} else {
+				in.Delim('[')
+				if out.Clients == nil {
+					if !in.IsDelim(']') {
+						out.Clients = make([]SessionClient, 0, 2)
+					} else {
+						out.Clients = []SessionClient{}
+					}
+				} else {
+					out.Clients = (out.Clients)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 SessionClient
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v10).UnmarshalEasyJSON(in)
+					}
+					out.Clients = append(out.Clients, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel3(out *jwriter.Writer, in Session) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"ip_address\":"
+		out.RawString(prefix)
+		out.String(string(in.IPAddress))
+	}
+	{
+		const prefix string = ",\"started\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Started))
+	}
+	{
+		const prefix string = ",\"last_access\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastAccess))
+	}
+	{
+		const prefix string = ",\"expires\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Expires))
+	}
+	{
+		const prefix string = ",\"current\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Current))
+	}
+	{
+		const prefix string = ",\"clients\":"
+		out.RawString(prefix)
+		if in.Clients == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.Clients {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				(v12).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Session) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Session) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Session) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Session) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel3(l, v)
+}
+func easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel4(in *jlexer.Lexer, out *KeycloakSession) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "ipAddress":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IPAddress = string(in.String())
+			}
+		case "started":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Started = int64(in.Int64())
+			}
+		case "lastAccess":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LastAccess = int64(in.Int64())
+			}
+		case "expires":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Expires = int64(in.Int64())
+			}
+		case "browser":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Browser = string(in.String())
+			}
+		case "clients":
+			if in.IsNull() {
+				in.Skip()
+				out.Clients = nil
+			} else {
+				in.Delim('[')
+				if out.Clients == nil {
+					if !in.IsDelim(']') {
+						out.Clients = make([]KeycloakClient, 0, 2)
+					} else {
+						out.Clients = []KeycloakClient{}
+					}
+				} else {
+					out.Clients = (out.Clients)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 KeycloakClient
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v13).UnmarshalEasyJSON(in)
+					}
+					out.Clients = append(out.Clients, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel4(out *jwriter.Writer, in KeycloakSession) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"ipAddress\":"
+		out.RawString(prefix)
+		out.String(string(in.IPAddress))
+	}
+	{
+		const prefix string = ",\"started\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Started))
+	}
+	{
+		const prefix string = ",\"lastAccess\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastAccess))
+	}
+	{
+		const prefix string = ",\"expires\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Expires))
+	}
+	{
+		const prefix string = ",\"browser\":"
+		out.RawString(prefix)
+		out.String(string(in.Browser))
+	}
+	{
+		const prefix string = ",\"clients\":"
+		out.RawString(prefix)
+		if in.Clients == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.Clients {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				(v15).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KeycloakSession) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KeycloakSession) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KeycloakSession) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KeycloakSession) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel4(l, v)
+}
+func easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel5(in *jlexer.Lexer, out *KeycloakDevices) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(KeycloakDevices, 0, 0)
+			} else {
+				*out = KeycloakDevices{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v16 KeycloakDevice
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(v16).UnmarshalEasyJSON(in)
+			}
+			*out = append(*out, v16)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel5(out *jwriter.Writer, in KeycloakDevices) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v17, v18 := range in {
+			if v17 > 0 {
+				out.RawByte(',')
+			}
+			(v18).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KeycloakDevices) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KeycloakDevices) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KeycloakDevices) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KeycloakDevices) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel5(l, v)
+}
+func easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel6(in *jlexer.Lexer, out *KeycloakDevice) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "ipAddress":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IPAddress = string(in.String())
+			}
+		case "os":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OS = string(in.String())
+			}
+		case "osVersion":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OSVersion = string(in.String())
+			}
+		case "browser":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Browser = string(in.String())
+			}
+		case "device":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Device = string(in.String())
+			}
+		case "lastAccess":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LastAccess = int64(in.Int64())
+			}
+		case "mobile":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Mobile = bool(in.Bool())
+			}
+		case "sessions":
+			if in.IsNull() {
+				in.Skip()
+				out.Sessions = nil
+			} else {
+				in.Delim('[')
+				if out.Sessions == nil {
+					if !in.IsDelim(']') {
+						out.Sessions = make([]KeycloakSession, 0, 0)
+					} else {
+						out.Sessions = []KeycloakSession{}
+					}
+				} else {
+					out.Sessions = (out.Sessions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 KeycloakSession
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v19).UnmarshalEasyJSON(in)
+					}
+					out.Sessions = append(out.Sessions, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel6(out *jwriter.Writer, in KeycloakDevice) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"ipAddress\":"
+		out.RawString(prefix)
+		out.String(string(in.IPAddress))
+	}
+	{
+		const prefix string = ",\"os\":"
+		out.RawString(prefix)
+		out.String(string(in.OS))
+	}
+	{
+		const prefix string = ",\"osVersion\":"
+		out.RawString(prefix)
+		out.String(string(in.OSVersion))
+	}
+	{
+		const prefix string = ",\"browser\":"
+		out.RawString(prefix)
+		out.String(string(in.Browser))
+	}
+	{
+		const prefix string = ",\"device\":"
+		out.RawString(prefix)
+		out.String(string(in.Device))
+	}
+	{
+		const prefix string = ",\"lastAccess\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastAccess))
+	}
+	{
+		const prefix string = ",\"mobile\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Mobile))
+	}
+	{
+		const prefix string = ",\"sessions\":"
+		out.RawString(prefix)
+		if in.Sessions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v20, v21 := range in.Sessions {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				(v21).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KeycloakDevice) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KeycloakDevice) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KeycloakDevice) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KeycloakDevice) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel6(l, v)
+}
+func easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel7(in *jlexer.Lexer, out *KeycloakClient) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "clientId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientID = string(in.String())
+			}
+		case "clientName":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientName = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel7(out *jwriter.Writer, in KeycloakClient) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"clientId\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ClientID))
+	}
+	{
+		const prefix string = ",\"clientName\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientName))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KeycloakClient) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KeycloakClient) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KeycloakClient) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KeycloakClient) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel7(l, v)
+}
+func easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel8(in *jlexer.Lexer, out *Device) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "ip_address":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IPAddress = string(in.String())
+			}
+		case "os":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OS = string(in.String())
+			}
+		case "os_version":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OSVersion = string(in.String())
+			}
+		case "browser":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Browser = string(in.String())
+			}
+		case "browser_version":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.BrowserVersion = string(in.String())
+			}
+		case "device_class":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DeviceClass = string(in.String())
+			}
+		case "last_access":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LastAccess = int64(in.Int64())
+			}
+		case "current":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Current = bool(in.Bool())
+			}
+		case "sessions":
+			if in.IsNull() {
+				in.Skip()
+				out.Sessions = nil
+			} else {
+				in.Delim('[')
+				if out.Sessions == nil {
+					if !in.IsDelim(']') {
+						out.Sessions = make([]Session, 0, 0)
+					} else {
+						out.Sessions = []Session{}
+					}
+				} else {
+					out.Sessions = (out.Sessions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v22 Session
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v22).UnmarshalEasyJSON(in)
+					}
+					out.Sessions = append(out.Sessions, v22)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel8(out *jwriter.Writer, in Device) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"ip_address\":"
+		out.RawString(prefix)
+		out.String(string(in.IPAddress))
+	}
+	{
+		const prefix string = ",\"os\":"
+		out.RawString(prefix)
+		out.String(string(in.OS))
+	}
+	{
+		const prefix string = ",\"os_version\":"
+		out.RawString(prefix)
+		out.String(string(in.OSVersion))
+	}
+	{
+		const prefix string = ",\"browser\":"
+		out.RawString(prefix)
+		out.String(string(in.Browser))
+	}
+	{
+		const prefix string = ",\"browser_version\":"
+		out.RawString(prefix)
+		out.String(string(in.BrowserVersion))
+	}
+	{
+		const prefix string = ",\"device_class\":"
+		out.RawString(prefix)
+		out.String(string(in.DeviceClass))
+	}
+	{
+		const prefix string = ",\"last_access\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastAccess))
+	}
+	{
+		const prefix string = ",\"current\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Current))
+	}
+	{
+		const prefix string = ",\"sessions\":"
+		out.RawString(prefix)
+		if in.Sessions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v23, v24 := range in.Sessions {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				(v24).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Device) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Device) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA818f49aEncodeGithubComDnonakolesaxNotedAuthInternalModel8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Device) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Device) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA818f49aDecodeGithubComDnonakolesaxNotedAuthInternalModel8(l, v)
+}
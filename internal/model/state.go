@@ -0,0 +1,13 @@
+package model
+
+// AuthState — всё, что нужно сохранить между редиректом в keycloak и callback'ом.
+// Хранится одной записью, чтобы частичная запись не оставляла state без code_verifier.
+type AuthState struct { //nolint:recvcheck // autogen issues
+	ReturnURL     string   `json:"return_url"`
+	CodeVerifier  string   `json:"code_verifier"`
+	Nonce         string   `json:"nonce"`
+	CreatedAt     int64    `json:"created_at"`
+	ClientIPHash  string   `json:"client_ip_hash"`
+	UserAgentHash string   `json:"user_agent_hash"`
+	Scopes        []string `json:"scopes"`
+}
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"slices"
+	"strings"
+)
+
 type TokenDTO struct { //nolint:recvcheck // autogen issues
 	AccessToken     string `json:"access_token"`
 	ExpiresIn       int    `json:"expires_in"`
@@ -14,6 +19,8 @@ type TokenDTO struct { //nolint:recvcheck // autogen issues
 	ReturnURL       string `json:"return_url"`
 }
 
+// TokenGRPCDTO — результат проверки пары токенов. Claims — claims актуального access token
+// (после обновления — нового), Active == false, если их получить не удалось.
 type TokenGRPCDTO struct { //nolint:recvcheck // autogen issues
 	AccessToken  string
 	RefreshToken string
@@ -21,11 +28,45 @@ type TokenGRPCDTO struct { //nolint:recvcheck // autogen issues
 	ExpiresIn    int
 	RefreshExp   int
 	UserID       string
+	Claims       IntrospectDTO
 }
 
 type IntrospectDTO struct { //nolint:recvcheck // autogen issues
-	Active  bool   `json:"active"`
-	Subject string `json:"sub"`
+	Active       bool   `json:"active"`
+	Subject      string `json:"sub"`
+	ExpiresAt    int64  `json:"exp"`
+	SessionID    string `json:"sid"`
+	SessionState string `json:"session_state"`
+	Username     string `json:"username"`
+	// Scope — scope токена через пробел.
+	Scope          string            `json:"scope"`
+	RealmAccess    Access            `json:"realm_access"`
+	ResourceAccess map[string]Access `json:"resource_access"`
+}
+
+// Access — роли из realm_access или из resource_access[client] токена keycloak.
+type Access struct {
+	Roles []string `json:"roles"`
+}
+
+func (a Access) HasRole(role string) bool {
+	return slices.Contains(a.Roles, role)
+}
+
+func (id IntrospectDTO) Scopes() []string {
+	return strings.Fields(id.Scope)
+}
+
+func (id IntrospectDTO) HasScope(scope string) bool {
+	return slices.Contains(id.Scopes(), scope)
+}
+
+func (id IntrospectDTO) HasRealmRole(role string) bool {
+	return id.RealmAccess.HasRole(role)
+}
+
+func (id IntrospectDTO) HasClientRole(client string, role string) bool {
+	return id.ResourceAccess[client].HasRole(role)
 }
 
 func (td *TokenGRPCDTO) ToTokenDTO() TokenDTO {
@@ -37,3 +78,12 @@ func (td *TokenGRPCDTO) ToTokenDTO() TokenDTO {
 		RefreshExp:   td.RefreshExp,
 	}
 }
+
+// WebSession — серверная сессия, разрешённая по id из cookie. Refreshed выставляется,
+// если при разрешении токены пришлось обновить. Claims — как в TokenGRPCDTO.
+type WebSession struct {
+	UserID    string
+	Tokens    TokenDTO
+	Refreshed bool
+	Claims    IntrospectDTO
+}
@@ -17,7 +17,105 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel(in *jlexer.Lexer, out *TokenGRPCDTO) {
+func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel(in *jlexer.Lexer, out *WebSession) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "UserID":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserID = string(in.String())
+			}
+		case "Tokens":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Tokens).UnmarshalEasyJSON(in)
+			}
+		case "Refreshed":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Refreshed = bool(in.Bool())
+			}
+		case "Claims":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Claims).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel(out *jwriter.Writer, in WebSession) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"UserID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"Tokens\":"
+		out.RawString(prefix)
+		(in.Tokens).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"Refreshed\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Refreshed))
+	}
+	{
+		const prefix string = ",\"Claims\":"
+		out.RawString(prefix)
+		(in.Claims).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v WebSession) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v WebSession) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *WebSession) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *WebSession) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel(l, v)
+}
+func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel1(in *jlexer.Lexer, out *TokenGRPCDTO) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -67,6 +165,12 @@ func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel(in *jlexe
 			} else {
 				out.UserID = string(in.String())
 			}
+		case "Claims":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Claims).UnmarshalEasyJSON(in)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -77,7 +181,7 @@ func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel(in *jlexe
 		in.Consumed()
 	}
 }
-func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel(out *jwriter.Writer, in TokenGRPCDTO) {
+func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel1(out *jwriter.Writer, in TokenGRPCDTO) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -111,33 +215,38 @@ func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel(out *jwri
 		out.RawString(prefix)
 		out.String(string(in.UserID))
 	}
+	{
+		const prefix string = ",\"Claims\":"
+		out.RawString(prefix)
+		(in.Claims).MarshalEasyJSON(out)
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v TokenGRPCDTO) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel(&w, v)
+	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v TokenGRPCDTO) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel(w, v)
+	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *TokenGRPCDTO) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel(&r, v)
+	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *TokenGRPCDTO) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel(l, v)
+	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel1(l, v)
 }
-func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel1(in *jlexer.Lexer, out *TokenDTO) {
+func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel2(in *jlexer.Lexer, out *TokenDTO) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -227,7 +336,7 @@ func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel1(in *jlex
 		in.Consumed()
 	}
 }
-func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel1(out *jwriter.Writer, in TokenDTO) {
+func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel2(out *jwriter.Writer, in TokenDTO) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -292,27 +401,27 @@ func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel1(out *jwr
 // MarshalJSON supports json.Marshaler interface
 func (v TokenDTO) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel1(&w, v)
+	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v TokenDTO) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel1(w, v)
+	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *TokenDTO) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel1(&r, v)
+	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *TokenDTO) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel1(l, v)
+	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel2(l, v)
 }
-func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel2(in *jlexer.Lexer, out *IntrospectDTO) {
+func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel3(in *jlexer.Lexer, out *IntrospectDTO) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -338,6 +447,62 @@ func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel2(in *jlex
 			} else {
 				out.Subject = string(in.String())
 			}
+		case "exp":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ExpiresAt = int64(in.Int64())
+			}
+		case "sid":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SessionID = string(in.String())
+			}
+		case "session_state":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SessionState = string(in.String())
+			}
+		case "username":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Username = string(in.String())
+			}
+		case "scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
+			}
+		case "realm_access":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.RealmAccess).UnmarshalEasyJSON(in)
+			}
+		case "resource_access":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.ResourceAccess = make(map[string]Access)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v1 Access
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v1).UnmarshalEasyJSON(in)
+					}
+					(out.ResourceAccess)[key] = v1
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -348,7 +513,7 @@ func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel2(in *jlex
 		in.Consumed()
 	}
 }
-func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel2(out *jwriter.Writer, in IntrospectDTO) {
+func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel3(out *jwriter.Writer, in IntrospectDTO) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -362,29 +527,177 @@ func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel2(out *jwr
 		out.RawString(prefix)
 		out.String(string(in.Subject))
 	}
+	{
+		const prefix string = ",\"exp\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ExpiresAt))
+	}
+	{
+		const prefix string = ",\"sid\":"
+		out.RawString(prefix)
+		out.String(string(in.SessionID))
+	}
+	{
+		const prefix string = ",\"session_state\":"
+		out.RawString(prefix)
+		out.String(string(in.SessionState))
+	}
+	{
+		const prefix string = ",\"username\":"
+		out.RawString(prefix)
+		out.String(string(in.Username))
+	}
+	{
+		const prefix string = ",\"scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
+	}
+	{
+		const prefix string = ",\"realm_access\":"
+		out.RawString(prefix)
+		(in.RealmAccess).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"resource_access\":"
+		out.RawString(prefix)
+		if in.ResourceAccess == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v2First := true
+			for v2Name, v2Value := range in.ResourceAccess {
+				if v2First {
+					v2First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v2Name))
+				out.RawByte(':')
+				(v2Value).MarshalEasyJSON(out)
+			}
+			out.RawByte('}')
+		}
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v IntrospectDTO) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel2(&w, v)
+	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v IntrospectDTO) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel2(w, v)
+	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *IntrospectDTO) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel2(&r, v)
+	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *IntrospectDTO) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel2(l, v)
+	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel3(l, v)
+}
+func easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel4(in *jlexer.Lexer, out *Access) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "roles":
+			if in.IsNull() {
+				in.Skip()
+				out.Roles = nil
+			} else {
+				in.Delim('[')
+				if out.Roles == nil {
+					if !in.IsDelim(']') {
+						out.Roles = make([]string, 0, 4)
+					} else {
+						out.Roles = []string{}
+					}
+				} else {
+					out.Roles = (out.Roles)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v3 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v3 = string(in.String())
+					}
+					out.Roles = append(out.Roles, v3)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel4(out *jwriter.Writer, in Access) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"roles\":"
+		out.RawString(prefix[1:])
+		if in.Roles == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v4, v5 := range in.Roles {
+				if v4 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v5))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Access) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Access) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF041b085EncodeGithubComDnonakolesaxNotedAuthInternalModel4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Access) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Access) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF041b085DecodeGithubComDnonakolesaxNotedAuthInternalModel4(l, v)
 }
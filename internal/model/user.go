@@ -1,7 +1,14 @@
 package model
 
 type User struct { //nolint:recvcheck // autogen issues
-	Login     string `json:"login"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
+	Login         string `json:"login"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Enabled       bool   `json:"enabled"`
+	// CreatedTimestamp — время регистрации в keycloak, unix ms.
+	CreatedTimestamp int64 `json:"created_timestamp"`
+	// Attributes — атрибуты из user_attribute, разрешённые в users.attributes.
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
@@ -49,6 +49,54 @@ func easyjson9e1087fdDecodeGithubComDnonakolesaxNotedAuthInternalModel(in *jlexe
 			} else {
 				out.LastName = string(in.String())
 			}
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "email_verified":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.EmailVerified = bool(in.Bool())
+			}
+		case "enabled":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Enabled = bool(in.Bool())
+			}
+		case "created_timestamp":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CreatedTimestamp = int64(in.Int64())
+			}
+		case "attributes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Attributes = make(map[string]string)
+				} else {
+					out.Attributes = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v1 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v1 = string(in.String())
+					}
+					(out.Attributes)[key] = v1
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -78,6 +126,45 @@ func easyjson9e1087fdEncodeGithubComDnonakolesaxNotedAuthInternalModel(out *jwri
 		out.RawString(prefix)
 		out.String(string(in.LastName))
 	}
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"email_verified\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.EmailVerified))
+	}
+	{
+		const prefix string = ",\"enabled\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Enabled))
+	}
+	{
+		const prefix string = ",\"created_timestamp\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.CreatedTimestamp))
+	}
+	if len(in.Attributes) != 0 {
+		const prefix string = ",\"attributes\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('{')
+			v2First := true
+			for v2Name, v2Value := range in.Attributes {
+				if v2First {
+					v2First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v2Name))
+				out.RawByte(':')
+				out.String(string(v2Value))
+			}
+			out.RawByte('}')
+		}
+	}
 	out.RawByte('}')
 }
 
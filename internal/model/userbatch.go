@@ -0,0 +1,11 @@
+package model
+
+type UsersBatchRequest struct { //nolint:recvcheck // autogen issues
+	IDs []string `json:"ids"`
+}
+
+// UsersBatch — найденные пользователи по id и id, которых нет в реалме.
+type UsersBatch struct { //nolint:recvcheck // autogen issues
+	Users   map[string]User `json:"users"`
+	Missing []string        `json:"missing"`
+}
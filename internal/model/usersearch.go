@@ -0,0 +1,14 @@
+package model
+
+type FoundUser struct { //nolint:recvcheck // autogen issues
+	ID        string `json:"user_id"`
+	Login     string `json:"login"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// UsersPage — страница поиска; NextCursor пуст на последней странице.
+type UsersPage struct { //nolint:recvcheck // autogen issues
+	Users      []FoundUser `json:"users"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,96 @@
+package principal
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+const logKey = "principal"
+
+// Principal — аутентифицированный пользователь запроса, построенный по проверенному access token.
+type Principal struct {
+	Subject     string
+	Username    string
+	RealmRoles  []string
+	ClientRoles map[string][]string
+	Scopes      []string
+	SessionID   string
+	ExpiresAt   time.Time
+}
+
+func FromIntrospection(intro model.IntrospectDTO) Principal {
+	p := Principal{
+		Subject:    intro.Subject,
+		Username:   intro.Username,
+		RealmRoles: intro.RealmAccess.Roles,
+		Scopes:     intro.Scopes(),
+		SessionID:  intro.SessionID,
+	}
+
+	if p.SessionID == consts.EmptyString {
+		p.SessionID = intro.SessionState
+	}
+
+	if intro.ExpiresAt > 0 {
+		p.ExpiresAt = time.Unix(intro.ExpiresAt, 0)
+	}
+
+	if len(intro.ResourceAccess) > 0 {
+		p.ClientRoles = make(map[string][]string, len(intro.ResourceAccess))
+		for client, access := range intro.ResourceAccess {
+			p.ClientRoles[client] = access.Roles
+		}
+	}
+
+	return p
+}
+
+func (p Principal) HasRealmRole(role string) bool {
+	return slices.Contains(p.RealmRoles, role)
+}
+
+func (p Principal) HasClientRole(client string, role string) bool {
+	return slices.Contains(p.ClientRoles[client], role)
+}
+
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, consts.PrincipalContextKey, p)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(consts.PrincipalContextKey).(Principal)
+	return p, ok
+}
+
+// Attach переносит в ctx principal, который AuthMW положил в user values запроса fasthttp.
+func Attach(ctx context.Context, req *fasthttp.RequestCtx) context.Context {
+	p, ok := req.UserValue(consts.CtxPrincipalKey).(Principal)
+
+	if !ok {
+		return ctx
+	}
+
+	return WithPrincipal(ctx, p)
+}
+
+// LogAttr — subject пользователя для логов, пустой атрибут (не выводится), если запрос анонимный.
+func LogAttr(ctx context.Context) slog.Attr {
+	p, ok := FromContext(ctx)
+
+	if !ok {
+		return slog.Attr{}
+	}
+
+	return slog.String(logKey, p.Subject)
+}
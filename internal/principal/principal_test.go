@@ -0,0 +1,57 @@
+package principal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+func TestFromIntrospection(t *testing.T) {
+	t.Parallel()
+
+	exp := time.Now().Add(time.Minute).Truncate(time.Second)
+	p := FromIntrospection(model.IntrospectDTO{
+		Active:         true,
+		Subject:        "user-1",
+		Username:       "alice",
+		ExpiresAt:      exp.Unix(),
+		SessionState:   "sid-1",
+		Scope:          "openid  profile",
+		RealmAccess:    model.Access{Roles: []string{"admin"}},
+		ResourceAccess: map[string]model.Access{"noted-api": {Roles: []string{"editor"}}},
+	})
+
+	require.Equal(t, "user-1", p.Subject)
+	require.Equal(t, "alice", p.Username)
+	require.Equal(t, "sid-1", p.SessionID)
+	require.Equal(t, exp, p.ExpiresAt)
+	require.Equal(t, []string{"openid", "profile"}, p.Scopes)
+	require.True(t, p.HasRealmRole("admin"))
+	require.True(t, p.HasClientRole("noted-api", "editor"))
+	require.False(t, p.HasClientRole("other", "editor"))
+	require.True(t, p.HasScope("profile"))
+}
+
+func TestAttach(t *testing.T) {
+	t.Parallel()
+
+	req := &fasthttp.RequestCtx{}
+
+	_, ok := FromContext(Attach(context.Background(), req))
+	require.False(t, ok)
+	require.Empty(t, LogAttr(context.Background()).Key)
+
+	req.SetUserValue(consts.CtxPrincipalKey, Principal{Subject: "user-1"})
+	ctx := Attach(context.Background(), req)
+
+	p, ok := FromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, "user-1", p.Subject)
+	require.Equal(t, "user-1", LogAttr(ctx).Value.String())
+}
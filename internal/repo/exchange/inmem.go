@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+type lruEntry struct {
+	key       string
+	token     model.ExchangedToken
+	storedAt  time.Time
+	expiresAt time.Time
+}
+
+// InMemExchangeRepo — LRU обменянных токенов ограниченного размера, у каждой записи свой TTL.
+type InMemExchangeRepo struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+	logger  *slog.Logger
+}
+
+func NewInMemExchangeRepo(size int, logger *slog.Logger) *InMemExchangeRepo {
+	return &InMemExchangeRepo{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		logger:  logger,
+	}
+}
+
+// Get возвращает токен с ExpiresIn, уменьшенным на время, прошедшее с Set.
+func (er *InMemExchangeRepo) Get(ctx context.Context, key string) (model.ExchangedToken, error) {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+
+	elem, ok := er.entries[key]
+
+	if !ok {
+		return model.ExchangedToken{}, errorvals.ErrObjectNotFoundInRepoError
+	}
+
+	entry, _ := elem.Value.(*lruEntry)
+	now := time.Now()
+
+	if now.After(entry.expiresAt) {
+		er.logger.DebugContext(ctx, "Exchange cache entry expired")
+		er.remove(elem)
+		return model.ExchangedToken{}, errorvals.ErrObjectNotFoundInRepoError
+	}
+
+	er.order.MoveToFront(elem)
+
+	token := entry.token
+	token.ExpiresIn -= int(now.Sub(entry.storedAt) / time.Second)
+
+	return token, nil
+}
+
+func (er *InMemExchangeRepo) Set(ctx context.Context, key string, token model.ExchangedToken,
+	ttl time.Duration) error {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+
+	if elem, ok := er.entries[key]; ok {
+		er.remove(elem)
+	}
+
+	now := time.Now()
+	er.entries[key] = er.order.PushFront(&lruEntry{
+		key:       key,
+		token:     token,
+		storedAt:  now,
+		expiresAt: now.Add(ttl),
+	})
+
+	for er.order.Len() > er.size {
+		er.logger.DebugContext(ctx, "Evicting least recently used exchange cache entry")
+		er.remove(er.order.Back())
+	}
+
+	return nil
+}
+
+// remove должен вызываться под er.mu.
+func (er *InMemExchangeRepo) remove(elem *list.Element) {
+	entry, _ := er.order.Remove(elem).(*lruEntry)
+	delete(er.entries, entry.key)
+}
@@ -0,0 +1,63 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+}
+
+func TestInMemExchangeRepo_SetThenGet(t *testing.T) {
+	t.Parallel()
+
+	repo := NewInMemExchangeRepo(10, testLogger())
+	ctx := context.Background()
+
+	token := model.ExchangedToken{AccessToken: "at", ExpiresIn: 300}
+	require.NoError(t, repo.Set(ctx, "h1", token, time.Minute))
+
+	got, err := repo.Get(ctx, "h1")
+	require.NoError(t, err)
+	require.Equal(t, token, got)
+}
+
+func TestInMemExchangeRepo_Expired(t *testing.T) {
+	t.Parallel()
+
+	repo := NewInMemExchangeRepo(10, testLogger())
+	ctx := context.Background()
+
+	require.NoError(t, repo.Set(ctx, "h1", model.ExchangedToken{AccessToken: "at"}, time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+
+	_, err := repo.Get(ctx, "h1")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+}
+
+func TestInMemExchangeRepo_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	repo := NewInMemExchangeRepo(2, testLogger())
+	ctx := context.Background()
+
+	require.NoError(t, repo.Set(ctx, "h1", model.ExchangedToken{AccessToken: "1"}, time.Minute))
+	require.NoError(t, repo.Set(ctx, "h2", model.ExchangedToken{AccessToken: "2"}, time.Minute))
+	_, err := repo.Get(ctx, "h1")
+	require.NoError(t, err)
+	require.NoError(t, repo.Set(ctx, "h3", model.ExchangedToken{AccessToken: "3"}, time.Minute))
+
+	_, err = repo.Get(ctx, "h2")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+	_, err = repo.Get(ctx, "h1")
+	require.NoError(t, err)
+}
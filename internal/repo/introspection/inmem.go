@@ -0,0 +1,124 @@
+package introspection
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+type lruEntry struct {
+	key       string
+	dto       model.IntrospectDTO
+	sessionID string
+	expiresAt time.Time
+}
+
+// InMemIntrospectionRepo — LRU ограниченного размера, у каждой записи свой TTL.
+type InMemIntrospectionRepo struct {
+	mu       sync.Mutex
+	size     int
+	order    *list.List
+	entries  map[string]*list.Element
+	sessions map[string]map[string]struct{}
+	logger   *slog.Logger
+}
+
+func NewInMemIntrospectionRepo(size int, logger *slog.Logger) *InMemIntrospectionRepo {
+	return &InMemIntrospectionRepo{
+		size:     size,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		sessions: make(map[string]map[string]struct{}),
+		logger:   logger,
+	}
+}
+
+func (ir *InMemIntrospectionRepo) Get(ctx context.Context, key string) (model.IntrospectDTO, error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	elem, ok := ir.entries[key]
+
+	if !ok {
+		return model.IntrospectDTO{}, errorvals.ErrObjectNotFoundInRepoError
+	}
+
+	entry, _ := elem.Value.(*lruEntry)
+
+	if time.Now().After(entry.expiresAt) {
+		ir.logger.DebugContext(ctx, "Introspection cache entry expired")
+		ir.remove(elem)
+		return model.IntrospectDTO{}, errorvals.ErrObjectNotFoundInRepoError
+	}
+
+	ir.order.MoveToFront(elem)
+
+	return entry.dto, nil
+}
+
+func (ir *InMemIntrospectionRepo) Set(ctx context.Context, key string, sessionID string, dto model.IntrospectDTO,
+	ttl time.Duration) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if elem, ok := ir.entries[key]; ok {
+		ir.remove(elem)
+	}
+
+	elem := ir.order.PushFront(&lruEntry{
+		key:       key,
+		dto:       dto,
+		sessionID: sessionID,
+		expiresAt: time.Now().Add(ttl),
+	})
+	ir.entries[key] = elem
+
+	if sessionID != "" {
+		if ir.sessions[sessionID] == nil {
+			ir.sessions[sessionID] = make(map[string]struct{})
+		}
+		ir.sessions[sessionID][key] = struct{}{}
+	}
+
+	for ir.order.Len() > ir.size {
+		ir.logger.DebugContext(ctx, "Evicting least recently used introspection cache entry")
+		ir.remove(ir.order.Back())
+	}
+
+	return nil
+}
+
+func (ir *InMemIntrospectionRepo) PurgeSession(ctx context.Context, sessionID string) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	keys := ir.sessions[sessionID]
+	ir.logger.DebugContext(ctx, "Purging session from introspection cache", slog.Int("entries", len(keys)))
+
+	for key := range keys {
+		if elem, ok := ir.entries[key]; ok {
+			ir.remove(elem)
+		}
+	}
+	delete(ir.sessions, sessionID)
+
+	return nil
+}
+
+// remove должен вызываться под ir.mu.
+func (ir *InMemIntrospectionRepo) remove(elem *list.Element) {
+	entry, _ := ir.order.Remove(elem).(*lruEntry)
+	delete(ir.entries, entry.key)
+
+	if keys, ok := ir.sessions[entry.sessionID]; ok {
+		delete(keys, entry.key)
+		if len(keys) == 0 {
+			delete(ir.sessions, entry.sessionID)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package introspection
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+}
+
+func TestInMemIntrospectionRepo_SetThenGet_OK(t *testing.T) {
+	t.Parallel()
+
+	repo := NewInMemIntrospectionRepo(10, testLogger())
+	ctx := context.Background()
+
+	dto := model.IntrospectDTO{Active: true, Subject: "user-1", SessionID: "sid-1"}
+	require.NoError(t, repo.Set(ctx, "h1", "sid-1", dto, time.Minute))
+
+	got, err := repo.Get(ctx, "h1")
+	require.NoError(t, err)
+	require.Equal(t, dto, got)
+}
+
+func TestInMemIntrospectionRepo_Get_Expired(t *testing.T) {
+	t.Parallel()
+
+	repo := NewInMemIntrospectionRepo(10, testLogger())
+	ctx := context.Background()
+
+	require.NoError(t, repo.Set(ctx, "h1", "", model.IntrospectDTO{Active: true}, time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+
+	_, err := repo.Get(ctx, "h1")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+}
+
+func TestInMemIntrospectionRepo_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	repo := NewInMemIntrospectionRepo(2, testLogger())
+	ctx := context.Background()
+
+	require.NoError(t, repo.Set(ctx, "h1", "", model.IntrospectDTO{Subject: "1"}, time.Minute))
+	require.NoError(t, repo.Set(ctx, "h2", "", model.IntrospectDTO{Subject: "2"}, time.Minute))
+
+	// h1 становится самым свежим, вытесняется h2
+	_, err := repo.Get(ctx, "h1")
+	require.NoError(t, err)
+	require.NoError(t, repo.Set(ctx, "h3", "", model.IntrospectDTO{Subject: "3"}, time.Minute))
+
+	_, err = repo.Get(ctx, "h2")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+	_, err = repo.Get(ctx, "h1")
+	require.NoError(t, err)
+	_, err = repo.Get(ctx, "h3")
+	require.NoError(t, err)
+}
+
+func TestInMemIntrospectionRepo_PurgeSession(t *testing.T) {
+	t.Parallel()
+
+	repo := NewInMemIntrospectionRepo(10, testLogger())
+	ctx := context.Background()
+
+	require.NoError(t, repo.Set(ctx, "h1", "sid-1", model.IntrospectDTO{Active: true}, time.Minute))
+	require.NoError(t, repo.Set(ctx, "h2", "sid-1", model.IntrospectDTO{Active: true}, time.Minute))
+	require.NoError(t, repo.Set(ctx, "h3", "sid-2", model.IntrospectDTO{Active: true}, time.Minute))
+
+	require.NoError(t, repo.PurgeSession(ctx, "sid-1"))
+
+	_, err := repo.Get(ctx, "h1")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+	_, err = repo.Get(ctx, "h2")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+	_, err = repo.Get(ctx, "h3")
+	require.NoError(t, err)
+}
@@ -0,0 +1,94 @@
+package introspection
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	dbredis "github.com/dnonakolesax/noted-auth/internal/db/redis"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+const (
+	entryKeyPrefix   = "introspection:"
+	sessionKeyPrefix = "introspection-session:"
+)
+
+type RedisIntrospectionRepo struct {
+	client *dbredis.Client
+	maxTTL time.Duration
+	logger *slog.Logger
+}
+
+// NewRedisIntrospectionRepo принимает maxTTL, чтобы индекс сессии жил не дольше самых долгих записей.
+func NewRedisIntrospectionRepo(client *dbredis.Client, maxTTL time.Duration,
+	logger *slog.Logger) *RedisIntrospectionRepo {
+	return &RedisIntrospectionRepo{
+		client: client,
+		maxTTL: maxTTL,
+		logger: logger,
+	}
+}
+
+func (rr *RedisIntrospectionRepo) Get(ctx context.Context, key string) (model.IntrospectDTO, error) {
+	val, err := rr.client.Get(ctx, entryKeyPrefix+key)
+
+	if err != nil {
+		return model.IntrospectDTO{}, err
+	}
+
+	var dto model.IntrospectDTO
+	err = easyjson.Unmarshal([]byte(val), &dto)
+
+	if err != nil {
+		rr.logger.ErrorContext(ctx, "Failed to unmarshal cached introspection",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.IntrospectDTO{}, err
+	}
+
+	return dto, nil
+}
+
+func (rr *RedisIntrospectionRepo) Set(ctx context.Context, key string, sessionID string, dto model.IntrospectDTO,
+	ttl time.Duration) error {
+	bts, err := easyjson.Marshal(dto)
+
+	if err != nil {
+		rr.logger.ErrorContext(ctx, "Failed to marshal introspection",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return err
+	}
+
+	err = rr.client.Set(ctx, entryKeyPrefix+key, string(bts), ttl)
+
+	if err != nil {
+		return err
+	}
+
+	if sessionID == "" {
+		return nil
+	}
+
+	return rr.client.SAddWithTTL(ctx, sessionKeyPrefix+sessionID, key, rr.maxTTL)
+}
+
+func (rr *RedisIntrospectionRepo) PurgeSession(ctx context.Context, sessionID string) error {
+	keys, err := rr.client.SMembers(ctx, sessionKeyPrefix+sessionID)
+
+	if err != nil {
+		return err
+	}
+
+	toDelete := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		toDelete = append(toDelete, entryKeyPrefix+key)
+	}
+	toDelete = append(toDelete, sessionKeyPrefix+sessionID)
+
+	rr.logger.DebugContext(ctx, "Purging session from introspection cache", slog.Int("entries", len(keys)))
+
+	return rr.client.Del(ctx, toDelete...)
+}
@@ -0,0 +1,91 @@
+package introspection
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	dbredis "github.com/dnonakolesax/noted-auth/internal/db/redis"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+func newTestRedisRepo(t *testing.T) (*RedisIntrospectionRepo, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, portStr, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	cfg := &configs.RedisConfig{
+		Address:        host,
+		Port:           port,
+		RequestTimeout: 500 * time.Millisecond,
+	}
+
+	vaultCh := make(chan string)
+	t.Cleanup(func() { close(vaultCh) })
+
+	client, err := dbredis.NewClient(cfg, &atomic.Bool{}, testLogger(), vaultCh)
+	require.NoError(t, err)
+
+	return NewRedisIntrospectionRepo(client, time.Hour, testLogger()), mr
+}
+
+func TestRedisIntrospectionRepo_SetThenGet_OK(t *testing.T) {
+	t.Parallel()
+
+	repo, mr := newTestRedisRepo(t)
+	ctx := context.Background()
+
+	dto := model.IntrospectDTO{Active: true, Subject: "user-1", ExpiresAt: 42, SessionID: "sid-1"}
+	require.NoError(t, repo.Set(ctx, "h1", "sid-1", dto, time.Minute))
+
+	got, err := repo.Get(ctx, "h1")
+	require.NoError(t, err)
+	require.Equal(t, dto, got)
+	require.Equal(t, time.Minute, mr.TTL(entryKeyPrefix+"h1"))
+}
+
+func TestRedisIntrospectionRepo_Get_Expired(t *testing.T) {
+	t.Parallel()
+
+	repo, mr := newTestRedisRepo(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Set(ctx, "h1", "", model.IntrospectDTO{Active: true}, time.Second))
+	mr.FastForward(2 * time.Second)
+
+	_, err := repo.Get(ctx, "h1")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+}
+
+func TestRedisIntrospectionRepo_PurgeSession(t *testing.T) {
+	t.Parallel()
+
+	repo, mr := newTestRedisRepo(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Set(ctx, "h1", "sid-1", model.IntrospectDTO{Active: true}, time.Minute))
+	require.NoError(t, repo.Set(ctx, "h2", "sid-1", model.IntrospectDTO{Active: true}, time.Minute))
+	require.NoError(t, repo.Set(ctx, "h3", "sid-2", model.IntrospectDTO{Active: true}, time.Minute))
+
+	require.NoError(t, repo.PurgeSession(ctx, "sid-1"))
+
+	_, err := repo.Get(ctx, "h1")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+	_, err = repo.Get(ctx, "h2")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+	_, err = repo.Get(ctx, "h3")
+	require.NoError(t, err)
+	require.False(t, mr.Exists(sessionKeyPrefix+"sid-1"))
+}
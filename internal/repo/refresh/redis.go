@@ -0,0 +1,130 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	dbredis "github.com/dnonakolesax/noted-auth/internal/db/redis"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+)
+
+const keyPrefix = "refresh-used:"
+
+// markUsedScript запоминает время первого использования хэша refresh token (ARGV[1] = ARGV[2] мс)
+// в hash сессии KEYS[1] и продлевает его до ARGV[3] мс. Возвращает время первого использования,
+// если хэш уже был, иначе 0.
+//
+//nolint:gochecknoglobals // скрипт разбирается один раз, дальше вызывается по sha
+var markUsedScript = redis.NewScript(`
+local first = redis.call('HGET', KEYS[1], ARGV[1])
+if not first then
+	redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+end
+if redis.call('PTTL', KEYS[1]) < tonumber(ARGV[3]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+if first then
+	return tonumber(first)
+end
+return 0
+`)
+
+// usedAtScript возвращает время первого использования хэша ARGV[1] из hash сессии KEYS[1] или nil.
+//
+//nolint:gochecknoglobals // скрипт разбирается один раз, дальше вызывается по sha
+var usedAtScript = redis.NewScript(`
+local first = redis.call('HGET', KEYS[1], ARGV[1])
+if not first then
+	return nil
+end
+return tonumber(first)
+`)
+
+// unmarkScript удаляет хэш ARGV[1] из hash сессии KEYS[1].
+//
+//nolint:gochecknoglobals // скрипт разбирается один раз, дальше вызывается по sha
+var unmarkScript = redis.NewScript(`
+return redis.call('HDEL', KEYS[1], ARGV[1])
+`)
+
+// RedisRefreshRepo хранит хэши уже обменянных refresh token по сессиям keycloak.
+type RedisRefreshRepo struct {
+	client *dbredis.Client
+	logger *slog.Logger
+}
+
+func NewRedisRefreshRepo(client *dbredis.Client, logger *slog.Logger) *RedisRefreshRepo {
+	return &RedisRefreshRepo{
+		client: client,
+		logger: logger,
+	}
+}
+
+// MarkUsed отмечает refresh token использованным. Возвращает время первого использования,
+// если токен уже предъявлялся, и нулевое время, если это первое использование.
+// Запись о сессии живёт не меньше ttl.
+func (rr *RedisRefreshRepo) MarkUsed(ctx context.Context, sessionID string, tokenHash string,
+	ttl time.Duration) (time.Time, error) {
+	res, err := rr.client.RunScript(ctx, markUsedScript, []string{keyPrefix + sessionID}, tokenHash,
+		time.Now().UnixMilli(), max(ttl.Milliseconds(), 1))
+
+	if err != nil {
+		rr.logger.WarnContext(ctx, "Failed to mark refresh token used",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return time.Time{}, err
+	}
+
+	firstUsed, ok := res.(int64)
+
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected script result %T", res)
+	}
+
+	if firstUsed == 0 {
+		return time.Time{}, nil
+	}
+
+	return time.UnixMilli(firstUsed), nil
+}
+
+// UsedAt возвращает время первого использования refresh token или нулевое время, если он не предъявлялся.
+func (rr *RedisRefreshRepo) UsedAt(ctx context.Context, sessionID string, tokenHash string) (time.Time, error) {
+	res, err := rr.client.RunScript(ctx, usedAtScript, []string{keyPrefix + sessionID}, tokenHash)
+
+	if errors.Is(err, errorvals.ErrObjectNotFoundInRepoError) {
+		return time.Time{}, nil
+	}
+
+	if err != nil {
+		rr.logger.WarnContext(ctx, "Failed to check refresh token usage",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return time.Time{}, err
+	}
+
+	firstUsed, ok := res.(int64)
+
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected script result %T", res)
+	}
+
+	return time.UnixMilli(firstUsed), nil
+}
+
+// Unmark снимает отметку об использовании refresh token.
+func (rr *RedisRefreshRepo) Unmark(ctx context.Context, sessionID string, tokenHash string) error {
+	_, err := rr.client.RunScript(ctx, unmarkScript, []string{keyPrefix + sessionID}, tokenHash)
+
+	if err != nil {
+		rr.logger.WarnContext(ctx, "Failed to unmark refresh token",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return err
+	}
+
+	return nil
+}
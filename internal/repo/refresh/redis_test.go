@@ -0,0 +1,111 @@
+package refresh
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	dbredis "github.com/dnonakolesax/noted-auth/internal/db/redis"
+)
+
+func newTestRepo(t *testing.T) (*RedisRefreshRepo, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, portStr, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	vaultCh := make(chan string)
+	t.Cleanup(func() { close(vaultCh) })
+
+	client, err := dbredis.NewClient(&configs.RedisConfig{
+		Address:        host,
+		Port:           port,
+		RequestTimeout: 500 * time.Millisecond,
+	}, &atomic.Bool{}, logger, vaultCh)
+	require.NoError(t, err)
+
+	return NewRedisRefreshRepo(client, logger), mr
+}
+
+func TestRedisRefreshRepo_MarkUsed_DetectsReuse(t *testing.T) {
+	t.Parallel()
+
+	repo, mr := newTestRepo(t)
+	ctx := context.Background()
+
+	before := time.Now().Add(-time.Second)
+
+	first, err := repo.MarkUsed(ctx, "sid-1", "rt-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, first.IsZero())
+
+	// другой токен той же сессии и тот же токен другой сессии — не повтор
+	first, err = repo.MarkUsed(ctx, "sid-1", "rt-2", time.Minute)
+	require.NoError(t, err)
+	require.True(t, first.IsZero())
+
+	first, err = repo.MarkUsed(ctx, "sid-2", "rt-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, first.IsZero())
+
+	first, err = repo.MarkUsed(ctx, "sid-1", "rt-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, first.After(before))
+
+	require.Equal(t, time.Minute, mr.TTL(keyPrefix+"sid-1"))
+}
+
+func TestRedisRefreshRepo_MarkUsed_OnlyExtendsTTL(t *testing.T) {
+	t.Parallel()
+
+	repo, mr := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.MarkUsed(ctx, "sid", "rt-1", time.Hour)
+	require.NoError(t, err)
+
+	_, err = repo.MarkUsed(ctx, "sid", "rt-2", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, mr.TTL(keyPrefix+"sid"))
+}
+
+func TestRedisRefreshRepo_UsedAtAndUnmark(t *testing.T) {
+	t.Parallel()
+
+	repo, _ := newTestRepo(t)
+	ctx := context.Background()
+
+	used, err := repo.UsedAt(ctx, "sid", "rt-1")
+	require.NoError(t, err)
+	require.True(t, used.IsZero())
+
+	_, err = repo.MarkUsed(ctx, "sid", "rt-1", time.Minute)
+	require.NoError(t, err)
+	used, err = repo.UsedAt(ctx, "sid", "rt-1")
+	require.NoError(t, err)
+	require.False(t, used.IsZero())
+
+	require.NoError(t, repo.Unmark(ctx, "sid", "rt-1"))
+	used, err = repo.UsedAt(ctx, "sid", "rt-1")
+	require.NoError(t, err)
+	require.True(t, used.IsZero())
+
+	// после снятия отметки токен снова считается неиспользованным
+	first, err := repo.MarkUsed(ctx, "sid", "rt-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, first.IsZero())
+}
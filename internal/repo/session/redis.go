@@ -0,0 +1,96 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	dbredis "github.com/dnonakolesax/noted-auth/internal/db/redis"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+const keyPrefix = "websession:"
+
+type sealer interface {
+	Seal(plaintext []byte, additionalData []byte) ([]byte, error)
+	Open(sealed []byte, additionalData []byte) ([]byte, error)
+}
+
+// RedisSessionRepo хранит токены серверных сессий в зашифрованном виде. Ключом служит хэш id сессии,
+// поэтому по содержимому redis нельзя восстановить cookie.
+type RedisSessionRepo struct {
+	client *dbredis.Client
+	sealer sealer
+	logger *slog.Logger
+}
+
+func NewRedisSessionRepo(client *dbredis.Client, sealer sealer, logger *slog.Logger) *RedisSessionRepo {
+	return &RedisSessionRepo{
+		client: client,
+		sealer: sealer,
+		logger: logger,
+	}
+}
+
+func redisKey(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return keyPrefix + hex.EncodeToString(sum[:])
+}
+
+func (sr *RedisSessionRepo) Save(ctx context.Context, id string, tokens model.TokenDTO, ttl time.Duration) error {
+	bts, err := easyjson.Marshal(tokens)
+
+	if err != nil {
+		sr.logger.ErrorContext(ctx, "Failed to marshal session tokens",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return err
+	}
+
+	key := redisKey(id)
+	sealed, err := sr.sealer.Seal(bts, []byte(key))
+
+	if err != nil {
+		sr.logger.ErrorContext(ctx, "Failed to seal session tokens",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return err
+	}
+
+	return sr.client.Set(ctx, key, string(sealed), ttl)
+}
+
+func (sr *RedisSessionRepo) Get(ctx context.Context, id string) (model.TokenDTO, error) {
+	key := redisKey(id)
+	val, err := sr.client.Get(ctx, key)
+
+	if err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	bts, err := sr.sealer.Open([]byte(val), []byte(key))
+
+	if err != nil {
+		sr.logger.WarnContext(ctx, "Failed to open session tokens",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.TokenDTO{}, err
+	}
+
+	var tokens model.TokenDTO
+	err = easyjson.Unmarshal(bts, &tokens)
+
+	if err != nil {
+		sr.logger.ErrorContext(ctx, "Failed to unmarshal session tokens",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.TokenDTO{}, err
+	}
+
+	return tokens, nil
+}
+
+func (sr *RedisSessionRepo) Delete(ctx context.Context, id string) error {
+	return sr.client.Del(ctx, redisKey(id))
+}
@@ -0,0 +1,99 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	dbredis "github.com/dnonakolesax/noted-auth/internal/db/redis"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/seal"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+}
+
+func newTestRepo(t *testing.T) (*RedisSessionRepo, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, portStr, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	vaultCh := make(chan string)
+	t.Cleanup(func() { close(vaultCh) })
+
+	client, err := dbredis.NewClient(&configs.RedisConfig{
+		Address:        host,
+		Port:           port,
+		RequestTimeout: 500 * time.Millisecond,
+	}, &atomic.Bool{}, testLogger(), vaultCh)
+	require.NoError(t, err)
+
+	sealCh := make(chan string)
+	t.Cleanup(func() { close(sealCh) })
+
+	sealer, err := seal.NewSealer(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32)),
+		testLogger(), sealCh)
+	require.NoError(t, err)
+
+	return NewRedisSessionRepo(client, sealer, testLogger()), mr
+}
+
+func TestRedisSessionRepo_SaveGetDelete(t *testing.T) {
+	t.Parallel()
+
+	repo, mr := newTestRepo(t)
+	ctx := context.Background()
+
+	tokens := model.TokenDTO{AccessToken: "at-secret", RefreshToken: "rt-secret", IDToken: "idt", RefreshExp: 60}
+	require.NoError(t, repo.Save(ctx, "sid-1", tokens, time.Minute))
+
+	// ни id сессии, ни токены не лежат в redis в открытом виде
+	for _, key := range mr.Keys() {
+		require.NotContains(t, key, "sid-1")
+		val, err := mr.Get(key)
+		require.NoError(t, err)
+		require.NotContains(t, val, "at-secret")
+		require.NotContains(t, val, "rt-secret")
+		require.Equal(t, time.Minute, mr.TTL(key))
+	}
+
+	got, err := repo.Get(ctx, "sid-1")
+	require.NoError(t, err)
+	require.Equal(t, tokens, got)
+
+	require.NoError(t, repo.Delete(ctx, "sid-1"))
+	_, err = repo.Get(ctx, "sid-1")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+}
+
+func TestRedisSessionRepo_Get_ValueMovedToOtherKeyRejected(t *testing.T) {
+	t.Parallel()
+
+	repo, mr := newTestRepo(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Save(ctx, "sid-victim", model.TokenDTO{AccessToken: "at"}, time.Minute))
+	val, err := mr.Get(redisKey("sid-victim"))
+	require.NoError(t, err)
+	require.NoError(t, mr.Set(redisKey("sid-attacker"), val))
+
+	_, err = repo.Get(ctx, "sid-attacker")
+	require.ErrorIs(t, err, errorvals.ErrSealedDataInvalid)
+}
@@ -4,17 +4,21 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/muesli/cache2go"
 
 	"github.com/dnonakolesax/noted-auth/internal/consts"
 	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
 )
 
 type InMemStateRepo struct {
 	client *cache2go.CacheTable
-	logger *slog.Logger
+	// consumeMu делает пару Value+Delete в ConsumeState атомарной
+	consumeMu sync.Mutex
+	logger    *slog.Logger
 }
 
 func NewInMemStateRepo(logger *slog.Logger) *InMemStateRepo {
@@ -25,34 +29,65 @@ func NewInMemStateRepo(logger *slog.Logger) *InMemStateRepo {
 	}
 }
 
-func (sr *InMemStateRepo) SetState(ctx context.Context, state string, redirectURI string, timeout time.Duration) error {
+func (sr *InMemStateRepo) SetState(ctx context.Context, state string, record model.AuthState,
+	timeout time.Duration) error {
 	sr.logger.DebugContext(ctx, "Adding state to in-memory cache")
-	sr.client.Add(state, timeout, redirectURI)
+	sr.client.Add(state, timeout, record)
 
 	return nil
 }
 
-func (sr *InMemStateRepo) GetState(ctx context.Context, state string) (string, error) {
+func (sr *InMemStateRepo) GetState(ctx context.Context, state string) (model.AuthState, error) {
 	sr.logger.DebugContext(ctx, "Getting state from in-memory cache")
 	val, err := sr.client.Value(state)
 
 	if err != nil {
 		if errors.Is(err, cache2go.ErrKeyNotFound) {
 			sr.logger.WarnContext(ctx, "Key not found in in-memory cache")
-			return "", errorvals.ErrObjectNotFoundInRepoError
+			return model.AuthState{}, errorvals.ErrObjectNotFoundInRepoError
 		}
 		sr.logger.ErrorContext(ctx, "Error getting state from in-memory cache",
 			slog.String(consts.ErrorLoggerKey, err.Error()))
-		return "", err
+		return model.AuthState{}, err
 	}
 	sr.logger.DebugContext(ctx, "Got state from in-memory cache")
 
-	stringData, ok := val.Data().(string)
+	record, ok := val.Data().(model.AuthState)
 
 	if !ok {
-		sr.logger.ErrorContext(ctx, "Failed to cast in-memory cache data to string")
-		return "", errors.New("failed to cast data to string")
+		sr.logger.ErrorContext(ctx, "Failed to cast in-memory cache data to auth state")
+		return model.AuthState{}, errors.New("failed to cast data to auth state")
 	}
 
-	return stringData, nil
+	return record, nil
+}
+
+// ConsumeState забирает state и удаляет его. Повторный вызов в течение replayWindow
+// возвращает errorvals.ErrStateReplayed, а не ErrObjectNotFoundInRepoError.
+func (sr *InMemStateRepo) ConsumeState(ctx context.Context, state string,
+	replayWindow time.Duration) (model.AuthState, error) {
+	sr.consumeMu.Lock()
+	defer sr.consumeMu.Unlock()
+
+	record, err := sr.GetState(ctx, state)
+
+	if errors.Is(err, errorvals.ErrObjectNotFoundInRepoError) && sr.client.Exists(state+consumedSuffix) {
+		sr.logger.WarnContext(ctx, "State already consumed")
+		return model.AuthState{}, errorvals.ErrStateReplayed
+	}
+
+	if err != nil {
+		return model.AuthState{}, err
+	}
+
+	_, err = sr.client.Delete(state)
+
+	if err != nil && !errors.Is(err, cache2go.ErrKeyNotFound) {
+		sr.logger.ErrorContext(ctx, "Error deleting state from in-memory cache",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.AuthState{}, err
+	}
+	sr.client.Add(state+consumedSuffix, replayWindow, struct{}{})
+
+	return record, nil
 }
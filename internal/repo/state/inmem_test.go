@@ -4,6 +4,8 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,8 +13,21 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
 )
 
+func testRecord() model.AuthState {
+	return model.AuthState{
+		ReturnURL:     "https://example.com/cb",
+		CodeVerifier:  "verifier",
+		Nonce:         "nonce",
+		CreatedAt:     1700000000,
+		ClientIPHash:  "ip-hash",
+		UserAgentHash: "ua-hash",
+		Scopes:        []string{"openid"},
+	}
+}
+
 func TestInMemStateRepo_SetThenGet_OK(t *testing.T) {
 	t.Parallel()
 
@@ -20,11 +35,11 @@ func TestInMemStateRepo_SetThenGet_OK(t *testing.T) {
 	repo := NewInMemStateRepo(logger)
 
 	ctx := context.Background()
-	require.NoError(t, repo.SetState(ctx, "state-1", "https://example.com/cb", 2*time.Second))
+	require.NoError(t, repo.SetState(ctx, "state-1", testRecord(), 2*time.Second))
 
 	got, err := repo.GetState(ctx, "state-1")
 	require.NoError(t, err)
-	require.Equal(t, "https://example.com/cb", got)
+	require.Equal(t, testRecord(), got)
 }
 
 func TestInMemStateRepo_Get_NotFound(t *testing.T) {
@@ -43,12 +58,12 @@ func TestInMemStateRepo_Get_CastError(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 	repo := NewInMemStateRepo(logger)
 
-	// кладём не-string, чтобы сломать каст
+	// кладём не AuthState, чтобы сломать каст
 	repo.client.Add("state-bad", 2*time.Second, 123)
 
 	_, err := repo.GetState(context.Background(), "state-bad")
 	require.Error(t, err)
-	require.Equal(t, "failed to cast data to string", err.Error())
+	require.Equal(t, "failed to cast data to auth state", err.Error())
 }
 
 func TestInMemStateRepo_Cache2goErrKeyNotFound_IsReal(t *testing.T) {
@@ -58,3 +73,58 @@ func TestInMemStateRepo_Cache2goErrKeyNotFound_IsReal(t *testing.T) {
 	_, err := cache2go.Cache("state").Value("missing")
 	require.ErrorIs(t, err, cache2go.ErrKeyNotFound)
 }
+
+func TestInMemStateRepo_ConsumeState_SingleUse(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	repo := NewInMemStateRepo(logger)
+
+	ctx := context.Background()
+	require.NoError(t, repo.SetState(ctx, "state-consume", testRecord(), 2*time.Second))
+
+	got, err := repo.ConsumeState(ctx, "state-consume", 2*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, testRecord(), got)
+
+	_, err = repo.ConsumeState(ctx, "state-consume", 2*time.Second)
+	require.ErrorIs(t, err, errorvals.ErrStateReplayed)
+
+	_, err = repo.GetState(ctx, "state-consume")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+}
+
+func TestInMemStateRepo_ConsumeState_NotFound(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	repo := NewInMemStateRepo(logger)
+
+	_, err := repo.ConsumeState(context.Background(), "state-never-set", time.Second)
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+}
+
+func TestInMemStateRepo_ConsumeState_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	repo := NewInMemStateRepo(logger)
+
+	ctx := context.Background()
+	require.NoError(t, repo.SetState(ctx, "state-race", testRecord(), 2*time.Second))
+
+	var wins atomic.Int32
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.ConsumeState(ctx, "state-race", 2*time.Second); err == nil {
+				wins.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), wins.Load())
+}
@@ -5,10 +5,35 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/mailru/easyjson"
+	"github.com/redis/go-redis/v9"
+
 	"github.com/dnonakolesax/noted-auth/internal/consts"
 	dbredis "github.com/dnonakolesax/noted-auth/internal/db/redis"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
 )
 
+// consumedSuffix — суффикс ключа-метки использованного state.
+const consumedSuffix = ":consumed"
+
+// consumeScript атомарно забирает значение и оставляет вместо него метку на ARGV[1] мс.
+// Возвращает значение, 0 если метка уже стоит (повтор), nil если ключа не было.
+//
+//nolint:gochecknoglobals // скрипт разбирается один раз, дальше вызывается по sha
+var consumeScript = redis.NewScript(`
+local val = redis.call('GET', KEYS[1])
+if val then
+	redis.call('DEL', KEYS[1])
+	redis.call('SET', KEYS[2], '1', 'PX', ARGV[1])
+	return val
+end
+if redis.call('EXISTS', KEYS[2]) == 1 then
+	return 0
+end
+return false
+`)
+
 type RedisStateRepo struct {
 	client *dbredis.Client
 	logger *slog.Logger
@@ -21,11 +46,19 @@ func NewRedisStateRepo(client *dbredis.Client, logger *slog.Logger) *RedisStateR
 	}
 }
 
-func (rr *RedisStateRepo) SetState(ctx context.Context, state string, redirectURI string, timeout time.Duration) error {
-	rr.logger.DebugContext(ctx, "Setting state", "state", state, "redirectURI", redirectURI)
+func (rr *RedisStateRepo) SetState(ctx context.Context, state string, record model.AuthState,
+	timeout time.Duration) error {
+	rr.logger.DebugContext(ctx, "Setting state", "state", state, "redirectURI", record.ReturnURL)
+	bts, err := easyjson.Marshal(record)
+
+	if err != nil {
+		rr.logger.ErrorContext(ctx, "Failed to marshal state", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return err
+	}
+
 	dbctx, cancel := context.WithTimeout(ctx, rr.client.Timeout)
 	defer cancel()
-	rsp := rr.client.Client.Set(dbctx, state, redirectURI, timeout)
+	rsp := rr.client.Client.Set(dbctx, state, bts, timeout)
 
 	if rsp.Err() != nil {
 		rr.logger.ErrorContext(ctx, "Failed to set state", slog.String(consts.ErrorLoggerKey, rsp.Err().Error()))
@@ -36,16 +69,53 @@ func (rr *RedisStateRepo) SetState(ctx context.Context, state string, redirectUR
 	return nil
 }
 
-func (rr *RedisStateRepo) GetState(ctx context.Context, state string) (string, error) {
+func (rr *RedisStateRepo) GetState(ctx context.Context, state string) (model.AuthState, error) {
 	rr.logger.DebugContext(ctx, "Getting state", "state", state)
 	val, err := rr.client.Get(ctx, state)
 
 	if err != nil {
 		rr.logger.ErrorContext(ctx, "Failed to get state", slog.String(consts.ErrorLoggerKey, err.Error()))
-		return "", err
+		return model.AuthState{}, err
 	}
 
 	rr.logger.DebugContext(ctx, "Got state")
 
-	return val, nil
+	return rr.unmarshal(ctx, val)
+}
+
+// ConsumeState забирает state и удаляет его. Повторный вызов в течение replayWindow
+// возвращает errorvals.ErrStateReplayed, а не ErrObjectNotFoundInRepoError.
+func (rr *RedisStateRepo) ConsumeState(ctx context.Context, state string,
+	replayWindow time.Duration) (model.AuthState, error) {
+	rr.logger.DebugContext(ctx, "Consuming state", "state", state)
+	res, err := rr.client.RunScript(ctx, consumeScript, []string{state, state + consumedSuffix},
+		max(replayWindow.Milliseconds(), 1))
+
+	if err != nil {
+		rr.logger.WarnContext(ctx, "Failed to consume state", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.AuthState{}, err
+	}
+
+	val, ok := res.(string)
+
+	if !ok {
+		rr.logger.WarnContext(ctx, "State already consumed", "state", state)
+		return model.AuthState{}, errorvals.ErrStateReplayed
+	}
+
+	rr.logger.DebugContext(ctx, "Consumed state")
+
+	return rr.unmarshal(ctx, val)
+}
+
+func (rr *RedisStateRepo) unmarshal(ctx context.Context, val string) (model.AuthState, error) {
+	var record model.AuthState
+	err := easyjson.Unmarshal([]byte(val), &record)
+
+	if err != nil {
+		rr.logger.ErrorContext(ctx, "Failed to unmarshal state", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.AuthState{}, err
+	}
+
+	return record, nil
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/dnonakolesax/noted-auth/internal/configs"
 	dbredis "github.com/dnonakolesax/noted-auth/internal/db/redis"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
 )
 
 func TestRedisStateRepo_SetThenGet_OK(t *testing.T) {
@@ -45,9 +46,54 @@ func TestRedisStateRepo_SetThenGet_OK(t *testing.T) {
 	repo := NewRedisStateRepo(client, logger)
 
 	ctx := context.Background()
-	require.NoError(t, repo.SetState(ctx, "s1", "https://example.com/cb", 2*time.Second))
+	require.NoError(t, repo.SetState(ctx, "s1", testRecord(), 2*time.Second))
 
 	got, err := repo.GetState(ctx, "s1")
 	require.NoError(t, err)
-	require.Equal(t, "https://example.com/cb", got)
+	require.Equal(t, testRecord(), got)
+}
+
+func TestRedisStateRepo_ConsumeState_SingleUse(t *testing.T) {
+	t.Parallel()
+
+	mr := miniredis.RunT(t)
+	host, portStr, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	cfg := &configs.RedisConfig{
+		Address:        host,
+		Port:           port,
+		RequestTimeout: 500 * time.Millisecond,
+	}
+
+	vaultCh := make(chan string)
+	t.Cleanup(func() { close(vaultCh) })
+
+	client, err := dbredis.NewClient(cfg, &atomic.Bool{}, logger, vaultCh)
+	require.NoError(t, err)
+
+	repo := NewRedisStateRepo(client, logger)
+
+	ctx := context.Background()
+	require.NoError(t, repo.SetState(ctx, "s1", testRecord(), 2*time.Second))
+
+	got, err := repo.ConsumeState(ctx, "s1", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, testRecord(), got)
+	require.False(t, mr.Exists("s1"))
+
+	_, err = repo.ConsumeState(ctx, "s1", time.Minute)
+	require.ErrorIs(t, err, errorvals.ErrStateReplayed)
+
+	_, err = repo.ConsumeState(ctx, "never-set", time.Minute)
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+
+	// после окна повтора метка пропадает
+	mr.FastForward(2 * time.Minute)
+	_, err = repo.ConsumeState(ctx, "s1", time.Minute)
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
 }
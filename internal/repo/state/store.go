@@ -0,0 +1,239 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+type Backend interface {
+	SetState(ctx context.Context, state string, record model.AuthState, timeout time.Duration) error
+	GetState(ctx context.Context, state string) (model.AuthState, error)
+	ConsumeState(ctx context.Context, state string, replayWindow time.Duration) (model.AuthState, error)
+}
+
+// Store объединяет несколько репозиториев state. backends[0] — основной, остальные — реплики.
+// Фоновые записи выполняются ограниченным числом воркеров через очередь фиксированного размера.
+type Store struct {
+	backends []Backend
+	config   configs.StateStoreConfig
+	jobs     chan func()
+	wg       sync.WaitGroup
+	logger   *slog.Logger
+}
+
+func NewStore(config configs.StateStoreConfig, logger *slog.Logger, backends ...Backend) (*Store, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("state store needs at least one backend")
+	}
+
+	switch config.WritePolicy {
+	case configs.StateWriteAllSync, configs.StateWritePrimaryAsync, configs.StateWriteQuorum:
+	default:
+		return nil, fmt.Errorf("unknown state write policy %q", config.WritePolicy)
+	}
+
+	switch config.ReadPolicy {
+	case configs.StateReadFirstHit, configs.StateReadPrimaryOnly:
+	default:
+		return nil, fmt.Errorf("unknown state read policy %q", config.ReadPolicy)
+	}
+
+	s := &Store{
+		backends: backends,
+		config:   config,
+		jobs:     make(chan func(), max(config.QueueSize, 0)),
+		logger:   logger,
+	}
+
+	for range max(config.Workers, 1) {
+		s.wg.Add(1)
+		go s.worker()
+	}
+
+	return s, nil
+}
+
+func (s *Store) worker() {
+	defer s.wg.Done()
+	for job := range s.jobs {
+		job()
+	}
+}
+
+// Close дожидается выполнения уже поставленных в очередь фоновых записей.
+func (s *Store) Close() {
+	close(s.jobs)
+	s.wg.Wait()
+}
+
+// background ставит запись в очередь. Контекст запроса не используется: он отменится раньше,
+// чем воркер дойдёт до задачи, поэтому берутся только его значения (trace).
+func (s *Store) background(ctx context.Context, name string, op func(ctx context.Context) error) {
+	bgCtx := context.WithoutCancel(ctx)
+	job := func() {
+		jobCtx, cancel := context.WithTimeout(bgCtx, s.config.ReplicationTimeout)
+		defer cancel()
+
+		if err := op(jobCtx); err != nil {
+			s.logger.ErrorContext(jobCtx, "Background state operation failed", slog.String("operation", name),
+				slog.String(consts.ErrorLoggerKey, err.Error()))
+		}
+	}
+
+	select {
+	case s.jobs <- job:
+	default:
+		s.logger.WarnContext(ctx, "State replication queue is full, dropping job", slog.String("operation", name))
+	}
+}
+
+func (s *Store) SetState(ctx context.Context, state string, record model.AuthState, timeout time.Duration) error {
+	switch s.config.WritePolicy {
+	case configs.StateWritePrimaryAsync:
+		err := s.backends[0].SetState(ctx, state, record, timeout)
+
+		if err != nil {
+			return err
+		}
+
+		for _, backend := range s.backends[1:] {
+			s.background(ctx, "set", func(ctx context.Context) error {
+				return backend.SetState(ctx, state, record, timeout)
+			})
+		}
+
+		return nil
+	case configs.StateWriteQuorum:
+		errs := s.setAll(ctx, state, record, timeout)
+		failed := 0
+
+		for _, err := range errs {
+			if err != nil {
+				failed++
+			}
+		}
+
+		if len(s.backends)-failed > len(s.backends)/2 {
+			return nil
+		}
+
+		return fmt.Errorf("state write quorum not reached (%d of %d failed): %w", failed, len(s.backends),
+			errors.Join(errs...))
+	default:
+		return errors.Join(s.setAll(ctx, state, record, timeout)...)
+	}
+}
+
+// setAll пишет во все репозитории параллельно и возвращает ошибки по индексам репозиториев.
+func (s *Store) setAll(ctx context.Context, state string, record model.AuthState, timeout time.Duration) []error {
+	errs := make([]error, len(s.backends))
+	wg := sync.WaitGroup{}
+
+	for i, backend := range s.backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = backend.SetState(ctx, state, record, timeout)
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func (s *Store) GetState(ctx context.Context, state string) (model.AuthState, error) {
+	if s.config.ReadPolicy == configs.StateReadPrimaryOnly {
+		return s.backends[0].GetState(ctx, state)
+	}
+
+	var lastErr error
+
+	for _, backend := range s.backends {
+		record, err := backend.GetState(ctx, state)
+
+		if err == nil {
+			return record, nil
+		}
+
+		if !errors.Is(err, errorvals.ErrObjectNotFoundInRepoError) {
+			s.logger.WarnContext(ctx, "Failed to read state from backend, trying next",
+				slog.String(consts.ErrorLoggerKey, err.Error()))
+			lastErr = err
+		}
+	}
+
+	if lastErr != nil {
+		return model.AuthState{}, lastErr
+	}
+
+	return model.AuthState{}, errorvals.ErrObjectNotFoundInRepoError
+}
+
+// ConsumeState забирает state из всех репозиториев, чтобы копия в реплике не осталась пригодной
+// для повтора. Повтор хотя бы в одном из них считается повтором: иначе два параллельных
+// callback'а могли бы забрать один state из разных репозиториев.
+func (s *Store) ConsumeState(ctx context.Context, state string, replayWindow time.Duration) (model.AuthState, error) {
+	if s.config.ReadPolicy == configs.StateReadPrimaryOnly {
+		record, err := s.backends[0].ConsumeState(ctx, state, replayWindow)
+
+		if err != nil {
+			return model.AuthState{}, err
+		}
+
+		for _, backend := range s.backends[1:] {
+			s.background(ctx, "consume", func(ctx context.Context) error {
+				_, cerr := backend.ConsumeState(ctx, state, replayWindow)
+				if errors.Is(cerr, errorvals.ErrObjectNotFoundInRepoError) {
+					return nil
+				}
+				return cerr
+			})
+		}
+
+		return record, nil
+	}
+
+	var record model.AuthState
+	var lastErr error
+	found := false
+	replayed := false
+
+	for _, backend := range s.backends {
+		got, err := backend.ConsumeState(ctx, state, replayWindow)
+
+		switch {
+		case err == nil:
+			if !found {
+				record = got
+				found = true
+			}
+		case errors.Is(err, errorvals.ErrStateReplayed):
+			replayed = true
+		case errors.Is(err, errorvals.ErrObjectNotFoundInRepoError):
+		default:
+			s.logger.WarnContext(ctx, "Failed to consume state from backend",
+				slog.String(consts.ErrorLoggerKey, err.Error()))
+			lastErr = err
+		}
+	}
+
+	switch {
+	case replayed:
+		return model.AuthState{}, errorvals.ErrStateReplayed
+	case found:
+		return record, nil
+	case lastErr != nil:
+		return model.AuthState{}, lastErr
+	default:
+		return model.AuthState{}, errorvals.ErrObjectNotFoundInRepoError
+	}
+}
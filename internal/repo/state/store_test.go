@@ -0,0 +1,231 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	dbredis "github.com/dnonakolesax/noted-auth/internal/db/redis"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+var errBackendDown = errors.New("backend down")
+
+type failingBackend struct{}
+
+func (failingBackend) SetState(context.Context, string, model.AuthState, time.Duration) error {
+	return errBackendDown
+}
+
+func (failingBackend) GetState(context.Context, string) (model.AuthState, error) {
+	return model.AuthState{}, errBackendDown
+}
+
+func (failingBackend) ConsumeState(context.Context, string, time.Duration) (model.AuthState, error) {
+	return model.AuthState{}, errBackendDown
+}
+
+func newStoreBackends(t *testing.T) (*InMemStateRepo, *RedisStateRepo) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, portStr, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	vaultCh := make(chan string)
+	t.Cleanup(func() { close(vaultCh) })
+
+	client, err := dbredis.NewClient(&configs.RedisConfig{
+		Address:        host,
+		Port:           port,
+		RequestTimeout: 500 * time.Millisecond,
+	}, &atomic.Bool{}, logger, vaultCh)
+	require.NoError(t, err)
+
+	return NewInMemStateRepo(logger), NewRedisStateRepo(client, logger)
+}
+
+func newTestStore(t *testing.T, write string, read string, backends ...Backend) *Store {
+	t.Helper()
+
+	store, err := NewStore(configs.StateStoreConfig{
+		WritePolicy:        write,
+		ReadPolicy:         read,
+		Workers:            2,
+		QueueSize:          16,
+		ReplicationTimeout: time.Second,
+	}, slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})), backends...)
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestStore_WriteAllSync_WritesEveryBackend(t *testing.T) {
+	t.Parallel()
+
+	inmem, redis := newStoreBackends(t)
+	store := newTestStore(t, configs.StateWriteAllSync, configs.StateReadFirstHit, inmem, redis)
+	t.Cleanup(store.Close)
+
+	ctx := context.Background()
+	require.NoError(t, store.SetState(ctx, "store-all-sync", testRecord(), time.Minute))
+
+	for _, backend := range []Backend{inmem, redis} {
+		got, err := backend.GetState(ctx, "store-all-sync")
+		require.NoError(t, err)
+		require.Equal(t, testRecord(), got)
+	}
+
+	failing := newTestStore(t, configs.StateWriteAllSync, configs.StateReadFirstHit, redis, failingBackend{})
+	t.Cleanup(failing.Close)
+	require.ErrorIs(t, failing.SetState(ctx, "store-all-sync-2", testRecord(), time.Minute), errBackendDown)
+}
+
+func TestStore_WritePrimaryAsync_ReplicatesInBackground(t *testing.T) {
+	t.Parallel()
+
+	inmem, redis := newStoreBackends(t)
+	store := newTestStore(t, configs.StateWritePrimaryAsync, configs.StateReadFirstHit, inmem, redis)
+
+	// контекст запроса отменяется сразу после ответа — репликация не должна от этого ломаться
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, store.SetState(ctx, "store-async", testRecord(), time.Minute))
+	cancel()
+
+	_, err := inmem.GetState(context.Background(), "store-async")
+	require.NoError(t, err)
+
+	store.Close() // дожидаемся воркеров
+
+	got, err := redis.GetState(context.Background(), "store-async")
+	require.NoError(t, err)
+	require.Equal(t, testRecord(), got)
+}
+
+func TestStore_WritePrimaryAsync_PrimaryErrorFails(t *testing.T) {
+	t.Parallel()
+
+	inmem, _ := newStoreBackends(t)
+	store := newTestStore(t, configs.StateWritePrimaryAsync, configs.StateReadFirstHit, failingBackend{}, inmem)
+	t.Cleanup(store.Close)
+
+	require.ErrorIs(t, store.SetState(context.Background(), "store-async-fail", testRecord(), time.Minute),
+		errBackendDown)
+}
+
+func TestStore_WriteQuorum(t *testing.T) {
+	t.Parallel()
+
+	inmem, redis := newStoreBackends(t)
+	ctx := context.Background()
+
+	majority := newTestStore(t, configs.StateWriteQuorum, configs.StateReadFirstHit,
+		inmem, redis, failingBackend{})
+	t.Cleanup(majority.Close)
+	require.NoError(t, majority.SetState(ctx, "store-quorum", testRecord(), time.Minute))
+
+	minority := newTestStore(t, configs.StateWriteQuorum, configs.StateReadFirstHit,
+		redis, failingBackend{}, failingBackend{})
+	t.Cleanup(minority.Close)
+	require.ErrorIs(t, minority.SetState(ctx, "store-quorum-2", testRecord(), time.Minute), errBackendDown)
+}
+
+func TestStore_ReadFirstHit_MissDoesNotOverrideHit(t *testing.T) {
+	t.Parallel()
+
+	inmem, redis := newStoreBackends(t)
+	store := newTestStore(t, configs.StateWriteAllSync, configs.StateReadFirstHit, inmem, failingBackend{}, redis)
+	t.Cleanup(store.Close)
+
+	ctx := context.Background()
+	// state есть только в последнем репозитории
+	require.NoError(t, redis.SetState(ctx, "store-first-hit", testRecord(), time.Minute))
+
+	got, err := store.GetState(ctx, "store-first-hit")
+	require.NoError(t, err)
+	require.Equal(t, testRecord(), got)
+
+	got, err = store.ConsumeState(ctx, "store-first-hit", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, testRecord(), got)
+
+	_, err = store.GetState(ctx, "store-missing")
+	require.ErrorIs(t, err, errBackendDown)
+}
+
+func TestStore_ReadPrimaryOnly(t *testing.T) {
+	t.Parallel()
+
+	inmem, redis := newStoreBackends(t)
+	store := newTestStore(t, configs.StateWriteAllSync, configs.StateReadPrimaryOnly, inmem, redis)
+
+	ctx := context.Background()
+	require.NoError(t, redis.SetState(ctx, "store-primary-only", testRecord(), time.Minute))
+
+	_, err := store.GetState(ctx, "store-primary-only")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+
+	require.NoError(t, store.SetState(ctx, "store-primary-only-2", testRecord(), time.Minute))
+	_, err = store.ConsumeState(ctx, "store-primary-only-2", time.Minute)
+	require.NoError(t, err)
+
+	// копия в реплике забирается фоном
+	store.Close()
+	_, err = redis.ConsumeState(ctx, "store-primary-only-2", time.Minute)
+	require.ErrorIs(t, err, errorvals.ErrStateReplayed)
+}
+
+func TestStore_ConsumeState_ReplayInAnyBackendWins(t *testing.T) {
+	t.Parallel()
+
+	inmem, redis := newStoreBackends(t)
+	store := newTestStore(t, configs.StateWriteAllSync, configs.StateReadFirstHit, inmem, redis)
+	t.Cleanup(store.Close)
+
+	ctx := context.Background()
+	require.NoError(t, store.SetState(ctx, "store-replay", testRecord(), time.Minute))
+
+	// параллельный callback успел забрать state из redis
+	_, err := redis.ConsumeState(ctx, "store-replay", time.Minute)
+	require.NoError(t, err)
+
+	_, err = store.ConsumeState(ctx, "store-replay", time.Minute)
+	require.ErrorIs(t, err, errorvals.ErrStateReplayed)
+
+	// копия в памяти тоже забрана
+	_, err = inmem.ConsumeState(ctx, "store-replay", time.Minute)
+	require.ErrorIs(t, err, errorvals.ErrStateReplayed)
+}
+
+func TestNewStore_RejectsUnknownPolicies(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	_, err := NewStore(configs.StateStoreConfig{WritePolicy: "sometimes", ReadPolicy: configs.StateReadFirstHit},
+		logger, failingBackend{})
+	require.Error(t, err)
+
+	_, err = NewStore(configs.StateStoreConfig{WritePolicy: configs.StateWriteAllSync, ReadPolicy: "random"},
+		logger, failingBackend{})
+	require.Error(t, err)
+
+	_, err = NewStore(configs.StateStoreConfig{WritePolicy: configs.StateWriteAllSync,
+		ReadPolicy: configs.StateReadFirstHit}, logger)
+	require.Error(t, err)
+}
@@ -2,11 +2,13 @@ package user
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/dnonakolesax/noted-auth/internal/consts"
 	dbsql "github.com/dnonakolesax/noted-auth/internal/db/sql"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
 	"github.com/dnonakolesax/noted-auth/internal/model"
 )
 
@@ -18,16 +20,27 @@ const userLoginKey = "user_login"
 const (
 	getUserFileName       = "get_user"
 	getUserByNameFileName = "get_user_by_name"
+	getUsersFileName      = "get_users"
+	searchUsersFileName   = "search_users"
+	getAttributesFileName = "get_user_attributes"
 )
 
+// likeEscaper экранирует спецсимволы LIKE (в search_users.sql задан ESCAPE '\').
+//
+//nolint:gochecknoglobals // неизменяемый заменитель
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type Repo struct {
-	worker   dbsql.IPGXWorker
-	realmID  string
-	logger   *slog.Logger
-	requests map[string]string
+	worker  dbsql.IPGXWorker
+	realmID string
+	// attributes — имена атрибутов user_attribute, которые отдаются в профиле; пустой — не запрашиваются.
+	attributes []string
+	logger     *slog.Logger
+	requests   map[string]string
 }
 
-func NewUserRepo(worker dbsql.IPGXWorker, realmID string, requestsPath string, logger *slog.Logger) (*Repo, error) {
+func NewUserRepo(worker dbsql.IPGXWorker, realmID string, attributes []string, requestsPath string,
+	logger *slog.Logger) (*Repo, error) {
 	userRequests, err := dbsql.LoadSQLRequests(requestsPath + thisDomainName)
 
 	if err != nil {
@@ -36,10 +49,11 @@ func NewUserRepo(worker dbsql.IPGXWorker, realmID string, requestsPath string, l
 	}
 
 	return &Repo{
-		worker:   worker,
-		realmID:  realmID,
-		logger:   logger,
-		requests: userRequests,
+		worker:     worker,
+		realmID:    realmID,
+		attributes: attributes,
+		logger:     logger,
+		requests:   userRequests,
 	}, nil
 }
 
@@ -54,10 +68,11 @@ func (ur *Repo) GetUser(ctx context.Context, userID string) (model.User, error)
 
 	if !result.Next() {
 		ur.logger.WarnContext(ctx, "User not found", slog.String(userIDKey, userID))
-		return model.User{}, errors.New("not found")
+		return model.User{}, fmt.Errorf("%w: user %s", errorvals.ErrObjectNotFoundInRepoError, userID)
 	}
 	var user model.User
-	err = result.Scan(&user.Login, &user.FirstName, &user.LastName)
+	err = result.Scan(&user.Login, &user.FirstName, &user.LastName, &user.Email, &user.EmailVerified,
+		&user.Enabled, &user.CreatedTimestamp)
 	if err != nil {
 		ur.logger.ErrorContext(ctx, "Error scanning row", slog.String(consts.ErrorLoggerKey, err.Error()))
 		return model.User{}, err
@@ -65,7 +80,7 @@ func (ur *Repo) GetUser(ctx context.Context, userID string) (model.User, error)
 
 	if result.Next() {
 		ur.logger.ErrorContext(ctx, "Too many rows", slog.String(userIDKey, userID))
-		return model.User{}, errors.New("too many rows")
+		return model.User{}, errorvals.ErrTooManyRows
 	}
 
 	err = result.Close()
@@ -73,9 +88,133 @@ func (ur *Repo) GetUser(ctx context.Context, userID string) (model.User, error)
 		ur.logger.ErrorContext(ctx, "Error closing result", slog.String(consts.ErrorLoggerKey, err.Error()))
 		return model.User{}, err
 	}
+
+	attributes, err := ur.userAttributes(ctx, []string{userID})
+	if err != nil {
+		return model.User{}, err
+	}
+	user.Attributes = attributes[userID]
+
 	return user, nil
 }
 
+// GetUsers возвращает найденных пользователей по id; отсутствующих в ответе просто нет.
+func (ur *Repo) GetUsers(ctx context.Context, userIDs []string) (map[string]model.User, error) {
+	ur.logger.InfoContext(ctx, "About to execute query", slog.String("query_name", ur.requests[getUsersFileName]))
+	result, err := ur.worker.Query(ctx, ur.requests[getUsersFileName], userIDs, ur.realmID)
+
+	if err != nil {
+		ur.logger.ErrorContext(ctx, "Error executing query", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	users := make(map[string]model.User, len(userIDs))
+
+	for result.Next() {
+		var id string
+		var user model.User
+		err = result.Scan(&id, &user.Login, &user.FirstName, &user.LastName, &user.Email, &user.EmailVerified,
+			&user.Enabled, &user.CreatedTimestamp)
+		if err != nil {
+			ur.logger.ErrorContext(ctx, "Error scanning row", slog.String(consts.ErrorLoggerKey, err.Error()))
+			return nil, err
+		}
+		users[id] = user
+	}
+
+	err = result.Close()
+	if err != nil {
+		ur.logger.ErrorContext(ctx, "Error closing result", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	attributes, err := ur.userAttributes(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for id, attrs := range attributes {
+		if user, ok := users[id]; ok {
+			user.Attributes = attrs
+			users[id] = user
+		}
+	}
+
+	return users, nil
+}
+
+// userAttributes читает разрешённые атрибуты пользователей. У многозначного атрибута
+// остаётся первое значение в лексикографическом порядке.
+func (ur *Repo) userAttributes(ctx context.Context, userIDs []string) (map[string]map[string]string, error) {
+	if len(ur.attributes) == 0 {
+		return nil, nil //nolint:nilnil // атрибуты не запрошены
+	}
+
+	ur.logger.InfoContext(ctx, "About to execute query", slog.String("query_name", ur.requests[getAttributesFileName]))
+	result, err := ur.worker.Query(ctx, ur.requests[getAttributesFileName], userIDs, ur.attributes)
+
+	if err != nil {
+		ur.logger.ErrorContext(ctx, "Error executing query", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	attributes := make(map[string]map[string]string, len(userIDs))
+
+	for result.Next() {
+		var id, name, value string
+		err = result.Scan(&id, &name, &value)
+		if err != nil {
+			ur.logger.ErrorContext(ctx, "Error scanning row", slog.String(consts.ErrorLoggerKey, err.Error()))
+			return nil, err
+		}
+
+		if attributes[id] == nil {
+			attributes[id] = make(map[string]string, len(ur.attributes))
+		}
+		if _, ok := attributes[id][name]; !ok {
+			attributes[id][name] = value
+		}
+	}
+
+	err = result.Close()
+	if err != nil {
+		ur.logger.ErrorContext(ctx, "Error closing result", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// SearchUsers ищет по префиксу username, email, имени и фамилии без учёта регистра.
+// Пагинация по ключу: возвращаются пользователи с username строго больше after в порядке username.
+func (ur *Repo) SearchUsers(ctx context.Context, prefix string, after string, limit int) ([]model.FoundUser, error) {
+	ur.logger.InfoContext(ctx, "About to execute query", slog.String("query_name", ur.requests[searchUsersFileName]))
+	pattern := likeEscaper.Replace(strings.ToLower(prefix)) + "%"
+	result, err := ur.worker.Query(ctx, ur.requests[searchUsersFileName], ur.realmID, pattern, after, limit)
+
+	if err != nil {
+		ur.logger.ErrorContext(ctx, "Error executing query", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+
+	users := make([]model.FoundUser, 0, limit)
+
+	for result.Next() {
+		var user model.FoundUser
+		err = result.Scan(&user.ID, &user.Login, &user.FirstName, &user.LastName)
+		if err != nil {
+			ur.logger.ErrorContext(ctx, "Error scanning row", slog.String(consts.ErrorLoggerKey, err.Error()))
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	err = result.Close()
+	if err != nil {
+		ur.logger.ErrorContext(ctx, "Error closing result", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil, err
+	}
+	return users, nil
+}
+
 func (ur *Repo) IDByName(ctx context.Context, login string) (model.UserID, error) {
 	ur.logger.InfoContext(ctx, "About to execute query", slog.String("query_name", ur.requests[getUserByNameFileName]))
 	result, err := ur.worker.Query(ctx, ur.requests[getUserByNameFileName], login, ur.realmID)
@@ -87,7 +226,7 @@ func (ur *Repo) IDByName(ctx context.Context, login string) (model.UserID, error
 
 	if !result.Next() {
 		ur.logger.WarnContext(ctx, "User not found", slog.String(userLoginKey, login))
-		return model.UserID{}, errors.New("not found")
+		return model.UserID{}, fmt.Errorf("%w: user %s", errorvals.ErrObjectNotFoundInRepoError, login)
 	}
 	var user model.UserID
 	err = result.Scan(&user.ID)
@@ -98,7 +237,7 @@ func (ur *Repo) IDByName(ctx context.Context, login string) (model.UserID, error
 
 	if result.Next() {
 		ur.logger.ErrorContext(ctx, "Too many rows", slog.String(userLoginKey, login))
-		return model.UserID{}, errors.New("too many rows")
+		return model.UserID{}, errorvals.ErrTooManyRows
 	}
 
 	err = result.Close()
@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	dbsql "github.com/dnonakolesax/noted-auth/internal/db/sql"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
 
 	"github.com/dnonakolesax/noted-auth/internal/mocks"
 )
@@ -136,7 +137,7 @@ func TestNewUserRepo_OK_LoadsSQL(t *testing.T) {
 
 	mw := mocks.NewMockIPGXWorker(t)
 
-	repo, err := NewUserRepo(mw, "realm-1", reqRoot+string(os.PathSeparator), testLogger())
+	repo, err := NewUserRepo(mw, "realm-1", nil, reqRoot+string(os.PathSeparator), testLogger())
 	require.NoError(t, err)
 	require.NotNil(t, repo)
 
@@ -147,7 +148,7 @@ func TestNewUserRepo_OK_LoadsSQL(t *testing.T) {
 func TestNewUserRepo_ErrorOnMissingDir(t *testing.T) {
 	mw := mocks.NewMockIPGXWorker(t)
 
-	_, err := NewUserRepo(mw, "realm-1", filepath.Join(t.TempDir(), "nope")+string(os.PathSeparator), testLogger())
+	_, err := NewUserRepo(mw, "realm-1", nil, filepath.Join(t.TempDir(), "nope")+string(os.PathSeparator), testLogger())
 	require.Error(t, err)
 }
 
@@ -196,7 +197,7 @@ func TestUserRepo_GetUser_NotFound(t *testing.T) {
 
 	_, err := ur.GetUser(ctx, "u1")
 	require.Error(t, err)
-	require.Equal(t, "not found", err.Error())
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
 }
 
 func TestUserRepo_GetUser_ScanError(t *testing.T) {
@@ -257,7 +258,7 @@ func TestUserRepo_GetUser_TooManyRows(t *testing.T) {
 
 	_, err := ur.GetUser(ctx, "u1")
 	require.Error(t, err)
-	require.Equal(t, "too many rows", err.Error())
+	require.ErrorIs(t, err, errorvals.ErrTooManyRows)
 }
 
 func TestUserRepo_GetUser_CloseError(t *testing.T) {
@@ -374,7 +375,7 @@ func TestUserRepo_IDByName_NotFound(t *testing.T) {
 
 	_, err := ur.IDByName(ctx, "alice")
 	require.Error(t, err)
-	require.Equal(t, "not found", err.Error())
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
 }
 
 func TestUserRepo_IDByName_ScanError(t *testing.T) {
@@ -433,7 +434,7 @@ func TestUserRepo_IDByName_TooManyRows(t *testing.T) {
 
 	_, err := ur.IDByName(ctx, "alice")
 	require.Error(t, err)
-	require.Equal(t, "too many rows", err.Error())
+	require.ErrorIs(t, err, errorvals.ErrTooManyRows)
 }
 
 func TestUserRepo_IDByName_CloseError(t *testing.T) {
@@ -501,3 +502,175 @@ func TestUserRepo_IDByName_OK(t *testing.T) {
 
 	require.Equal(t, fmt.Sprint(expected), got.ID)
 }
+
+/* ----------------------------- tests: GetUsers ----------------------------- */
+
+func TestUserRepo_GetUsers_OK(t *testing.T) {
+	ctx := context.Background()
+
+	mw := mocks.NewMockIPGXWorker(t)
+	ur := &Repo{
+		worker:   mw,
+		realmID:  "realm",
+		logger:   testLogger(),
+		requests: map[string]string{getUsersFileName: "SQL_GET_USERS"},
+	}
+
+	ids := []string{"u1", "u2", "u3"}
+	found := []string{"u1", "u3"}
+	row := 0
+
+	rows := &rowsStub{
+		nextSeq: []bool{true, true, false},
+		scanFn: func(dest ...any) error {
+			_ = setPtr(dest[0], found[row])
+			_ = setPtr(dest[1], "login-"+found[row])
+			_ = setPtr(dest[2], "First")
+			_ = setPtr(dest[3], "Last")
+			row++
+			return nil
+		},
+	}
+	resp := newPGXResponse(rows)
+
+	mw.EXPECT().
+		Query(mock.Anything, "SQL_GET_USERS", ids, "realm").
+		Return(resp, nil).
+		Once()
+
+	got, err := ur.GetUsers(ctx, ids)
+	require.NoError(t, err)
+
+	require.Len(t, got, 2)
+	require.Equal(t, "login-u1", got["u1"].Login)
+	require.Equal(t, "login-u3", got["u3"].Login)
+	require.NotContains(t, got, "u2")
+}
+
+func TestUserRepo_GetUsers_ScanError(t *testing.T) {
+	ctx := context.Background()
+
+	mw := mocks.NewMockIPGXWorker(t)
+	ur := &Repo{
+		worker:   mw,
+		realmID:  "realm",
+		logger:   testLogger(),
+		requests: map[string]string{getUsersFileName: "SQL_GET_USERS"},
+	}
+
+	scErr := errors.New("scan failed")
+
+	rows := &rowsStub{
+		nextSeq: []bool{true},
+		scanFn:  func(_ ...any) error { return scErr },
+	}
+	resp := newPGXResponse(rows)
+
+	mw.EXPECT().
+		Query(mock.Anything, "SQL_GET_USERS", []string{"u1"}, "realm").
+		Return(resp, nil).
+		Once()
+
+	_, err := ur.GetUsers(ctx, []string{"u1"})
+	require.ErrorIs(t, err, scErr)
+}
+
+/* ----------------------------- tests: SearchUsers ----------------------------- */
+
+func TestUserRepo_SearchUsers_EscapesPattern(t *testing.T) {
+	ctx := context.Background()
+
+	mw := mocks.NewMockIPGXWorker(t)
+	ur := &Repo{
+		worker:   mw,
+		realmID:  "realm",
+		logger:   testLogger(),
+		requests: map[string]string{searchUsersFileName: "SQL_SEARCH_USERS"},
+	}
+
+	rows := &rowsStub{
+		nextSeq: []bool{true, false},
+		scanFn: func(dest ...any) error {
+			_ = setPtr(dest[0], "u1")
+			_ = setPtr(dest[1], "bob_1")
+			_ = setPtr(dest[2], "Bob")
+			_ = setPtr(dest[3], "")
+			return nil
+		},
+	}
+	resp := newPGXResponse(rows)
+
+	// регистр приводится к нижнему, % и _ из запроса не работают как шаблоны
+	mw.EXPECT().
+		Query(mock.Anything, "SQL_SEARCH_USERS", "realm", `bob\_1\%%`, "alice", 21).
+		Return(resp, nil).
+		Once()
+
+	got, err := ur.SearchUsers(ctx, "Bob_1%", "alice", 21)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "u1", got[0].ID)
+	require.Equal(t, "bob_1", got[0].Login)
+}
+
+/* ----------------------------- tests: attributes ----------------------------- */
+
+func TestUserRepo_GetUser_WithAttributes(t *testing.T) {
+	ctx := context.Background()
+
+	mw := mocks.NewMockIPGXWorker(t)
+	ur := &Repo{
+		worker:     mw,
+		realmID:    "realm",
+		attributes: []string{"picture", "locale"},
+		logger:     testLogger(),
+		requests: map[string]string{
+			getUserFileName:       "SQL_GET_USER",
+			getAttributesFileName: "SQL_GET_ATTRIBUTES",
+		},
+	}
+
+	userRows := &rowsStub{
+		nextSeq: []bool{true, false},
+		scanFn: func(dest ...any) error {
+			_ = setPtr(dest[0], "bob")
+			_ = setPtr(dest[3], "bob@noted.dev")
+			_ = setPtr(dest[4], true)
+			_ = setPtr(dest[5], true)
+			_ = setPtr(dest[6], int64(1700000000000))
+			return nil
+		},
+	}
+
+	attrs := [][3]string{{"u1", "locale", "en"}, {"u1", "locale", "ru"}, {"u1", "picture", "https://cdn/bob.png"}}
+	row := 0
+	attrRows := &rowsStub{
+		nextSeq: []bool{true, true, true, false},
+		scanFn: func(dest ...any) error {
+			for i := range dest {
+				_ = setPtr(dest[i], attrs[row][i])
+			}
+			row++
+			return nil
+		},
+	}
+
+	mw.EXPECT().
+		Query(mock.Anything, "SQL_GET_USER", "u1", "realm").
+		Return(newPGXResponse(userRows), nil).
+		Once()
+	mw.EXPECT().
+		Query(mock.Anything, "SQL_GET_ATTRIBUTES", []string{"u1"}, []string{"picture", "locale"}).
+		Return(newPGXResponse(attrRows), nil).
+		Once()
+
+	got, err := ur.GetUser(ctx, "u1")
+	require.NoError(t, err)
+
+	require.Equal(t, "bob@noted.dev", got.Email)
+	require.True(t, got.EmailVerified)
+	require.True(t, got.Enabled)
+	require.Equal(t, int64(1700000000000), got.CreatedTimestamp)
+	// у многозначного атрибута остаётся первое значение
+	require.Equal(t, map[string]string{"locale": "en", "picture": "https://cdn/bob.png"}, got.Attributes)
+}
@@ -0,0 +1,176 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+)
+
+const (
+	keyLength = 32
+	// defaultKeyID присваивается ключу, заданному без id (одиночный ключ в base64).
+	defaultKeyID = "0"
+	keySeparator = ","
+	kidSeparator = ":"
+	// sealedSeparator отделяет id ключа от шифротекста: kid.base64url(nonce || ciphertext).
+	sealedSeparator = '.'
+)
+
+type keyring struct {
+	activeID string
+	keys     map[string]cipher.AEAD
+}
+
+// Sealer шифрует данные AES-256-GCM. Ключи приходят из vault строкой "kid1:key1,kid2:key2"
+// (ключи в base64) и могут быть заменены на лету. Шифрует первый ключ, расшифровывают все:
+// при ротации новый ключ ставится первым, а старый остаётся в списке, пока не истекут его данные.
+type Sealer struct {
+	ring   atomic.Pointer[keyring]
+	logger *slog.Logger
+}
+
+func NewSealer(keys string, logger *slog.Logger, vaultChan chan string) (*Sealer, error) {
+	s := &Sealer{logger: logger}
+
+	ring, err := parseKeyring(keys)
+
+	if err != nil {
+		return nil, err
+	}
+	s.ring.Store(ring)
+
+	go s.MonitorVault(vaultChan)
+
+	return s, nil
+}
+
+func parseKeyring(keys string) (*keyring, error) {
+	ring := &keyring{keys: map[string]cipher.AEAD{}}
+
+	for _, entry := range strings.Split(keys, keySeparator) {
+		entry = strings.TrimSpace(entry)
+		kid, key, found := strings.Cut(entry, kidSeparator)
+
+		if !found {
+			kid, key = defaultKeyID, entry
+		}
+
+		if kid == "" || strings.ContainsRune(kid, sealedSeparator) {
+			return nil, fmt.Errorf("invalid seal key id %q", kid)
+		}
+
+		if _, ok := ring.keys[kid]; ok {
+			return nil, fmt.Errorf("duplicate seal key id %q", kid)
+		}
+
+		aead, err := newAEAD(key)
+
+		if err != nil {
+			return nil, fmt.Errorf("seal key %q: %w", kid, err)
+		}
+
+		ring.keys[kid] = aead
+		if ring.activeID == "" {
+			ring.activeID = kid
+		}
+	}
+
+	return ring, nil
+}
+
+func newAEAD(key string) (cipher.AEAD, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode seal key: %w", err)
+	}
+
+	if len(raw) != keyLength {
+		return nil, fmt.Errorf("seal key must be %d bytes, got %d", keyLength, len(raw))
+	}
+
+	block, err := aes.NewCipher(raw)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// MonitorVault подменяет набор ключей. Данные, зашифрованные ключом, которого нет в новом наборе,
+// после этого не расшифровываются.
+func (s *Sealer) MonitorVault(vaultChan chan string) {
+	for keys := range vaultChan {
+		ring, err := parseKeyring(keys)
+
+		if err != nil {
+			s.logger.Error("Failed to apply seal keys from vault, keeping the old ones",
+				slog.String(consts.ErrorLoggerKey, err.Error()))
+			continue
+		}
+		s.ring.Store(ring)
+	}
+}
+
+// Seal возвращает kid.base64url(nonce || ciphertext). additionalData не шифруется,
+// но привязывается к шифротексту.
+func (s *Sealer) Seal(plaintext []byte, additionalData []byte) ([]byte, error) {
+	ring := s.ring.Load()
+	aead := ring.keys[ring.activeID]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, additionalData)
+	out := make([]byte, 0, len(ring.activeID)+1+base64.RawURLEncoding.EncodedLen(len(sealed)))
+	out = append(out, ring.activeID...)
+	out = append(out, sealedSeparator)
+
+	return base64.RawURLEncoding.AppendEncode(out, sealed), nil
+}
+
+// Open возвращает errorvals.ErrUnknownSealKey, если данные не в формате Sealer
+// или зашифрованы ключом, которого нет в наборе, и errorvals.ErrSealedDataInvalid, если они повреждены.
+func (s *Sealer) Open(sealed []byte, additionalData []byte) ([]byte, error) {
+	ring := s.ring.Load()
+
+	kid, payload, found := bytes.Cut(sealed, []byte{sealedSeparator})
+	if !found {
+		return nil, fmt.Errorf("%w: no key id", errorvals.ErrUnknownSealKey)
+	}
+
+	aead, ok := ring.keys[string(kid)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errorvals.ErrUnknownSealKey, kid)
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(string(payload))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errorvals.ErrSealedDataInvalid, err.Error())
+	}
+
+	if len(raw) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: too short", errorvals.ErrSealedDataInvalid)
+	}
+
+	nonce, ciphertext := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errorvals.ErrSealedDataInvalid, err.Error())
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,133 @@
+package seal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, keyLength))
+}
+
+func newTestSealer(t *testing.T, key string) (*Sealer, chan string) {
+	t.Helper()
+
+	vaultCh := make(chan string)
+	t.Cleanup(func() { close(vaultCh) })
+
+	s, err := NewSealer(key, slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})), vaultCh)
+	require.NoError(t, err)
+
+	return s, vaultCh
+}
+
+func TestSealer_SealOpen_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	s, _ := newTestSealer(t, testKey(1))
+
+	sealed, err := s.Seal([]byte("tokens"), []byte("aad"))
+	require.NoError(t, err)
+	require.NotContains(t, string(sealed), "tokens")
+
+	opened, err := s.Open(sealed, []byte("aad"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("tokens"), opened)
+}
+
+func TestSealer_Open_RejectsTamperedOrForeign(t *testing.T) {
+	t.Parallel()
+
+	s, _ := newTestSealer(t, testKey(1))
+
+	sealed, err := s.Seal([]byte("tokens"), []byte("aad"))
+	require.NoError(t, err)
+
+	_, err = s.Open(sealed, []byte("other-aad"))
+	require.ErrorIs(t, err, errorvals.ErrSealedDataInvalid)
+
+	sealed[len(sealed)-1] ^= 0xff
+	_, err = s.Open(sealed, []byte("aad"))
+	require.ErrorIs(t, err, errorvals.ErrSealedDataInvalid)
+
+	_, err = s.Open([]byte("eyJhbGciOiJSUzI1NiJ9.e30.sig"), []byte("aad"))
+	require.ErrorIs(t, err, errorvals.ErrUnknownSealKey)
+
+	_, err = s.Open([]byte("plain"), []byte("aad"))
+	require.ErrorIs(t, err, errorvals.ErrUnknownSealKey)
+}
+
+func TestSealer_Rotation_OldKeyStillOpens(t *testing.T) {
+	t.Parallel()
+
+	s, vaultCh := newTestSealer(t, "k1:"+testKey(1))
+
+	old, err := s.Seal([]byte("tokens"), nil)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(old), "k1."))
+
+	// новый ключ первым — им шифруется, старый остаётся для расшифровки
+	vaultCh <- "k2:" + testKey(2) + ", k1:" + testKey(1)
+
+	require.Eventually(t, func() bool {
+		sealed, serr := s.Seal([]byte("tokens"), nil)
+		return serr == nil && strings.HasPrefix(string(sealed), "k2.")
+	}, time.Second, 10*time.Millisecond)
+
+	opened, err := s.Open(old, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("tokens"), opened)
+
+	// старый ключ выведен из набора
+	vaultCh <- "k2:" + testKey(2)
+
+	require.Eventually(t, func() bool {
+		_, oerr := s.Open(old, nil)
+		return errors.Is(oerr, errorvals.ErrUnknownSealKey)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSealer_MonitorVault_ReplacesKey(t *testing.T) {
+	t.Parallel()
+
+	s, vaultCh := newTestSealer(t, testKey(1))
+
+	old, err := s.Seal([]byte("tokens"), nil)
+	require.NoError(t, err)
+
+	vaultCh <- "not base64!"
+	vaultCh <- testKey(2)
+
+	require.Eventually(t, func() bool {
+		_, oerr := s.Open(old, nil)
+		return oerr != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestNewSealer_RejectsBadKey(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	_, err := NewSealer("", logger, nil)
+	require.Error(t, err)
+
+	_, err = NewSealer(base64.StdEncoding.EncodeToString([]byte("short")), logger, nil)
+	require.Error(t, err)
+
+	_, err = NewSealer("k1:"+testKey(1)+",k1:"+testKey(2), logger, nil)
+	require.Error(t, err)
+
+	_, err = NewSealer("k.1:"+testKey(1), logger, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
+)
+
+const targetUserKey = "target_user"
+
+// AdminClient — admin REST API keycloak.
+type AdminClient interface {
+	SetEnabled(ctx context.Context, userID string, enabled bool) error
+	SetRequiredActions(ctx context.Context, userID string, actions []string) error
+	Logout(ctx context.Context, userID string) error
+	Sessions(ctx context.Context, userID string) ([]model.Session, error)
+}
+
+// AdminUsecase — операции поддержки над чужими пользователями. Каждое изменение пишется в лог
+// вместе с тем, кто его сделал.
+type AdminUsecase struct {
+	client AdminClient
+	cache  IntrospectionCache
+	logger *slog.Logger
+}
+
+func NewAdminUsecase(client AdminClient, cache IntrospectionCache, logger *slog.Logger) *AdminUsecase {
+	return &AdminUsecase{
+		client: client,
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+func (au *AdminUsecase) Sessions(ctx context.Context, userID string) (model.UserSessions, error) {
+	if userID == "" {
+		return model.UserSessions{}, fmt.Errorf("%w: empty user id", errorvals.ErrInvalidArgument)
+	}
+
+	sessions, err := au.client.Sessions(ctx, userID)
+
+	if err != nil {
+		return model.UserSessions{}, err
+	}
+
+	return model.UserSessions{UserID: userID, Sessions: sessions}, nil
+}
+
+// SetEnabled включает или выключает пользователя. При выключении его сессии завершаются:
+// keycloak не отзывает уже выданные токены сам.
+func (au *AdminUsecase) SetEnabled(ctx context.Context, userID string, enabled bool) error {
+	if userID == "" {
+		return fmt.Errorf("%w: empty user id", errorvals.ErrInvalidArgument)
+	}
+
+	err := au.client.SetEnabled(ctx, userID, enabled)
+
+	if err != nil {
+		return err
+	}
+
+	au.logger.InfoContext(ctx, "User enabled changed", slog.String(targetUserKey, userID),
+		slog.Bool("enabled", enabled), principal.LogAttr(ctx))
+
+	if enabled {
+		return nil
+	}
+
+	_, err = au.Logout(ctx, userID)
+
+	return err
+}
+
+// Logout завершает все сессии пользователя. Список берётся до выхода, чтобы вернуть отозванные
+// сессии и убрать их из кэша интроспекции.
+func (au *AdminUsecase) Logout(ctx context.Context, userID string) (model.SessionsRevoked, error) {
+	if userID == "" {
+		return model.SessionsRevoked{}, fmt.Errorf("%w: empty user id", errorvals.ErrInvalidArgument)
+	}
+
+	sessions, err := au.client.Sessions(ctx, userID)
+
+	if err != nil {
+		return model.SessionsRevoked{}, err
+	}
+
+	err = au.client.Logout(ctx, userID)
+
+	if err != nil {
+		return model.SessionsRevoked{}, err
+	}
+
+	revoked := model.SessionsRevoked{Revoked: make([]string, 0, len(sessions))}
+
+	for _, session := range sessions {
+		au.purgeCachedSession(ctx, session.ID)
+		revoked.Revoked = append(revoked.Revoked, session.ID)
+		revoked.CurrentRevoked = revoked.CurrentRevoked || session.ID == currentSessionID(ctx)
+	}
+
+	au.logger.InfoContext(ctx, "User logged out", slog.String(targetUserKey, userID),
+		slog.Int("count", len(revoked.Revoked)), principal.LogAttr(ctx))
+
+	return revoked, nil
+}
+
+// SetRequiredActions заменяет список обязательных действий (UPDATE_PASSWORD, CONFIGURE_TOTP и т.п.).
+// Пустой список снимает все; повторы убираются.
+func (au *AdminUsecase) SetRequiredActions(ctx context.Context, userID string, actions []string) error {
+	if userID == "" {
+		return fmt.Errorf("%w: empty user id", errorvals.ErrInvalidArgument)
+	}
+
+	unique := make([]string, 0, len(actions))
+	seen := make(map[string]struct{}, len(actions))
+
+	for _, action := range actions {
+		action = strings.TrimSpace(action)
+		if action == "" {
+			return fmt.Errorf("%w: empty required action", errorvals.ErrInvalidArgument)
+		}
+		if _, ok := seen[action]; ok {
+			continue
+		}
+		seen[action] = struct{}{}
+		unique = append(unique, action)
+	}
+
+	err := au.client.SetRequiredActions(ctx, userID, unique)
+
+	if err != nil {
+		return err
+	}
+
+	au.logger.InfoContext(ctx, "User required actions set", slog.String(targetUserKey, userID),
+		slog.String("actions", strings.Join(unique, ",")), principal.LogAttr(ctx))
+
+	return nil
+}
+
+func (au *AdminUsecase) purgeCachedSession(ctx context.Context, id string) {
+	if au.cache == nil {
+		return
+	}
+
+	err := au.cache.PurgeSession(ctx, id)
+
+	if err != nil {
+		au.logger.ErrorContext(ctx, "Error purging session from introspection cache",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+	}
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+type adminClientStub struct {
+	calls    []string
+	enabled  *bool
+	actions  []string
+	sessions []model.Session
+}
+
+func (a *adminClientStub) SetEnabled(_ context.Context, userID string, enabled bool) error {
+	a.calls = append(a.calls, "enabled "+userID)
+	a.enabled = &enabled
+	return nil
+}
+
+func (a *adminClientStub) SetRequiredActions(_ context.Context, userID string, actions []string) error {
+	a.calls = append(a.calls, "actions "+userID)
+	a.actions = actions
+	return nil
+}
+
+func (a *adminClientStub) Logout(_ context.Context, userID string) error {
+	a.calls = append(a.calls, "logout "+userID)
+	return nil
+}
+
+func (a *adminClientStub) Sessions(_ context.Context, userID string) ([]model.Session, error) {
+	a.calls = append(a.calls, "sessions "+userID)
+	return a.sessions, nil
+}
+
+func TestAdminUsecase_DisableLogsOut(t *testing.T) {
+	t.Parallel()
+
+	client := &adminClientStub{sessions: []model.Session{{ID: "s1"}, {ID: "s2"}}}
+	cache := newCacheStub()
+	require.NoError(t, cache.Set(context.Background(), "token", "s1", model.IntrospectDTO{Active: true}, 0))
+	uc := NewAdminUsecase(client, cache, testLogger())
+
+	require.NoError(t, uc.SetEnabled(context.Background(), "u1", true))
+	require.Equal(t, []string{"enabled u1"}, client.calls)
+
+	require.NoError(t, uc.SetEnabled(context.Background(), "u1", false))
+	require.Equal(t, []string{"enabled u1", "enabled u1", "sessions u1", "logout u1"}, client.calls)
+	require.False(t, *client.enabled)
+
+	_, err := cache.Get(context.Background(), "token")
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+}
+
+func TestAdminUsecase_Logout(t *testing.T) {
+	t.Parallel()
+
+	client := &adminClientStub{sessions: []model.Session{{ID: "s1"}, {ID: "s2"}}}
+	uc := NewAdminUsecase(client, nil, testLogger())
+
+	revoked, err := uc.Logout(context.Background(), "u1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"s1", "s2"}, revoked.Revoked)
+	require.False(t, revoked.CurrentRevoked)
+
+	_, err = uc.Logout(context.Background(), "")
+	require.ErrorIs(t, err, errorvals.ErrInvalidArgument)
+}
+
+func TestAdminUsecase_SetRequiredActions(t *testing.T) {
+	t.Parallel()
+
+	client := &adminClientStub{}
+	uc := NewAdminUsecase(client, nil, testLogger())
+
+	require.NoError(t, uc.SetRequiredActions(context.Background(), "u1",
+		[]string{"UPDATE_PASSWORD", " CONFIGURE_TOTP ", "UPDATE_PASSWORD"}))
+	require.Equal(t, []string{"UPDATE_PASSWORD", "CONFIGURE_TOTP"}, client.actions)
+
+	require.NoError(t, uc.SetRequiredActions(context.Background(), "u1", nil))
+	require.Empty(t, client.actions)
+
+	require.ErrorIs(t, uc.SetRequiredActions(context.Background(), "u1", []string{""}),
+		errorvals.ErrInvalidArgument)
+}
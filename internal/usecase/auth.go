@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -21,32 +22,101 @@ import (
 	"github.com/dnonakolesax/noted-auth/internal/consts"
 	"github.com/dnonakolesax/noted-auth/internal/errorvals"
 	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/jwt"
+	"github.com/dnonakolesax/noted-auth/internal/metrics"
 	"github.com/dnonakolesax/noted-auth/internal/model"
 	"github.com/dnonakolesax/noted-auth/internal/rnd"
 )
 
+const defaultScope = "openid"
+
+// Значения token_type_hint из RFC 7009.
+const (
+	TokenTypeHintAccess  = "access_token"
+	TokenTypeHintRefresh = "refresh_token"
+)
+
+// refreshReuseFallbackTTL — сколько помнить использованные refresh token без exp (offline токены).
+const refreshReuseFallbackTTL = 30 * 24 * time.Hour
+
 type StateRepo interface {
-	SetState(ctx context.Context, state string, redirectURI string, timeout time.Duration) error
-	GetState(ctx context.Context, state string) (string, error)
+	SetState(ctx context.Context, state string, record model.AuthState, timeout time.Duration) error
+	GetState(ctx context.Context, state string) (model.AuthState, error)
+	// ConsumeState атомарно забирает и удаляет state. Повторный вызов в течение replayWindow
+	// возвращает errorvals.ErrStateReplayed.
+	ConsumeState(ctx context.Context, state string, replayWindow time.Duration) (model.AuthState, error)
+}
+
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (jwt.Claims, error)
+	VerifyIDToken(ctx context.Context, token string, nonce string) (jwt.Claims, error)
+}
+
+// IntrospectionCache хранит результаты /token/introspect по хэшу access token.
+// При промахе Get возвращает errorvals.ErrObjectNotFoundInRepoError.
+type IntrospectionCache interface {
+	Get(ctx context.Context, key string) (model.IntrospectDTO, error)
+	Set(ctx context.Context, key string, sessionID string, dto model.IntrospectDTO, ttl time.Duration) error
+	PurgeSession(ctx context.Context, sessionID string) error
+}
+
+// RefreshTokenRepo помнит обменянные refresh token по сессиям keycloak.
+type RefreshTokenRepo interface {
+	// MarkUsed возвращает время первого использования, если токен уже предъявлялся, иначе нулевое время.
+	MarkUsed(ctx context.Context, sessionID string, tokenHash string, ttl time.Duration) (time.Time, error)
+	// UsedAt — то же, что MarkUsed, но без отметки.
+	UsedAt(ctx context.Context, sessionID string, tokenHash string) (time.Time, error)
+	Unmark(ctx context.Context, sessionID string, tokenHash string) error
+}
+
+// TokenExchangeCache хранит обменянные токены по хэшу subject token и audience.
+// При промахе Get возвращает errorvals.ErrObjectNotFoundInRepoError.
+type TokenExchangeCache interface {
+	Get(ctx context.Context, key string) (model.ExchangedToken, error)
+	Set(ctx context.Context, key string, token model.ExchangedToken, ttl time.Duration) error
 }
 
 type AuthUsecase struct {
 	authLifetime time.Duration
 	kcTimeout    time.Duration
-	repos        []StateRepo
+	repo         StateRepo
 	kcConfig     configs.KeycloakConfig
 	httpClient   *httpclient.HTTPClient
+	revokeClient *httpclient.HTTPClient
+	verifier     TokenVerifier
+	cache        IntrospectionCache
+	cacheMaxTTL  time.Duration
+	cacheMetrics *metrics.CacheMetrics
+	refreshRepo  RefreshTokenRepo
+	security     *metrics.SecurityMetrics
+	exchange     configs.TokenExchangeConfig
+	exchangeRepo TokenExchangeCache
 	logger       *slog.Logger
 	kcCSUpdating *atomic.Bool
 }
 
-func NewAuthUsecase(authLifetime time.Duration, repos []StateRepo, kcConfig configs.KeycloakConfig,
-	httpClient *httpclient.HTTPClient, logger *slog.Logger, vaultChan chan string) *AuthUsecase {
+// NewAuthUsecase принимает cache == nil, если кэш интроспекции выключен,
+// refreshRepo == nil, если обнаружение повторного использования refresh token выключено,
+// и exchangeRepo == nil, если обмен токена выключен.
+func NewAuthUsecase(authLifetime time.Duration, repo StateRepo, kcConfig configs.KeycloakConfig,
+	httpClient *httpclient.HTTPClient, revokeClient *httpclient.HTTPClient, verifier TokenVerifier,
+	cache IntrospectionCache, cacheMaxTTL time.Duration, cacheMetrics *metrics.CacheMetrics,
+	refreshRepo RefreshTokenRepo, security *metrics.SecurityMetrics, exchange configs.TokenExchangeConfig,
+	exchangeRepo TokenExchangeCache, logger *slog.Logger, vaultChan chan string) *AuthUsecase {
 	uc := &AuthUsecase{
 		authLifetime: authLifetime,
-		repos:        repos,
+		repo:         repo,
 		kcConfig:     kcConfig,
 		httpClient:   httpClient,
+		revokeClient: revokeClient,
+		verifier:     verifier,
+		cache:        cache,
+		cacheMaxTTL:  cacheMaxTTL,
+		cacheMetrics: cacheMetrics,
+		refreshRepo:  refreshRepo,
+		security:     security,
+		exchange:     exchange,
+		exchangeRepo: exchangeRepo,
 		logger:       logger,
 		kcCSUpdating: &atomic.Bool{},
 		kcTimeout:    kcConfig.TokenTimeout,
@@ -66,7 +136,10 @@ func (ac *AuthUsecase) MonitorVault(vaultChan chan string) {
 	}
 }
 
-func (ac *AuthUsecase) GetAuthLink(ctx context.Context, returnURL string) (string, error) {
+// GetAuthLink создаёт state и ссылку на авторизацию в keycloak. clientIP и userAgent
+// сохраняются в записи state только в виде хэшей.
+func (ac *AuthUsecase) GetAuthLink(ctx context.Context, returnURL string, clientIP string,
+	userAgent string) (string, error) {
 	state, err := rnd.GenRandomString(ac.kcConfig.StateLength)
 
 	if err != nil {
@@ -96,46 +169,42 @@ func (ac *AuthUsecase) GetAuthLink(ctx context.Context, returnURL string) (strin
 	bts := sha256.Sum256([]byte(b64cv))
 	sha := base64.RawURLEncoding.EncodeToString(bts[:])
 
-	err = ac.repos[0].SetState(ctx, encodedState, returnURL, ac.authLifetime)
+	nonce, err := rnd.GenRandomString(ac.kcConfig.NonceLength)
 
 	if err != nil {
-		ac.logger.ErrorContext(ctx, "Failed to set state",
+		ac.logger.ErrorContext(ctx, "Failed to create crypto-random string (nonce)",
 			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return "", err
+	}
+	encodedNonce := base64.RawURLEncoding.EncodeToString(nonce)
+
+	record := model.AuthState{
+		ReturnURL:     returnURL,
+		CodeVerifier:  b64cv,
+		Nonce:         encodedNonce,
+		CreatedAt:     time.Now().Unix(),
+		ClientIPHash:  sha256Hex(clientIP),
+		UserAgentHash: sha256Hex(userAgent),
+		Scopes:        []string{defaultScope},
 	}
 
-	err = ac.repos[0].SetState(ctx, encodedState+":code_verifier", b64cv, ac.authLifetime)
+	err = ac.repo.SetState(ctx, encodedState, record, ac.authLifetime)
 
 	if err != nil {
-		ac.logger.ErrorContext(ctx, "Failed to set code verifier",
+		ac.logger.ErrorContext(ctx, "Failed to set state",
 			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return "", err
 	}
 
-	go func() {
-		for i := 1; i < len(ac.repos); i++ {
-			err = ac.repos[i].SetState(ctx, encodedState, returnURL, ac.authLifetime)
-
-			if err != nil {
-				ac.logger.ErrorContext(ctx, "Failed to set state",
-					slog.String(consts.ErrorLoggerKey, err.Error()))
-			}
-
-			err = ac.repos[i].SetState(ctx, encodedState+":code_verifier", b64cv, ac.authLifetime)
-
-			if err != nil {
-				ac.logger.ErrorContext(ctx, "Failed to set code verifier",
-					slog.String(consts.ErrorLoggerKey, err.Error()))
-			}
-		}
-	}()
-
 	data := url.Values{}
 	data.Set("client_id", ac.kcConfig.ClientID)
 	data.Set("redirect_uri", ac.kcConfig.RedirectURI)
 	data.Set("state", encodedState)
-	data.Set("scope", "openid")
+	data.Set("scope", strings.Join(record.Scopes, " "))
 	data.Set("response_type", "code")
 	data.Set("code_challenge", sha)
 	data.Set("code_challenge_method", "S256")
+	data.Set("nonce", encodedNonce)
 	ac.logger.InfoContext(ctx, data.Encode())
 	link := fmt.Sprintf("%s%s?%s", ac.kcConfig.RealmAddress, ac.kcConfig.AuthEndpoint, data.Encode())
 	ac.logger.DebugContext(ctx, "Created auth link", slog.String("Link", link))
@@ -144,46 +213,24 @@ func (ac *AuthUsecase) GetAuthLink(ctx context.Context, returnURL string) (strin
 }
 
 func (ac *AuthUsecase) GetToken(ctx context.Context, state string, code string) (model.TokenDTO, error) {
-	var returnURL string
-	var codeVerifier string
-	for _, repo := range ac.repos {
-		var err error
-		returnURL, err = repo.GetState(ctx, state)
-
-		if err != nil && !errors.Is(err, errorvals.ErrObjectNotFoundInRepoError) {
-			ac.logger.ErrorContext(ctx, "Failed to get state", slog.String(consts.ErrorLoggerKey, err.Error()))
-			return model.TokenDTO{}, err
-		}
+	record, err := ac.repo.ConsumeState(ctx, state, ac.authLifetime)
 
-		codeVerifier, err = repo.GetState(ctx, state+":code_verifier")
-
-		if err != nil && !errors.Is(err, errorvals.ErrObjectNotFoundInRepoError) {
-			ac.logger.ErrorContext(ctx, "Failed to get code verifier", slog.String(consts.ErrorLoggerKey, err.Error()))
-			return model.TokenDTO{}, err
-		}
+	if err != nil {
+		ac.logger.ErrorContext(ctx, "Failed to get state", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.TokenDTO{}, err
 	}
 
-	if returnURL == "" {
+	if record.ReturnURL == "" {
 		ac.logger.WarnContext(ctx, "Return URL not found")
 		ac.logger.DebugContext(ctx, "", slog.String("state", state))
 		return model.TokenDTO{}, errors.New("return URL not found")
 	}
-	if codeVerifier == "" {
+	if record.CodeVerifier == "" {
 		ac.logger.WarnContext(ctx, "Code verifier not found")
 		ac.logger.DebugContext(ctx, "", slog.String("state", state))
 		return model.TokenDTO{}, errors.New("code verifier not found")
 	}
 
-	postState, err := rnd.GenRandomString(ac.kcConfig.StateLength)
-
-	if err != nil {
-		ac.logger.ErrorContext(ctx, "Failed to create crypto-random string (state)",
-			slog.String(consts.ErrorLoggerKey, err.Error()))
-		return model.TokenDTO{}, err
-	}
-
-	encodedState := base64.RawURLEncoding.EncodeToString(postState)
-
 	data := url.Values{}
 	data.Set("grant_type", "authorization_code")
 	data.Set("client_id", ac.kcConfig.ClientID)
@@ -192,9 +239,8 @@ func (ac *AuthUsecase) GetToken(ctx context.Context, state string, code string)
 	data.Set("client_secret", ac.kcConfig.ClientSecret)
 	data.Set("code", code)
 	data.Set("redirect_uri", ac.kcConfig.RedirectURI)
-	data.Set("state", encodedState)
-	data.Set("code_verifier", codeVerifier)
-	data.Set("scope", "openid")
+	data.Set("code_verifier", record.CodeVerifier)
+	data.Set("scope", strings.Join(record.Scopes, " "))
 
 	pCtx, cancel := context.WithTimeout(context.Background(), ac.kcTimeout)
 	defer cancel()
@@ -227,24 +273,129 @@ func (ac *AuthUsecase) GetToken(ctx context.Context, state string, code string)
 		return model.TokenDTO{}, err
 	}
 
-	// if encodedState != dto.State {
-	// 	ac.logger.ErrorContext(ctx, "State mismatch")
-	// 	ac.logger.DebugContext(ctx, "", "expected", encodedState, "actual", dto.SessionState)
-	// 	return model.TokenDTO{}, errors.New("state mismatch")
-	// }
-	dto.ReturnURL = returnURL
+	// state сверять не с чем: keycloak не возвращает его из /token. Привязку ответа
+	// к этому логину даёт nonce в id token.
+	_, err = ac.verifier.VerifyIDToken(ctx, dto.IDToken, record.Nonce)
+
+	if err != nil {
+		ac.logger.WarnContext(ctx, "ID token verification failed",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.TokenDTO{}, err
+	}
+
+	dto.ReturnURL = record.ReturnURL
 
 	return dto, nil
 }
 
 func (ac *AuthUsecase) GetLogoutLink(ctx context.Context, idt string) string {
-	trace, _ := ctx.Value(consts.TraceContextKey).(slog.Attr)
+	trace, _ := ctx.Value(consts.TraceContextKey).(string)
 	link := fmt.Sprintf("%s/%s?post_logout_redirect_uri=%s&id_token_hint=%s",
 		ac.kcConfig.RealmAddress, ac.kcConfig.LogoutEndpoint, ac.kcConfig.PostLogoutRedirectURI, idt)
-	ac.logger.DebugContext(ctx, "Created logout link", slog.String("link", link), trace)
+	if idt == consts.EmptyString {
+		// без id_token_hint keycloak принимает post_logout_redirect_uri только вместе с client_id
+		link = fmt.Sprintf("%s/%s?post_logout_redirect_uri=%s&client_id=%s",
+			ac.kcConfig.RealmAddress, ac.kcConfig.LogoutEndpoint, ac.kcConfig.PostLogoutRedirectURI, ac.kcConfig.ClientID)
+	}
+	ac.logger.DebugContext(ctx, "Created logout link", slog.String("link", link),
+		slog.String(consts.TraceLoggerKey, trace))
 	return link
 }
 
+func introspectionFromClaims(claims jwt.Claims) model.IntrospectDTO {
+	return model.IntrospectDTO{
+		Active:         true,
+		Subject:        claims.Subject,
+		ExpiresAt:      claims.ExpiresAt,
+		SessionID:      claims.SessionID,
+		SessionState:   claims.SessionState,
+		Username:       claims.Username,
+		Scope:          claims.Scope,
+		RealmAccess:    claims.RealmAccess,
+		ResourceAccess: claims.ResourceAccess,
+	}
+}
+
+// introspect проверяет access token локально по JWKS реалма. Удалённая интроспекция
+// используется, если локальная проверка выключена в конфиге, либо как fallback при её ошибке.
+func (ac *AuthUsecase) introspect(ctx context.Context, token string) (model.IntrospectDTO, error) {
+	if ac.verifier == nil || ac.kcConfig.TokenVerification != configs.TokenVerificationLocal {
+		return ac.introspectRemote(ctx, token)
+	}
+
+	if token == consts.EmptyString {
+		// кука с access token истекает вместе с ним, так что пустой токен — повод обновить пару
+		return model.IntrospectDTO{Active: false}, nil
+	}
+
+	claims, err := ac.verifier.Verify(ctx, token)
+
+	switch {
+	case err == nil:
+		return introspectionFromClaims(claims), nil
+	case errors.Is(err, errorvals.ErrTokenExpired):
+		return model.IntrospectDTO{Active: false, Subject: claims.Subject}, nil
+	case ac.kcConfig.IntrospectionFallback:
+		ac.logger.WarnContext(ctx, "Local token verification failed, falling back to introspection",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return ac.introspectRemote(ctx, token)
+	default:
+		ac.logger.WarnContext(ctx, "Local token verification failed",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.IntrospectDTO{}, err
+	}
+}
+
+// introspectRemote ходит в /token/introspect через кэш. Кэшируются только активные токены
+// и не дольше их exp, так что отозванный в keycloak токен живёт в кэше не больше cacheMaxTTL.
+func (ac *AuthUsecase) introspectRemote(ctx context.Context, token string) (model.IntrospectDTO, error) {
+	if ac.cache == nil {
+		return ac.isTokenValid(token)
+	}
+
+	key := sha256Hex(token)
+
+	cached, err := ac.cache.Get(ctx, key)
+
+	if err == nil {
+		ac.cacheMetrics.Hits.Inc()
+		return cached, nil
+	}
+
+	ac.cacheMetrics.Misses.Inc()
+
+	if !errors.Is(err, errorvals.ErrObjectNotFoundInRepoError) {
+		ac.logger.WarnContext(ctx, "Failed to get introspection from cache",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+	}
+
+	intro, err := ac.isTokenValid(token)
+
+	if err != nil || !intro.Active {
+		return intro, err
+	}
+
+	ttl := min(time.Until(time.Unix(intro.ExpiresAt, 0)), ac.cacheMaxTTL)
+
+	if ttl <= 0 {
+		return intro, nil
+	}
+
+	sessionID := intro.SessionID
+	if sessionID == consts.EmptyString {
+		sessionID = intro.SessionState
+	}
+
+	err = ac.cache.Set(ctx, key, sessionID, intro, ttl)
+
+	if err != nil {
+		ac.logger.WarnContext(ctx, "Failed to put introspection to cache",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+	}
+
+	return intro, nil
+}
+
 func (ac *AuthUsecase) isTokenValid(token string) (model.IntrospectDTO, error) {
 	introspectURL := ac.kcConfig.RealmAddress + "/token/introspect"
 
@@ -257,7 +408,7 @@ func (ac *AuthUsecase) isTokenValid(token string) (model.IntrospectDTO, error) {
 	client := &http.Client{Timeout: ac.kcTimeout}
 	resp, err := client.Do(req)
 	if err != nil {
-		return model.IntrospectDTO{}, err
+		return model.IntrospectDTO{}, fmt.Errorf("%w: %s", errorvals.ErrUpstreamUnavailable, err.Error())
 	}
 	defer func() {
 		if resp != nil && resp.Body != nil {
@@ -265,6 +416,11 @@ func (ac *AuthUsecase) isTokenValid(token string) (model.IntrospectDTO, error) {
 		}
 	}()
 
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return model.IntrospectDTO{}, fmt.Errorf("%w: introspection status code: %d",
+			errorvals.ErrUpstreamUnavailable, resp.StatusCode)
+	}
+
 	var result model.IntrospectDTO
 	err = json.NewDecoder(resp.Body).Decode(&result)
 	if err != nil {
@@ -291,7 +447,7 @@ func (ac *AuthUsecase) refreshTokens(refreshToken string) (model.TokenDTO, error
 	client := &http.Client{Timeout: ac.kcTimeout}
 	resp, err := client.Do(req)
 	if err != nil {
-		return model.TokenDTO{}, err
+		return model.TokenDTO{}, fmt.Errorf("%w: %s", errorvals.ErrUpstreamUnavailable, err.Error())
 	}
 	defer func() {
 		if resp != nil && resp.Body != nil {
@@ -299,13 +455,23 @@ func (ac *AuthUsecase) refreshTokens(refreshToken string) (model.TokenDTO, error
 		}
 	}()
 
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return model.TokenDTO{}, fmt.Errorf("%w: refresh status code: %d", errorvals.ErrUpstreamUnavailable,
+			resp.StatusCode)
+	case resp.StatusCode >= http.StatusBadRequest:
+		// invalid_grant: refresh token истёк или отозван
+		return model.TokenDTO{}, fmt.Errorf("%w: refresh status code: %d", errorvals.ErrTokenInvalid,
+			resp.StatusCode)
+	}
+
 	var tokens model.TokenDTO
 	err = json.NewDecoder(resp.Body).Decode(&tokens)
 	return tokens, err
 }
 
 func (ac *AuthUsecase) GetUserID(ctx context.Context, at string, rt string) (model.TokenGRPCDTO, error) {
-	intro, err := ac.isTokenValid(at)
+	intro, err := ac.introspect(ctx, at)
 
 	if err != nil {
 		return model.TokenGRPCDTO{}, err
@@ -313,19 +479,32 @@ func (ac *AuthUsecase) GetUserID(ctx context.Context, at string, rt string) (mod
 
 	if !intro.Active {
 		ac.logger.DebugContext(ctx, "tokens not active")
+		if rerr := ac.checkRefreshReuse(ctx, rt); rerr != nil {
+			return model.TokenGRPCDTO{}, rerr
+		}
 		newTokens, rerr := ac.refreshTokens(rt)
 		if rerr != nil {
 			ac.logger.ErrorContext(ctx, "failed to obtain new tokens",
 				slog.String(consts.ErrorLoggerKey, rerr.Error()))
 			return model.TokenGRPCDTO{}, rerr
 		}
+		if rerr = ac.markRefreshUsed(ctx, rt, newTokens.RefreshToken); rerr != nil {
+			return model.TokenGRPCDTO{}, rerr
+		}
+		claims := ac.issuedClaims(ctx, newTokens.AccessToken)
+		userID := intro.Subject
+		if userID == consts.EmptyString {
+			// access token уже истёк вместе с cookie или интроспекция вернула inactive без sub
+			userID = claims.Subject
+		}
 		return model.TokenGRPCDTO{
-			UserID:       intro.Subject,
+			UserID:       userID,
 			AccessToken:  newTokens.AccessToken,
 			RefreshToken: newTokens.RefreshToken,
 			IDToken:      newTokens.IDToken,
 			ExpiresIn:    newTokens.ExpiresIn,
 			RefreshExp:   newTokens.RefreshExp,
+			Claims:       claims,
 		}, nil
 	}
 	ac.logger.DebugContext(ctx, "tokens active")
@@ -334,5 +513,287 @@ func (ac *AuthUsecase) GetUserID(ctx context.Context, at string, rt string) (mod
 		UserID:       intro.Subject,
 		AccessToken:  "",
 		RefreshToken: "",
+		Claims:       intro,
 	}, nil
 }
+
+// issuedClaims читает claims access token, который keycloak только что выдал по refresh token:
+// проверять его повторно незачем.
+func (ac *AuthUsecase) issuedClaims(ctx context.Context, at string) model.IntrospectDTO {
+	claims, err := jwt.ExtractClaims(at)
+
+	if err != nil {
+		ac.logger.WarnContext(ctx, "Failed to parse issued access token",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.IntrospectDTO{}
+	}
+
+	return introspectionFromClaims(claims)
+}
+
+// VerifyAccessToken проверяет access token без обновления пары (Authorization: Bearer).
+// Истёкший, отозванный или поддельный токен — errorvals.ErrTokenInvalid.
+func (ac *AuthUsecase) VerifyAccessToken(ctx context.Context, at string) (model.IntrospectDTO, error) {
+	if at == consts.EmptyString {
+		return model.IntrospectDTO{}, fmt.Errorf("%w: empty token", errorvals.ErrTokenInvalid)
+	}
+
+	intro, err := ac.introspect(ctx, at)
+
+	if err != nil {
+		return model.IntrospectDTO{}, err
+	}
+
+	if !intro.Active {
+		return model.IntrospectDTO{}, fmt.Errorf("%w: token is not active", errorvals.ErrTokenInvalid)
+	}
+
+	return intro, nil
+}
+
+// refreshMark — ключ отметки refresh token: сессия keycloak, хэш токена и сколько её хранить.
+type refreshMark struct {
+	claims    jwt.Claims
+	sessionID string
+	hash      string
+	ttl       time.Duration
+}
+
+// parseRefreshMark читает claims refresh token без проверки подписи (keycloak подписывает его
+// ключом реалма, которого у нас нет). Поэтому отметки пишутся только после того, как keycloak
+// принял токен, а до обмена они лишь читаются.
+func (ac *AuthUsecase) parseRefreshMark(ctx context.Context, rt string) (refreshMark, bool) {
+	if ac.refreshRepo == nil || rt == consts.EmptyString {
+		return refreshMark{}, false
+	}
+
+	claims, err := jwt.ExtractClaims(rt)
+
+	if err != nil {
+		// такой токен не примет и keycloak
+		ac.logger.WarnContext(ctx, "Failed to parse refresh token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return refreshMark{}, false
+	}
+
+	sessionID := claims.SessionID
+	if sessionID == consts.EmptyString {
+		sessionID = claims.SessionState
+	}
+
+	ttl := refreshReuseFallbackTTL
+	if claims.ExpiresAt > 0 {
+		ttl = time.Until(time.Unix(claims.ExpiresAt, 0))
+	}
+
+	if sessionID == consts.EmptyString || ttl <= 0 {
+		return refreshMark{}, false
+	}
+
+	return refreshMark{claims: claims, sessionID: sessionID, hash: sha256Hex(rt), ttl: ttl}, true
+}
+
+// checkRefreshReuse до обмена проверяет, не предъявлялся ли refresh token раньше. Повторное
+// предъявление уже обменянного токена (позже refresh-reuse-grace, чтобы не ловить параллельные
+// запросы одного клиента) считается кражей: сессия keycloak завершается целиком, вместе
+// с токенами, выданными по украденному.
+func (ac *AuthUsecase) checkRefreshReuse(ctx context.Context, rt string) error {
+	mark, ok := ac.parseRefreshMark(ctx, rt)
+
+	if !ok {
+		return nil
+	}
+
+	firstUsed, err := ac.refreshRepo.UsedAt(ctx, mark.sessionID, mark.hash)
+
+	if err != nil {
+		ac.logger.WarnContext(ctx, "Failed to check refresh token reuse",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil
+	}
+
+	return ac.handleRefreshReuse(ctx, mark, firstUsed, rt)
+}
+
+// markRefreshUsed отмечает refresh token использованным после успешного обмена: сбой keycloak
+// или поддельный токен отметки не оставляют. Если параллельный запрос успел обменять тот же
+// токен раньше и за пределами grace, сессия отзывается по только что выданному newRT.
+func (ac *AuthUsecase) markRefreshUsed(ctx context.Context, rt string, newRT string) error {
+	mark, ok := ac.parseRefreshMark(ctx, rt)
+
+	if !ok {
+		return nil
+	}
+
+	firstUsed, err := ac.refreshRepo.MarkUsed(ctx, mark.sessionID, mark.hash, mark.ttl)
+
+	if err != nil {
+		ac.logger.WarnContext(ctx, "Failed to mark refresh token used",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return nil
+	}
+
+	return ac.handleRefreshReuse(ctx, mark, firstUsed, newRT)
+}
+
+// ForgetRefresh снимает отметку с refresh token, если его обмен не довели до конца
+// (например, не сохранили новую пару): повтор с тем же токеном не должен считаться кражей.
+func (ac *AuthUsecase) ForgetRefresh(ctx context.Context, rt string) {
+	mark, ok := ac.parseRefreshMark(ctx, rt)
+
+	if !ok {
+		return
+	}
+
+	if err := ac.refreshRepo.Unmark(ctx, mark.sessionID, mark.hash); err != nil {
+		ac.logger.WarnContext(ctx, "Failed to unmark refresh token",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+	}
+}
+
+// handleRefreshReuse завершает сессию keycloak через logoutRT, если токен уже обменивали
+// раньше, чем refresh-reuse-grace назад.
+func (ac *AuthUsecase) handleRefreshReuse(ctx context.Context, mark refreshMark, firstUsed time.Time,
+	logoutRT string) error {
+	if firstUsed.IsZero() || time.Since(firstUsed) <= ac.kcConfig.RefreshReuseGrace {
+		return nil
+	}
+
+	ac.security.RefreshTokenReuses.Inc()
+	ac.logger.ErrorContext(ctx, "Security incident: refresh token reuse, revoking session",
+		slog.String("incident", "refresh_token_reuse"), slog.String("sub", mark.claims.Subject),
+		slog.String("sid", mark.sessionID), slog.Time("first_used", firstUsed))
+
+	if err := ac.logoutSession(ctx, logoutRT); err != nil {
+		ac.logger.ErrorContext(ctx, "Failed to revoke session after refresh token reuse",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+	}
+
+	ac.purgeCachedSession(ctx, mark.sessionID)
+
+	return errorvals.ErrRefreshTokenReused
+}
+
+func (ac *AuthUsecase) purgeCachedSession(ctx context.Context, sessionID string) {
+	if ac.cache == nil || sessionID == consts.EmptyString {
+		return
+	}
+
+	if err := ac.cache.PurgeSession(ctx, sessionID); err != nil {
+		ac.logger.WarnContext(ctx, "Failed to purge session from introspection cache",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+	}
+}
+
+// RevokeOwned — Revoke от имени пользователя owner: токен с чужим sub не отзывается
+// (errorvals.ErrTokenNotOwned). Подпись не проверяется: подделанный токен keycloak всё равно не отзовёт.
+func (ac *AuthUsecase) RevokeOwned(ctx context.Context, owner string, token string, hint string) error {
+	claims, err := jwt.ExtractClaims(token)
+
+	if err != nil {
+		return fmt.Errorf("%w: token is not a jwt", errorvals.ErrInvalidArgument)
+	}
+
+	if owner == consts.EmptyString || claims.Subject != owner {
+		ac.logger.WarnContext(ctx, "Attempt to revoke token of another user", slog.String("owner", owner),
+			slog.String("sub", claims.Subject))
+		return errorvals.ErrTokenNotOwned
+	}
+
+	return ac.Revoke(ctx, token, hint)
+}
+
+// Revoke отзывает токен через /revoke keycloak (RFC 7009). hint — TokenTypeHintAccess или
+// TokenTypeHintRefresh, прочие значения не передаются. Отзыв неизвестного токена не ошибка.
+func (ac *AuthUsecase) Revoke(ctx context.Context, token string, hint string) error {
+	data := url.Values{}
+	data.Set("token", token)
+	if hint == TokenTypeHintAccess || hint == TokenTypeHintRefresh {
+		data.Set("token_type_hint", hint)
+	}
+	data.Set("client_id", ac.kcConfig.ClientID)
+	for ac.kcCSUpdating.Load() {
+	}
+	data.Set("client_secret", ac.kcConfig.ClientSecret)
+
+	rCtx, cancel := context.WithTimeout(ctx, ac.kcTimeout)
+	defer cancel()
+	resp, err := ac.revokeClient.PostForm(rCtx, data)
+	defer func() {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	if err != nil {
+		ac.logger.ErrorContext(ctx, "Failed to revoke token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return err
+	}
+
+	// закэшированный результат интроспекции иначе пережил бы отзыв
+	if claims, cerr := jwt.ExtractClaims(token); cerr == nil {
+		sessionID := claims.SessionID
+		if sessionID == consts.EmptyString {
+			sessionID = claims.SessionState
+		}
+		ac.purgeCachedSession(ctx, sessionID)
+	}
+
+	return nil
+}
+
+// RevokeTokens отзывает пару токенов при logout. Refresh token отзывается первым,
+// чтобы по нему уже нельзя было получить новый access token.
+func (ac *AuthUsecase) RevokeTokens(ctx context.Context, at string, rt string) error {
+	var errs []error
+
+	if rt != consts.EmptyString {
+		errs = append(errs, ac.Revoke(ctx, rt, TokenTypeHintRefresh))
+	}
+
+	if at != consts.EmptyString {
+		errs = append(errs, ac.Revoke(ctx, at, TokenTypeHintAccess))
+	}
+
+	return errors.Join(errs...)
+}
+
+// logoutSession завершает сессию keycloak, которой принадлежит refresh token (backchannel logout).
+// keycloak принимает для этого и уже обменянный токен.
+func (ac *AuthUsecase) logoutSession(ctx context.Context, refreshToken string) error {
+	data := url.Values{}
+	data.Set("client_id", ac.kcConfig.ClientID)
+	for ac.kcCSUpdating.Load() {
+	}
+	data.Set("client_secret", ac.kcConfig.ClientSecret)
+	data.Set("refresh_token", refreshToken)
+
+	lCtx, cancel := context.WithTimeout(ctx, ac.kcTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(lCtx, http.MethodPost,
+		ac.kcConfig.InterRealmAddress+ac.kcConfig.LogoutEndpoint, strings.NewReader(data.Encode()))
+
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Timeout: ac.kcTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("keycloak logout returned %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func sha256Hex(s string) string {
+	hash := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(hash[:])
+}
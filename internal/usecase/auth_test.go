@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"io"
@@ -9,15 +10,21 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dnonakolesax/noted-auth/internal/configs"
 	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/jwt"
+	"github.com/dnonakolesax/noted-auth/internal/metrics"
 	"github.com/dnonakolesax/noted-auth/internal/model"
 )
 
@@ -32,38 +39,56 @@ type stateRepoStub struct {
 
 	setCalls []setCall
 	get      map[string]getResult
+	consumed map[string]bool
 }
 
 type setCall struct {
-	state       string
-	redirectURI string
-	timeout     time.Duration
+	state   string
+	record  model.AuthState
+	timeout time.Duration
 }
 
 type getResult struct {
-	val string
+	val model.AuthState
 	err error
 }
 
 func newStateRepoStub() *stateRepoStub {
-	return &stateRepoStub{get: make(map[string]getResult)}
+	return &stateRepoStub{get: make(map[string]getResult), consumed: make(map[string]bool)}
 }
 
 func (s *stateRepoStub) SetState(_ context.Context, state string,
-	redirectURI string, timeout time.Duration) error {
+	record model.AuthState, timeout time.Duration) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.setCalls = append(s.setCalls, setCall{state: state, redirectURI: redirectURI, timeout: timeout})
+	s.setCalls = append(s.setCalls, setCall{state: state, record: record, timeout: timeout})
 	return nil
 }
 
 func (s *stateRepoStub) GetState(_ context.Context,
-	state string) (string, error) {
+	state string) (model.AuthState, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	r, ok := s.get[state]
 	if !ok {
-		return "", errorvals.ErrObjectNotFoundInRepoError
+		return model.AuthState{}, errorvals.ErrObjectNotFoundInRepoError
+	}
+	return r.val, r.err
+}
+
+func (s *stateRepoStub) ConsumeState(_ context.Context, state string, _ time.Duration) (model.AuthState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.consumed[state] {
+		return model.AuthState{}, errorvals.ErrStateReplayed
+	}
+	r, ok := s.get[state]
+	if !ok {
+		return model.AuthState{}, errorvals.ErrObjectNotFoundInRepoError
+	}
+	if r.err == nil {
+		delete(s.get, state)
+		s.consumed[state] = true
 	}
 	return r.val, r.err
 }
@@ -109,7 +134,7 @@ func TestAuthUsecase_GetAuthLink_WritesStateAndReturnsURL(t *testing.T) {
 
 	ac := &AuthUsecase{
 		authLifetime: 5 * time.Minute,
-		repos:        []StateRepo{stateRepo},
+		repo:         stateRepo,
 		kcConfig: configs.KeycloakConfig{
 			RealmAddress:          "https://kc.example",
 			AuthEndpoint:          "/auth",
@@ -117,6 +142,7 @@ func TestAuthUsecase_GetAuthLink_WritesStateAndReturnsURL(t *testing.T) {
 			RedirectURI:           "https://service.example/callback",
 			StateLength:           16,
 			CodeVerifierLength:    32,
+			NonceLength:           16,
 			PostLogoutRedirectURI: "https://service.example/post-logout",
 			LogoutEndpoint:        "logout",
 		},
@@ -124,7 +150,7 @@ func TestAuthUsecase_GetAuthLink_WritesStateAndReturnsURL(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	link, err := ac.GetAuthLink(ctx, "https://return.example/path")
+	link, err := ac.GetAuthLink(ctx, "https://return.example/path", "10.0.0.1", "test-agent")
 	require.NoError(t, err)
 
 	u, err := url.Parse(link)
@@ -140,6 +166,7 @@ func TestAuthUsecase_GetAuthLink_WritesStateAndReturnsURL(t *testing.T) {
 	require.Equal(t, "openid", q.Get("scope"))
 	require.Equal(t, "code", q.Get("response_type"))
 	require.Equal(t, "S256", q.Get("code_challenge_method"))
+	require.NotEmpty(t, q.Get("nonce"))
 
 	// state должен быть base64url-строкой (RawURLEncoding)
 	state := q.Get("state")
@@ -147,21 +174,28 @@ func TestAuthUsecase_GetAuthLink_WritesStateAndReturnsURL(t *testing.T) {
 	_, err = base64.RawURLEncoding.DecodeString(state)
 	require.NoError(t, err)
 
-	// repo должен получить два SetState: state и state:code_verifier
+	// repo должен получить один SetState со всей записью
 	calls := stateRepo.calls()
-	require.Len(t, calls, 2)
+	require.Len(t, calls, 1)
 
 	require.Equal(t, state, calls[0].state)
-	require.Equal(t, "https://return.example/path", calls[0].redirectURI)
 	require.Equal(t, ac.authLifetime, calls[0].timeout)
 
-	require.Equal(t, state+":code_verifier", calls[1].state)
-	require.NotEmpty(t, calls[1].redirectURI) // там хранится b64 code_verifier
-	require.Equal(t, ac.authLifetime, calls[1].timeout)
+	record := calls[0].record
+	require.Equal(t, "https://return.example/path", record.ReturnURL)
+	require.Equal(t, []string{"openid"}, record.Scopes)
+	require.NotZero(t, record.CreatedAt)
+	require.Equal(t, q.Get("nonce"), record.Nonce)
+
+	// IP и user agent хранятся только хэшами
+	require.Equal(t, sha256Hex("10.0.0.1"), record.ClientIPHash)
+	require.Equal(t, sha256Hex("test-agent"), record.UserAgentHash)
 
-	// code_verifier тоже должен быть base64url
-	_, err = base64.RawURLEncoding.DecodeString(calls[1].redirectURI)
+	// code_verifier должен быть base64url, а challenge — его S256
+	_, err = base64.RawURLEncoding.DecodeString(record.CodeVerifier)
 	require.NoError(t, err)
+	challenge := sha256.Sum256([]byte(record.CodeVerifier))
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(challenge[:]), q.Get("code_challenge"))
 }
 
 /* ----------------------------- GetToken (pre-HTTP branches) ----------------------------- */
@@ -170,10 +204,9 @@ func TestAuthUsecase_GetToken_ReturnURLNotFound(t *testing.T) {
 	t.Parallel()
 
 	stateRepo := newStateRepoStub()
-	// GetState вернёт ErrObjectNotFoundInRepoError => returnURL останется ""
-	// code_verifier тоже пустой
+	// промах пробрасывается как есть: handleToken отвечает на него 408 (state истёк)
 	ac := &AuthUsecase{
-		repos:  []StateRepo{stateRepo},
+		repo:   stateRepo,
 		logger: testLogger(),
 		kcConfig: configs.KeycloakConfig{
 			StateLength: 16,
@@ -182,18 +215,17 @@ func TestAuthUsecase_GetToken_ReturnURLNotFound(t *testing.T) {
 	}
 
 	_, err := ac.GetToken(context.Background(), "someState", "code")
-	require.Error(t, err)
-	require.Equal(t, "return URL not found", err.Error())
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
 }
 
 func TestAuthUsecase_GetToken_CodeVerifierNotFound(t *testing.T) {
 	t.Parallel()
 
 	stateRepo := newStateRepoStub()
-	stateRepo.get["st"] = getResult{val: "https://return.example", err: nil}
-	// st:code_verifier отсутствует => verifier ""
+	stateRepo.get["st"] = getResult{val: model.AuthState{ReturnURL: "https://return.example"}, err: nil}
+	// code_verifier отсутствует в записи
 	ac := &AuthUsecase{
-		repos:  []StateRepo{stateRepo},
+		repo:   stateRepo,
 		logger: testLogger(),
 		kcConfig: configs.KeycloakConfig{
 			StateLength: 16,
@@ -210,9 +242,9 @@ func TestAuthUsecase_GetToken_RepoErrorPropagates(t *testing.T) {
 	t.Parallel()
 
 	stateRepo := newStateRepoStub()
-	stateRepo.get["st"] = getResult{val: "", err: io.ErrUnexpectedEOF} // любая ошибка != not found
+	stateRepo.get["st"] = getResult{err: io.ErrUnexpectedEOF} // любая ошибка != not found
 	ac := &AuthUsecase{
-		repos:  []StateRepo{stateRepo},
+		repo:   stateRepo,
 		logger: testLogger(),
 		kcConfig: configs.KeycloakConfig{
 			StateLength: 16,
@@ -224,6 +256,91 @@ func TestAuthUsecase_GetToken_RepoErrorPropagates(t *testing.T) {
 	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
 }
 
+func TestAuthUsecase_GetToken_ReplayedState(t *testing.T) {
+	t.Parallel()
+
+	stateRepo := newStateRepoStub()
+	stateRepo.consumed["st"] = true
+	ac := &AuthUsecase{
+		repo:         stateRepo,
+		logger:       testLogger(),
+		kcCSUpdating: &atomic.Bool{},
+	}
+
+	_, err := ac.GetToken(context.Background(), "st", "code")
+	require.ErrorIs(t, err, errorvals.ErrStateReplayed)
+}
+
+/* ----------------------------- GetToken (code exchange) ----------------------------- */
+
+func newTokenEndpoint(t *testing.T) *httpclient.HTTPClient {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			// NewWithRetry проверяет доступность keycloak HEAD-запросом и ждёт 405
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "verifier", r.PostForm.Get("code_verifier"))
+		_ = json.NewEncoder(w).Encode(model.TokenDTO{AccessToken: "at", RefreshToken: "rt", IDToken: "idt"})
+	}))
+	t.Cleanup(srv.Close)
+
+	hc, err := httpclient.NewWithRetry(srv.URL, &configs.HTTPClientConfig{
+		DialTimeout:     time.Second,
+		MaxIdleConns:    1,
+		IdleConnTimeout: time.Second,
+		RequestTimeout:  time.Second,
+		RetryPolicy:     configs.HTTPRetryPolicyConfig{MaxAttempts: 1, RetryOnStatus: map[int]bool{}},
+	}, metrics.NewHTTPRequestMetrics(prometheus.NewRegistry(), "test"), &atomic.Bool{}, testLogger())
+	require.NoError(t, err)
+
+	return hc
+}
+
+func TestAuthUsecase_GetToken_IDTokenNonceChecked(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		nonce   string
+		wantErr error
+	}{
+		"nonce matches":  {nonce: "nonce-1"},
+		"nonce mismatch": {nonce: "other", wantErr: errorvals.ErrIDTokenInvalid},
+	}
+
+	for name, tc := range cases {
+		stateRepo := newStateRepoStub()
+		stateRepo.get["st"] = getResult{val: model.AuthState{
+			ReturnURL:    "https://return.example",
+			CodeVerifier: "verifier",
+			Nonce:        tc.nonce,
+			Scopes:       []string{"openid"},
+		}}
+
+		ac := &AuthUsecase{
+			repo:         stateRepo,
+			httpClient:   newTokenEndpoint(t),
+			verifier:     verifierStub{nonce: "nonce-1"},
+			kcTimeout:    time.Second,
+			logger:       testLogger(),
+			kcCSUpdating: &atomic.Bool{},
+		}
+
+		dto, err := ac.GetToken(context.Background(), "st", "code")
+		if tc.wantErr != nil {
+			require.ErrorIs(t, err, tc.wantErr, name)
+			require.Empty(t, dto.AccessToken, name)
+			continue
+		}
+		require.NoError(t, err, name)
+		require.Equal(t, "at", dto.AccessToken, name)
+		require.Equal(t, "https://return.example", dto.ReturnURL, name)
+	}
+}
+
 /* ----------------------------- GetLogoutLink ----------------------------- */
 
 func TestAuthUsecase_GetLogoutLink_OK(t *testing.T) {
@@ -243,6 +360,25 @@ func TestAuthUsecase_GetLogoutLink_OK(t *testing.T) {
 	require.Contains(t, got, "id_token_hint=idtoken123")
 }
 
+func TestAuthUsecase_GetLogoutLink_WithoutIDToken(t *testing.T) {
+	t.Parallel()
+
+	ac := &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{
+			RealmAddress:          "https://kc.example/realms/r1",
+			LogoutEndpoint:        "protocol/openid-connect/logout",
+			PostLogoutRedirectURI: "https://service.example/bye",
+			ClientID:              "webpage",
+		},
+		logger: testLogger(),
+	}
+
+	got := ac.GetLogoutLink(context.Background(), "")
+	require.Contains(t, got, "post_logout_redirect_uri=")
+	require.Contains(t, got, "client_id=webpage")
+	require.NotContains(t, got, "id_token_hint")
+}
+
 /* ----------------------------- GetUserID (http-based, via httptest) ----------------------------- */
 
 func TestAuthUsecase_GetUserID_TokenActive_ReturnsSubjectOnly(t *testing.T) {
@@ -322,3 +458,636 @@ func TestAuthUsecase_GetUserID_TokenInactive_RefreshOK_ReturnsNewTokens(t *testi
 	require.Equal(t, int64(111), int64(out.ExpiresIn))
 	require.Equal(t, int64(222), int64(out.RefreshExp))
 }
+
+/* ----------------------------- GetUserID (local verification) ----------------------------- */
+
+type verifierStub struct {
+	claims jwt.Claims
+	err    error
+	// nonce — ожидаемый в VerifyIDToken nonce
+	nonce string
+}
+
+func (v verifierStub) Verify(_ context.Context, _ string) (jwt.Claims, error) {
+	return v.claims, v.err
+}
+
+func (v verifierStub) VerifyIDToken(_ context.Context, _ string, nonce string) (jwt.Claims, error) {
+	if nonce != v.nonce {
+		return jwt.Claims{}, errorvals.ErrIDTokenInvalid
+	}
+	return v.claims, v.err
+}
+
+func TestAuthUsecase_GetUserID_LocalVerification_Active(t *testing.T) {
+	t.Parallel()
+
+	ac := &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{TokenVerification: configs.TokenVerificationLocal},
+		verifier: verifierStub{claims: jwt.Claims{Subject: "user-123"}},
+		logger:   testLogger(),
+	}
+
+	out, err := ac.GetUserID(context.Background(), "access", "refresh")
+	require.NoError(t, err)
+	require.Equal(t, "user-123", out.UserID)
+	require.Empty(t, out.AccessToken)
+	require.True(t, out.Claims.Active)
+	require.Equal(t, "user-123", out.Claims.Subject)
+}
+
+func TestAuthUsecase_GetUserID_LocalVerification_ExpiredRefreshes(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(model.TokenDTO{AccessToken: "newAT", RefreshToken: "newRT", IDToken: "newID"})
+	}))
+	defer srv.Close()
+
+	ac := &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{
+			RealmAddress:      srv.URL,
+			TokenVerification: configs.TokenVerificationLocal,
+		},
+		verifier:  verifierStub{claims: jwt.Claims{Subject: "user-123"}, err: errorvals.ErrTokenExpired},
+		kcTimeout: time.Minute,
+		logger:    testLogger(),
+	}
+
+	out, err := ac.GetUserID(context.Background(), "access", "refresh")
+	require.NoError(t, err)
+	require.Equal(t, "user-123", out.UserID)
+	require.Equal(t, "newAT", out.AccessToken)
+}
+
+func TestAuthUsecase_GetUserID_RefreshReturnsIssuedClaims(t *testing.T) {
+	t.Parallel()
+
+	issued := refreshJWT(t, "sid-2")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(model.TokenDTO{AccessToken: issued, RefreshToken: "newRT", IDToken: "newID"})
+	}))
+	defer srv.Close()
+
+	verifier := &countingVerifier{err: errorvals.ErrTokenExpired}
+	ac := &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{
+			RealmAddress:      srv.URL,
+			TokenVerification: configs.TokenVerificationLocal,
+		},
+		verifier:  verifier,
+		kcTimeout: time.Minute,
+		logger:    testLogger(),
+	}
+
+	out, err := ac.GetUserID(context.Background(), "access", "refresh")
+	require.NoError(t, err)
+	require.Equal(t, issued, out.AccessToken)
+	require.True(t, out.Claims.Active)
+	require.Equal(t, "user-1", out.Claims.Subject)
+	require.Equal(t, "sid-2", out.Claims.SessionID)
+	// только что выданный токен повторно не проверяется
+	require.Equal(t, int32(1), verifier.calls.Load())
+}
+
+func TestAuthUsecase_GetUserID_EmptyAccessTokenTakesSubjectFromIssued(t *testing.T) {
+	t.Parallel()
+
+	issued := refreshJWT(t, "sid-2")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(model.TokenDTO{AccessToken: issued, RefreshToken: "newRT", IDToken: "newID"})
+	}))
+	defer srv.Close()
+
+	verifier := &countingVerifier{}
+	ac := &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{
+			RealmAddress:      srv.URL,
+			TokenVerification: configs.TokenVerificationLocal,
+		},
+		verifier:  verifier,
+		kcTimeout: time.Minute,
+		logger:    testLogger(),
+	}
+
+	// cookie с access token истекла, остался только refresh token
+	out, err := ac.GetUserID(context.Background(), "", "refresh")
+	require.NoError(t, err)
+	require.Equal(t, issued, out.AccessToken)
+	require.Equal(t, "user-1", out.UserID)
+	require.Zero(t, verifier.calls.Load())
+}
+
+type countingVerifier struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (v *countingVerifier) Verify(_ context.Context, _ string) (jwt.Claims, error) {
+	v.calls.Add(1)
+	return jwt.Claims{}, v.err
+}
+
+func (v *countingVerifier) VerifyIDToken(_ context.Context, _ string, _ string) (jwt.Claims, error) {
+	return jwt.Claims{}, v.err
+}
+
+func TestAuthUsecase_GetUserID_LocalVerification_InvalidWithoutFallback(t *testing.T) {
+	t.Parallel()
+
+	ac := &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{TokenVerification: configs.TokenVerificationLocal},
+		verifier: verifierStub{err: errorvals.ErrTokenInvalid},
+		logger:   testLogger(),
+	}
+
+	_, err := ac.GetUserID(context.Background(), "access", "refresh")
+	require.ErrorIs(t, err, errorvals.ErrTokenInvalid)
+}
+
+func TestAuthUsecase_GetUserID_LocalVerification_FallbackToIntrospection(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token/introspect" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(model.IntrospectDTO{Active: true, Subject: "user-123"})
+	}))
+	defer srv.Close()
+
+	ac := &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{
+			RealmAddress:          srv.URL,
+			TokenVerification:     configs.TokenVerificationLocal,
+			IntrospectionFallback: true,
+		},
+		verifier:  verifierStub{err: errorvals.ErrJWKSUnavailable},
+		kcTimeout: time.Minute,
+		logger:    testLogger(),
+	}
+
+	out, err := ac.GetUserID(context.Background(), "access", "refresh")
+	require.NoError(t, err)
+	require.Equal(t, "user-123", out.UserID)
+}
+
+/* ----------------------------- introspection cache ----------------------------- */
+
+type cacheStub struct {
+	mu      sync.Mutex
+	entries map[string]model.IntrospectDTO
+	ttls    map[string]time.Duration
+	sids    map[string]string
+}
+
+func newCacheStub() *cacheStub {
+	return &cacheStub{
+		entries: make(map[string]model.IntrospectDTO),
+		ttls:    make(map[string]time.Duration),
+		sids:    make(map[string]string),
+	}
+}
+
+func (c *cacheStub) Get(_ context.Context, key string) (model.IntrospectDTO, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dto, ok := c.entries[key]
+	if !ok {
+		return model.IntrospectDTO{}, errorvals.ErrObjectNotFoundInRepoError
+	}
+	return dto, nil
+}
+
+func (c *cacheStub) Set(_ context.Context, key string, sessionID string, dto model.IntrospectDTO,
+	ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = dto
+	c.ttls[key] = ttl
+	c.sids[key] = sessionID
+	return nil
+}
+
+func (c *cacheStub) PurgeSession(_ context.Context, sessionID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, sid := range c.sids {
+		if sid == sessionID {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+func newIntrospectServer(t *testing.T, dto model.IntrospectDTO) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	calls := &atomic.Int32{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token/introspect" {
+			http.NotFound(w, r)
+			return
+		}
+		calls.Add(1)
+		_ = json.NewEncoder(w).Encode(dto)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, calls
+}
+
+func newCachedAuthUsecase(realm string, cache IntrospectionCache, maxTTL time.Duration) *AuthUsecase {
+	return &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{
+			RealmAddress:      realm,
+			TokenVerification: configs.TokenVerificationIntrospection,
+		},
+		cache:        cache,
+		cacheMaxTTL:  maxTTL,
+		cacheMetrics: metrics.NewCacheMetrics(prometheus.NewRegistry(), "test"),
+		kcTimeout:    time.Minute,
+		logger:       testLogger(),
+	}
+}
+
+func TestAuthUsecase_GetUserID_IntrospectionCache_HitSkipsKeycloak(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := newIntrospectServer(t, model.IntrospectDTO{
+		Active:    true,
+		Subject:   "user-123",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		SessionID: "sid-1",
+	})
+	cache := newCacheStub()
+	ac := newCachedAuthUsecase(srv.URL, cache, time.Minute)
+
+	for range 3 {
+		out, err := ac.GetUserID(context.Background(), "access", "refresh")
+		require.NoError(t, err)
+		require.Equal(t, "user-123", out.UserID)
+	}
+
+	require.Equal(t, int32(1), calls.Load())
+	require.InDelta(t, 2, testutil.ToFloat64(ac.cacheMetrics.Hits), 0)
+	require.InDelta(t, 1, testutil.ToFloat64(ac.cacheMetrics.Misses), 0)
+
+	// ключ — хэш токена, а не сам токен
+	require.NotContains(t, cache.entries, "access")
+	for key, sid := range cache.sids {
+		require.Equal(t, "sid-1", sid)
+		require.Equal(t, time.Minute, cache.ttls[key])
+	}
+}
+
+func TestAuthUsecase_GetUserID_IntrospectionCache_TTLBoundedByExp(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newIntrospectServer(t, model.IntrospectDTO{
+		Active:    true,
+		Subject:   "user-123",
+		ExpiresAt: time.Now().Add(10 * time.Second).Unix(),
+	})
+	cache := newCacheStub()
+	ac := newCachedAuthUsecase(srv.URL, cache, time.Hour)
+
+	_, err := ac.GetUserID(context.Background(), "access", "refresh")
+	require.NoError(t, err)
+
+	require.Len(t, cache.ttls, 1)
+	for _, ttl := range cache.ttls {
+		require.LessOrEqual(t, ttl, 10*time.Second)
+		require.Positive(t, ttl)
+	}
+}
+
+func TestAuthUsecase_GetUserID_IntrospectionCache_InactiveNotCached(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := newIntrospectServer(t, model.IntrospectDTO{Active: false, Subject: "user-123"})
+	cache := newCacheStub()
+	ac := newCachedAuthUsecase(srv.URL, cache, time.Minute)
+
+	intro, err := ac.introspect(context.Background(), "access")
+	require.NoError(t, err)
+	require.False(t, intro.Active)
+
+	_, err = ac.introspect(context.Background(), "access")
+	require.NoError(t, err)
+
+	require.Empty(t, cache.entries)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+/* ----------------------------- Refresh token reuse ----------------------------- */
+
+type refreshRepoStub struct {
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+func (r *refreshRepoStub) MarkUsed(_ context.Context, sessionID string, tokenHash string,
+	_ time.Duration) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := sessionID + "/" + tokenHash
+	first, ok := r.used[key]
+	if !ok {
+		r.used[key] = time.Now()
+		return time.Time{}, nil
+	}
+	return first, nil
+}
+
+func (r *refreshRepoStub) UsedAt(_ context.Context, sessionID string, tokenHash string) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.used[sessionID+"/"+tokenHash], nil
+}
+
+func (r *refreshRepoStub) Unmark(_ context.Context, sessionID string, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.used, sessionID+"/"+tokenHash)
+	return nil
+}
+
+func refreshJWT(t *testing.T, sid string) string {
+	t.Helper()
+
+	payload, err := json.Marshal(map[string]any{"sid": sid, "sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+
+	return "e30." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+// newRefreshReuseUsecase поднимает keycloak, который первые failures обменов отвечает 503,
+// а refresh token с суффиксом "forged" не принимает.
+func newRefreshReuseUsecase(t *testing.T, grace time.Duration,
+	failures int32) (*AuthUsecase, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+
+	refreshes := &atomic.Int32{}
+	logouts := &atomic.Int32{}
+	failed := &atomic.Int32{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token/introspect":
+			_ = json.NewEncoder(w).Encode(model.IntrospectDTO{Active: false, Subject: "user-1"})
+		case "/token":
+			_ = r.ParseForm()
+			if failed.Add(1) <= failures {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if strings.HasSuffix(r.PostForm.Get("refresh_token"), "forged") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			refreshes.Add(1)
+			_ = json.NewEncoder(w).Encode(model.TokenDTO{AccessToken: "newAT", RefreshToken: "newRT", IDToken: "id"})
+		case "/logout":
+			_ = r.ParseForm()
+			if r.PostForm.Get("refresh_token") == "" || r.PostForm.Get("client_secret") != "sec" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			logouts.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{
+			RealmAddress:      srv.URL,
+			InterRealmAddress: srv.URL,
+			LogoutEndpoint:    "/logout",
+			ClientID:          "cid",
+			ClientSecret:      "sec",
+			RefreshReuseGrace: grace,
+		},
+		kcTimeout:    time.Minute,
+		refreshRepo:  &refreshRepoStub{used: map[string]time.Time{}},
+		security:     metrics.NewSecurityMetrics(prometheus.NewRegistry()),
+		logger:       testLogger(),
+		kcCSUpdating: &atomic.Bool{},
+	}, refreshes, logouts
+}
+
+func TestAuthUsecase_GetUserID_RefreshReuseRevokesSession(t *testing.T) {
+	t.Parallel()
+
+	ac, refreshes, logouts := newRefreshReuseUsecase(t, 0, 0)
+	rt := refreshJWT(t, "sid-1")
+
+	out, err := ac.GetUserID(context.Background(), "", rt)
+	require.NoError(t, err)
+	require.Equal(t, "newAT", out.AccessToken)
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = ac.GetUserID(context.Background(), "", rt)
+	require.ErrorIs(t, err, errorvals.ErrRefreshTokenReused)
+	require.Equal(t, int32(1), refreshes.Load())
+	require.Equal(t, int32(1), logouts.Load())
+	require.InDelta(t, 1, testutil.ToFloat64(ac.security.RefreshTokenReuses), 0)
+
+	// другая сессия не затронута
+	_, err = ac.GetUserID(context.Background(), "", refreshJWT(t, "sid-2"))
+	require.NoError(t, err)
+}
+
+func TestAuthUsecase_GetUserID_RefreshReuseWithinGraceAllowed(t *testing.T) {
+	t.Parallel()
+
+	ac, refreshes, logouts := newRefreshReuseUsecase(t, time.Minute, 0)
+	rt := refreshJWT(t, "sid-1")
+
+	_, err := ac.GetUserID(context.Background(), "", rt)
+	require.NoError(t, err)
+	_, err = ac.GetUserID(context.Background(), "", rt)
+	require.NoError(t, err)
+
+	require.Equal(t, int32(2), refreshes.Load())
+	require.Equal(t, int32(0), logouts.Load())
+}
+
+func TestAuthUsecase_GetUserID_FailedRefreshNotMarked(t *testing.T) {
+	t.Parallel()
+
+	ac, refreshes, logouts := newRefreshReuseUsecase(t, 0, 1)
+	rt := refreshJWT(t, "sid-1")
+
+	_, err := ac.GetUserID(context.Background(), "", rt)
+	require.ErrorIs(t, err, errorvals.ErrUpstreamUnavailable)
+
+	time.Sleep(5 * time.Millisecond)
+
+	// повтор после сбоя keycloak — не кража
+	out, err := ac.GetUserID(context.Background(), "", rt)
+	require.NoError(t, err)
+	require.Equal(t, "newAT", out.AccessToken)
+	require.Equal(t, int32(1), refreshes.Load())
+	require.Equal(t, int32(0), logouts.Load())
+}
+
+func TestAuthUsecase_GetUserID_RejectedRefreshNotMarked(t *testing.T) {
+	t.Parallel()
+
+	ac, _, logouts := newRefreshReuseUsecase(t, 0, 0)
+	// поддельный токен с чужим sid: keycloak его не принимает, отметок в сессии не остаётся
+	forged := refreshJWT(t, "sid-1") + "forged"
+
+	for range 2 {
+		_, err := ac.GetUserID(context.Background(), "", forged)
+		require.ErrorIs(t, err, errorvals.ErrTokenInvalid)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	require.Empty(t, ac.refreshRepo.(*refreshRepoStub).used)
+	require.Equal(t, int32(0), logouts.Load())
+}
+
+func TestAuthUsecase_ForgetRefresh_AllowsRetry(t *testing.T) {
+	t.Parallel()
+
+	ac, refreshes, logouts := newRefreshReuseUsecase(t, 0, 0)
+	rt := refreshJWT(t, "sid-1")
+
+	_, err := ac.GetUserID(context.Background(), "", rt)
+	require.NoError(t, err)
+
+	// новую пару не сохранили — старый токен предъявят снова
+	ac.ForgetRefresh(context.Background(), rt)
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = ac.GetUserID(context.Background(), "", rt)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), refreshes.Load())
+	require.Equal(t, int32(0), logouts.Load())
+}
+
+/* ----------------------------- Revoke ----------------------------- */
+
+type revokeCall struct {
+	token string
+	hint  string
+}
+
+func newRevokeUsecase(t *testing.T) (*AuthUsecase, *[]revokeCall, *cacheStub) {
+	t.Helper()
+
+	var (
+		mu    sync.Mutex
+		calls []revokeCall
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		if r.PostForm.Get("client_id") != "cid" || r.PostForm.Get("client_secret") != "sec" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		calls = append(calls, revokeCall{token: r.PostForm.Get("token"), hint: r.PostForm.Get("token_type_hint")})
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	hc, err := httpclient.NewWithRetry(srv.URL, &configs.HTTPClientConfig{
+		DialTimeout:     time.Second,
+		MaxIdleConns:    1,
+		IdleConnTimeout: time.Second,
+		RequestTimeout:  time.Second,
+		RetryPolicy:     configs.HTTPRetryPolicyConfig{MaxAttempts: 1, RetryOnStatus: map[int]bool{}},
+	}, metrics.NewHTTPRequestMetrics(prometheus.NewRegistry(), "test"), &atomic.Bool{}, testLogger())
+	require.NoError(t, err)
+
+	cache := newCacheStub()
+
+	return &AuthUsecase{
+		kcConfig:     configs.KeycloakConfig{ClientID: "cid", ClientSecret: "sec"},
+		revokeClient: hc,
+		cache:        cache,
+		kcTimeout:    time.Minute,
+		logger:       testLogger(),
+		kcCSUpdating: &atomic.Bool{},
+	}, &calls, cache
+}
+
+func TestAuthUsecase_RevokeTokens_RefreshFirst(t *testing.T) {
+	t.Parallel()
+
+	ac, calls, _ := newRevokeUsecase(t)
+
+	require.NoError(t, ac.RevokeTokens(context.Background(), "at", "rt"))
+	require.Equal(t, []revokeCall{
+		{token: "rt", hint: TokenTypeHintRefresh},
+		{token: "at", hint: TokenTypeHintAccess},
+	}, *calls)
+}
+
+func TestAuthUsecase_Revoke_PurgesCachedSession(t *testing.T) {
+	t.Parallel()
+
+	ac, calls, cache := newRevokeUsecase(t)
+	require.NoError(t, cache.Set(context.Background(), "key", "sid-1", model.IntrospectDTO{Active: true}, time.Minute))
+
+	// неизвестный hint не передаётся в keycloak
+	require.NoError(t, ac.Revoke(context.Background(), refreshJWT(t, "sid-1"), "id_token"))
+	require.Len(t, *calls, 1)
+	require.Empty(t, (*calls)[0].hint)
+	require.Empty(t, cache.entries)
+}
+
+func TestAuthUsecase_RevokeOwned_RejectsForeignTokens(t *testing.T) {
+	t.Parallel()
+
+	ac, calls, _ := newRevokeUsecase(t)
+
+	// refreshJWT выписан на user-1
+	require.ErrorIs(t, ac.RevokeOwned(context.Background(), "user-2", refreshJWT(t, "sid-1"), ""),
+		errorvals.ErrTokenNotOwned)
+	require.ErrorIs(t, ac.RevokeOwned(context.Background(), "user-1", "opaque", ""),
+		errorvals.ErrInvalidArgument)
+	require.Empty(t, *calls)
+
+	require.NoError(t, ac.RevokeOwned(context.Background(), "user-1", refreshJWT(t, "sid-1"), ""))
+	require.Len(t, *calls, 1)
+}
+
+/* ----------------------------- VerifyAccessToken (bearer) ----------------------------- */
+
+func TestAuthUsecase_VerifyAccessToken_ExpiredNotRefreshed(t *testing.T) {
+	t.Parallel()
+
+	ac := &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{TokenVerification: configs.TokenVerificationLocal},
+		verifier: verifierStub{claims: jwt.Claims{Subject: "user-123"}, err: errorvals.ErrTokenExpired},
+		logger:   testLogger(),
+	}
+
+	_, err := ac.VerifyAccessToken(context.Background(), "access")
+	require.ErrorIs(t, err, errorvals.ErrTokenInvalid)
+
+	_, err = ac.VerifyAccessToken(context.Background(), "")
+	require.ErrorIs(t, err, errorvals.ErrTokenInvalid)
+
+	ac.verifier = verifierStub{claims: jwt.Claims{Subject: "user-123"}}
+	intro, err := ac.VerifyAccessToken(context.Background(), "access")
+	require.NoError(t, err)
+	require.Equal(t, "user-123", intro.Subject)
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+const grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// ExchangeToken обменивает access token пользователя на токен для audience (RFC 8693) от имени
+// клиента реалма. Результат кэшируется по хэшу subjectToken до expires_in - refresh-skew,
+// но не дольше жизни самого subjectToken.
+func (ac *AuthUsecase) ExchangeToken(ctx context.Context, subjectToken string,
+	audience string) (model.ExchangedToken, error) {
+	if !ac.exchange.Enabled {
+		return model.ExchangedToken{}, errorvals.ErrTokenExchangeDisabled
+	}
+
+	if audience == consts.EmptyString {
+		return model.ExchangedToken{}, fmt.Errorf("%w: empty audience", errorvals.ErrInvalidArgument)
+	}
+
+	if !slices.Contains(ac.exchange.Audiences, audience) {
+		return model.ExchangedToken{}, fmt.Errorf("%w: %s", errorvals.ErrAudienceNotAllowed, audience)
+	}
+
+	// keycloak обменял бы и отозванный токен, если его подпись ещё валидна
+	intro, err := ac.VerifyAccessToken(ctx, subjectToken)
+
+	if err != nil {
+		ac.logger.WarnContext(ctx, "Subject token rejected", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.ExchangedToken{}, err
+	}
+
+	key := sha256Hex(subjectToken) + " " + audience
+
+	if ac.exchangeRepo != nil {
+		cached, cErr := ac.exchangeRepo.Get(ctx, key)
+
+		if cErr == nil {
+			return cached, nil
+		}
+
+		if !errors.Is(cErr, errorvals.ErrObjectNotFoundInRepoError) {
+			ac.logger.WarnContext(ctx, "Failed to read exchange cache", slog.String(consts.ErrorLoggerKey, cErr.Error()))
+		}
+	}
+
+	token, err := ac.exchangeRemote(ctx, subjectToken, audience)
+
+	if err != nil {
+		return model.ExchangedToken{}, err
+	}
+
+	ttl := time.Duration(token.ExpiresIn)*time.Second - ac.exchange.RefreshSkew
+	if intro.ExpiresAt != 0 {
+		ttl = min(ttl, time.Until(time.Unix(intro.ExpiresAt, 0)))
+	}
+
+	if ac.exchangeRepo != nil && ttl > 0 {
+		if err = ac.exchangeRepo.Set(ctx, key, token, ttl); err != nil {
+			ac.logger.WarnContext(ctx, "Failed to cache exchanged token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		}
+	}
+
+	return token, nil
+}
+
+func (ac *AuthUsecase) exchangeRemote(ctx context.Context, subjectToken string,
+	audience string) (model.ExchangedToken, error) {
+	data := url.Values{}
+	data.Set("grant_type", grantTypeTokenExchange)
+	data.Set("client_id", ac.kcConfig.ClientID)
+	for ac.kcCSUpdating.Load() {
+	}
+	data.Set("client_secret", ac.kcConfig.ClientSecret)
+	data.Set("subject_token", subjectToken)
+	data.Set("subject_token_type", model.TokenTypeAccessToken)
+	data.Set("requested_token_type", model.TokenTypeAccessToken)
+	data.Set("audience", audience)
+
+	pCtx, cancel := context.WithTimeout(ctx, ac.kcTimeout)
+	defer cancel()
+	resp, err := ac.httpClient.PostForm(pCtx, data)
+	defer func() {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	if err != nil {
+		ac.logger.ErrorContext(ctx, "Failed to exchange token", slog.String(consts.ErrorLoggerKey, err.Error()),
+			slog.String("audience", audience))
+
+		var statusErr *httpclient.StatusError
+		if errors.As(err, &statusErr) {
+			switch statusErr.Code {
+			case http.StatusBadRequest:
+				return model.ExchangedToken{}, fmt.Errorf("%w: exchange rejected by keycloak",
+					errorvals.ErrInvalidArgument)
+			case http.StatusUnauthorized:
+				return model.ExchangedToken{}, fmt.Errorf("%w: subject token rejected by keycloak",
+					errorvals.ErrTokenInvalid)
+			case http.StatusForbidden:
+				// клиенту реалма не выдано право token-exchange на audience
+				return model.ExchangedToken{}, fmt.Errorf("%w: %s", errorvals.ErrAudienceNotAllowed, audience)
+			}
+		}
+		return model.ExchangedToken{}, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		ac.logger.ErrorContext(ctx, "Failed to read token exchange response",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.ExchangedToken{}, err
+	}
+
+	var token model.ExchangedToken
+	err = easyjson.Unmarshal(body, &token)
+
+	if err != nil {
+		ac.logger.ErrorContext(ctx, "Failed to unmarshal token exchange response",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.ExchangedToken{}, err
+	}
+
+	if token.AccessToken == consts.EmptyString {
+		return model.ExchangedToken{}, fmt.Errorf("%w: empty exchanged token", errorvals.ErrUpstreamUnavailable)
+	}
+
+	if token.IssuedTokenType == consts.EmptyString {
+		token.IssuedTokenType = model.TokenTypeAccessToken
+	}
+
+	ac.logger.InfoContext(ctx, "Token exchanged", slog.String("audience", audience),
+		slog.Int("expires_in", token.ExpiresIn))
+
+	return token, nil
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/jwt"
+	"github.com/dnonakolesax/noted-auth/internal/metrics"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+type exchangeCacheStub struct {
+	entries map[string]model.ExchangedToken
+	ttls    map[string]time.Duration
+}
+
+func (e *exchangeCacheStub) Get(_ context.Context, key string) (model.ExchangedToken, error) {
+	token, ok := e.entries[key]
+	if !ok {
+		return model.ExchangedToken{}, errorvals.ErrObjectNotFoundInRepoError
+	}
+	return token, nil
+}
+
+func (e *exchangeCacheStub) Set(_ context.Context, key string, token model.ExchangedToken,
+	ttl time.Duration) error {
+	e.entries[key] = token
+	e.ttls[key] = ttl
+	return nil
+}
+
+// newExchangeUsecase поднимает token endpoint: audience "billing" keycloak не разрешает (403).
+func newExchangeUsecase(t *testing.T, subjectExp time.Time) (*AuthUsecase, *exchangeCacheStub, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, grantTypeTokenExchange, r.PostForm.Get("grant_type"))
+		require.Equal(t, "noted-webpage", r.PostForm.Get("client_id"))
+		require.Equal(t, "secret", r.PostForm.Get("client_secret"))
+		require.Equal(t, "subject", r.PostForm.Get("subject_token"))
+		require.Equal(t, model.TokenTypeAccessToken, r.PostForm.Get("subject_token_type"))
+
+		calls.Add(1)
+		if r.PostForm.Get("audience") == "billing" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"access_token":"for-%s","expires_in":300,"token_type":"Bearer"}`,
+			r.PostForm.Get("audience"))
+	}))
+	t.Cleanup(srv.Close)
+
+	hc, err := httpclient.NewWithRetry(srv.URL, &configs.HTTPClientConfig{
+		DialTimeout:     time.Second,
+		MaxIdleConns:    1,
+		IdleConnTimeout: time.Second,
+		RequestTimeout:  time.Second,
+		RetryPolicy:     configs.HTTPRetryPolicyConfig{MaxAttempts: 1, RetryOnStatus: map[int]bool{}},
+	}, metrics.NewHTTPRequestMetrics(prometheus.NewRegistry(), "test"), &atomic.Bool{}, testLogger())
+	require.NoError(t, err)
+
+	cache := &exchangeCacheStub{entries: map[string]model.ExchangedToken{}, ttls: map[string]time.Duration{}}
+
+	return &AuthUsecase{
+		kcConfig: configs.KeycloakConfig{
+			ClientID:          "noted-webpage",
+			ClientSecret:      "secret",
+			TokenVerification: configs.TokenVerificationLocal,
+		},
+		httpClient: hc,
+		verifier:   verifierStub{claims: jwt.Claims{Subject: "user-123", ExpiresAt: subjectExp.Unix()}},
+		exchange: configs.TokenExchangeConfig{
+			Enabled:     true,
+			Audiences:   []string{"notes", "billing"},
+			RefreshSkew: 10 * time.Second,
+		},
+		exchangeRepo: cache,
+		kcTimeout:    time.Second,
+		logger:       testLogger(),
+		kcCSUpdating: &atomic.Bool{},
+	}, cache, &calls
+}
+
+func TestAuthUsecase_ExchangeToken_CachesPerSubjectAndAudience(t *testing.T) {
+	t.Parallel()
+
+	ac, cache, calls := newExchangeUsecase(t, time.Now().Add(time.Hour))
+
+	for range 3 {
+		token, err := ac.ExchangeToken(context.Background(), "subject", "notes")
+		require.NoError(t, err)
+		require.Equal(t, "for-notes", token.AccessToken)
+		require.Equal(t, model.TokenTypeAccessToken, token.IssuedTokenType)
+	}
+	require.Equal(t, int32(1), calls.Load())
+
+	// ключ — хэш токена, а не сам токен
+	require.NotContains(t, cache.entries, "subject notes")
+	for _, ttl := range cache.ttls {
+		require.Equal(t, 290*time.Second, ttl)
+	}
+}
+
+func TestAuthUsecase_ExchangeToken_TTLBoundedBySubjectExp(t *testing.T) {
+	t.Parallel()
+
+	ac, cache, _ := newExchangeUsecase(t, time.Now().Add(time.Minute))
+
+	_, err := ac.ExchangeToken(context.Background(), "subject", "notes")
+	require.NoError(t, err)
+
+	require.Len(t, cache.ttls, 1)
+	for _, ttl := range cache.ttls {
+		require.LessOrEqual(t, ttl, time.Minute)
+		require.Positive(t, ttl)
+	}
+}
+
+func TestAuthUsecase_ExchangeToken_Rejections(t *testing.T) {
+	t.Parallel()
+
+	ac, cache, calls := newExchangeUsecase(t, time.Now().Add(time.Hour))
+
+	_, err := ac.ExchangeToken(context.Background(), "subject", "files")
+	require.ErrorIs(t, err, errorvals.ErrAudienceNotAllowed)
+	_, err = ac.ExchangeToken(context.Background(), "subject", "")
+	require.ErrorIs(t, err, errorvals.ErrInvalidArgument)
+	require.Zero(t, calls.Load())
+
+	// в конфиге разрешён, но keycloak не дал клиенту права на обмен
+	_, err = ac.ExchangeToken(context.Background(), "subject", "billing")
+	require.ErrorIs(t, err, errorvals.ErrAudienceNotAllowed)
+	require.Empty(t, cache.entries)
+
+	ac.verifier = verifierStub{err: errorvals.ErrTokenInvalid}
+	_, err = ac.ExchangeToken(context.Background(), "subject", "notes")
+	require.ErrorIs(t, err, errorvals.ErrTokenInvalid)
+
+	ac.exchange.Enabled = false
+	_, err = ac.ExchangeToken(context.Background(), "subject", "notes")
+	require.ErrorIs(t, err, errorvals.ErrTokenExchangeDisabled)
+}
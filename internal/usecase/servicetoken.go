@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mailru/easyjson"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/jwt"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+type serviceTokenEntry struct {
+	token   model.TokenDTO
+	expires time.Time
+}
+
+// ServiceTokenUsecase выдаёт сервисам Noted токены grant client_credentials. Токены кэшируются по
+// паре audience + набор scope до expires_in - skew, одновременные запросы одного ключа ходят
+// в keycloak один раз.
+type ServiceTokenUsecase struct {
+	httpClient *httpclient.HTTPClient
+	clientID   string
+	audiences  map[string]string
+	skew       time.Duration
+	logger     *slog.Logger
+
+	mu     sync.Mutex
+	secret string
+	cache  map[string]serviceTokenEntry
+	group  singleflight.Group
+	now    func() time.Time
+}
+
+// NewServiceTokenUsecase — httpClient должен смотреть на token endpoint реалма.
+func NewServiceTokenUsecase(httpClient *httpclient.HTTPClient, clientID string, secret string,
+	audiences map[string]string, skew time.Duration, logger *slog.Logger, vaultChan chan string) *ServiceTokenUsecase {
+	uc := &ServiceTokenUsecase{
+		httpClient: httpClient,
+		clientID:   clientID,
+		audiences:  audiences,
+		skew:       skew,
+		logger:     logger,
+		secret:     secret,
+		cache:      make(map[string]serviceTokenEntry),
+		now:        time.Now,
+	}
+
+	go uc.MonitorVault(vaultChan)
+
+	return uc
+}
+
+func (su *ServiceTokenUsecase) MonitorVault(vaultChan chan string) {
+	for secret := range vaultChan {
+		su.mu.Lock()
+		su.secret = secret
+		su.mu.Unlock()
+	}
+}
+
+// Get возвращает токен для audience со scopes; порядок и повторы scopes на ключ кэша не влияют.
+func (su *ServiceTokenUsecase) Get(ctx context.Context, audience string, scopes []string) (model.ServiceToken,
+	error) {
+	if audience == "" {
+		return model.ServiceToken{}, fmt.Errorf("%w: empty audience", errorvals.ErrInvalidArgument)
+	}
+
+	audienceScope, ok := su.audiences[audience]
+	if !ok {
+		return model.ServiceToken{}, fmt.Errorf("%w: %s", errorvals.ErrAudienceNotAllowed, audience)
+	}
+
+	scopes = slices.Compact(slices.Sorted(slices.Values(scopes)))
+	scopes = slices.DeleteFunc(scopes, func(s string) bool { return s == "" })
+	key := audience + " " + strings.Join(scopes, " ")
+
+	if entry, ok := su.cached(key); ok {
+		return su.toServiceToken(entry), nil
+	}
+
+	// запрос в keycloak общий для всех ждущих, поэтому не отменяется вместе с контекстом первого из них
+	result, err, shared := su.group.Do(key, func() (any, error) {
+		if entry, ok := su.cached(key); ok {
+			return entry, nil
+		}
+
+		return su.fetch(context.WithoutCancel(ctx), key, audience, audienceScope, scopes)
+	})
+
+	if err != nil {
+		return model.ServiceToken{}, err
+	}
+
+	if shared {
+		su.logger.DebugContext(ctx, "Service token request coalesced", slog.String("audience", audience))
+	}
+
+	entry, _ := result.(serviceTokenEntry)
+
+	return su.toServiceToken(entry), nil
+}
+
+func (su *ServiceTokenUsecase) cached(key string) (serviceTokenEntry, bool) {
+	su.mu.Lock()
+	defer su.mu.Unlock()
+
+	entry, ok := su.cache[key]
+
+	if !ok || !su.now().Before(entry.expires.Add(-su.skew)) {
+		return serviceTokenEntry{}, false
+	}
+
+	return entry, true
+}
+
+// fetch запрашивает scope сервиса вместе с scopes: client_credentials в keycloak игнорирует параметр audience,
+// aud попадает в токен только через audience mapper client scope.
+func (su *ServiceTokenUsecase) fetch(ctx context.Context, key string, audience string, audienceScope string,
+	scopes []string) (serviceTokenEntry, error) {
+	su.mu.Lock()
+	secret := su.secret
+	su.mu.Unlock()
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", su.clientID)
+	form.Set("client_secret", secret)
+	requested := slices.Compact(slices.Sorted(slices.Values(append([]string{audienceScope}, scopes...))))
+	requested = slices.DeleteFunc(requested, func(s string) bool { return s == "" })
+	form.Set("scope", strings.Join(requested, " "))
+
+	resp, err := su.httpClient.PostForm(ctx, form)
+	defer func() {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	if err != nil {
+		su.logger.ErrorContext(ctx, "Failed to get service token", slog.String(consts.ErrorLoggerKey, err.Error()),
+			slog.String("audience", audience))
+
+		// 400 — keycloak не знает запрошенный scope
+		var statusErr *httpclient.StatusError
+		if errors.As(err, &statusErr) && statusErr.Code == http.StatusBadRequest {
+			return serviceTokenEntry{}, fmt.Errorf("%w: scopes rejected by keycloak", errorvals.ErrInvalidArgument)
+		}
+		return serviceTokenEntry{}, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		su.logger.ErrorContext(ctx, "Failed to read service token response",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return serviceTokenEntry{}, err
+	}
+
+	var dto model.TokenDTO
+	err = easyjson.Unmarshal(body, &dto)
+
+	if err != nil {
+		su.logger.ErrorContext(ctx, "Failed to unmarshal service token response",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return serviceTokenEntry{}, err
+	}
+
+	if dto.AccessToken == "" {
+		return serviceTokenEntry{}, fmt.Errorf("%w: empty service token", errorvals.ErrUpstreamUnavailable)
+	}
+
+	claims, err := jwt.ExtractClaims(dto.AccessToken)
+
+	if err != nil {
+		su.logger.ErrorContext(ctx, "Failed to parse service token", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return serviceTokenEntry{}, fmt.Errorf("%w: malformed service token", errorvals.ErrUpstreamUnavailable)
+	}
+
+	// без audience mapper у scope keycloak молча выдаст токен без нужного aud — такой не кэшируем
+	if !slices.Contains(claims.Audience, audience) {
+		su.logger.ErrorContext(ctx, "Service token misses requested audience", slog.String("audience", audience),
+			slog.String("scope", audienceScope), slog.Any("aud", []string(claims.Audience)))
+		return serviceTokenEntry{}, fmt.Errorf("%w: %s missing from aud", errorvals.ErrAudienceNotAllowed, audience)
+	}
+
+	entry := serviceTokenEntry{token: dto, expires: su.now().Add(time.Duration(dto.ExpiresIn) * time.Second)}
+
+	su.mu.Lock()
+	su.evictExpired()
+	su.cache[key] = entry
+	su.mu.Unlock()
+
+	su.logger.InfoContext(ctx, "Service token issued", slog.String("audience", audience),
+		slog.String("scope", dto.Scope), slog.Int("expires_in", dto.ExpiresIn))
+
+	return entry, nil
+}
+
+// evictExpired вызывается под su.mu: ключей немного (audiences × наборы scope), так что полный проход дешёвый.
+func (su *ServiceTokenUsecase) evictExpired() {
+	now := su.now()
+
+	for key, entry := range su.cache {
+		if !now.Before(entry.expires) {
+			delete(su.cache, key)
+		}
+	}
+}
+
+func (su *ServiceTokenUsecase) toServiceToken(entry serviceTokenEntry) model.ServiceToken {
+	return model.ServiceToken{
+		AccessToken: entry.token.AccessToken,
+		ExpiresIn:   int(entry.expires.Sub(su.now()) / time.Second),
+		TokenType:   entry.token.TokenType,
+		Scope:       entry.token.Scope,
+	}
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/metrics"
+)
+
+// newTokenBroker поднимает token endpoint, который отвечает с задержкой delay и считает запросы.
+// Как audience mapper keycloak, scope вида <сервис>-aud добавляет сервис в aud; billing настроен без mapper.
+func newTokenBroker(t *testing.T, delay time.Duration) (*ServiceTokenUsecase, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !assertForm(t, r) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		n := calls.Add(1)
+		time.Sleep(delay)
+		_, _ = fmt.Fprintf(w, `{"access_token":%q,"expires_in":300,"token_type":"Bearer","scope":%q}`,
+			serviceJWT(t, r.PostForm.Get("scope"), n), r.PostForm.Get("scope"))
+	}))
+	t.Cleanup(srv.Close)
+
+	hc, err := httpclient.NewWithRetry(srv.URL, &configs.HTTPClientConfig{
+		DialTimeout:     time.Second,
+		MaxIdleConns:    1,
+		IdleConnTimeout: time.Second,
+		RequestTimeout:  time.Second,
+		RetryPolicy:     configs.HTTPRetryPolicyConfig{MaxAttempts: 1, RetryOnStatus: map[int]bool{}},
+	}, metrics.NewHTTPRequestMetrics(prometheus.NewRegistry(), "test"), &atomic.Bool{}, testLogger())
+	require.NoError(t, err)
+
+	audiences := map[string]string{"notes": "notes-aud", "files": "files-aud", "billing": "billing"}
+
+	return NewServiceTokenUsecase(hc, "noted-auth-broker", "secret", audiences, 30*time.Second, testLogger(),
+		make(chan string)), &calls
+}
+
+// serviceJWT — неподписанный токен с aud из scope; номер запроса идёт вместо подписи, его достаёт serial.
+func serviceJWT(t *testing.T, scope string, n int32) string {
+	t.Helper()
+
+	aud := []string{}
+	for _, s := range strings.Fields(scope) {
+		if service, ok := strings.CutSuffix(s, "-aud"); ok {
+			aud = append(aud, service)
+		}
+	}
+
+	payload, err := json.Marshal(map[string]any{"aud": aud, "azp": "noted-auth-broker"})
+	require.NoError(t, err)
+
+	return fmt.Sprintf("e30.%s.svc-%d", base64.RawURLEncoding.EncodeToString(payload), n)
+}
+
+func serial(token string) string {
+	return token[strings.LastIndex(token, ".")+1:]
+}
+
+func assertForm(t *testing.T, r *http.Request) bool {
+	t.Helper()
+
+	if err := r.ParseForm(); err != nil {
+		return false
+	}
+
+	return r.PostForm.Get("grant_type") == "client_credentials" && r.PostForm.Get("client_id") == "noted-auth-broker" &&
+		r.PostForm.Get("client_secret") == "secret" && r.PostForm.Get("scope") != "" && !r.PostForm.Has("audience")
+}
+
+func TestServiceTokenUsecase_CachesPerAudienceAndScopes(t *testing.T) {
+	t.Parallel()
+
+	uc, calls := newTokenBroker(t, 0)
+	now := time.Now()
+	uc.now = func() time.Time { return now }
+
+	first, err := uc.Get(context.Background(), "notes", []string{"notes:write", "notes:read"})
+	require.NoError(t, err)
+	require.Equal(t, "svc-1", serial(first.AccessToken))
+	require.Equal(t, "notes-aud notes:read notes:write", first.Scope)
+	require.Equal(t, 300, first.ExpiresIn)
+
+	// другой порядок и повторы — тот же ключ
+	now = now.Add(100 * time.Second)
+	again, err := uc.Get(context.Background(), "notes", []string{"notes:read", "notes:write", "notes:read"})
+	require.NoError(t, err)
+	require.Equal(t, "svc-1", serial(again.AccessToken))
+	require.Equal(t, 200, again.ExpiresIn)
+
+	other, err := uc.Get(context.Background(), "files", []string{"notes:read", "notes:write"})
+	require.NoError(t, err)
+	require.Equal(t, "svc-2", serial(other.AccessToken))
+
+	// за skew до истечения токен запрашивается заново
+	now = now.Add(170 * time.Second)
+	renewed, err := uc.Get(context.Background(), "notes", []string{"notes:read", "notes:write"})
+	require.NoError(t, err)
+	require.Equal(t, "svc-3", serial(renewed.AccessToken))
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestServiceTokenUsecase_CoalescesConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	uc, calls := newTokenBroker(t, 100*time.Millisecond)
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 10)
+
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := uc.Get(context.Background(), "notes", nil)
+			require.NoError(t, err)
+			tokens[i] = serial(token.AccessToken)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), calls.Load())
+	for _, token := range tokens {
+		require.Equal(t, "svc-1", token)
+	}
+}
+
+func TestServiceTokenUsecase_RejectsAudience(t *testing.T) {
+	t.Parallel()
+
+	uc, calls := newTokenBroker(t, 0)
+
+	_, err := uc.Get(context.Background(), "payments", nil)
+	require.ErrorIs(t, err, errorvals.ErrAudienceNotAllowed)
+
+	_, err = uc.Get(context.Background(), "", nil)
+	require.ErrorIs(t, err, errorvals.ErrInvalidArgument)
+	require.Zero(t, calls.Load())
+}
+
+func TestServiceTokenUsecase_RejectsTokenWithoutAudience(t *testing.T) {
+	t.Parallel()
+
+	uc, calls := newTokenBroker(t, 0)
+
+	// scope billing есть, но aud не добавляет — токен не выдаётся и не кэшируется
+	for range 2 {
+		_, err := uc.Get(context.Background(), "billing", []string{"notes:read"})
+		require.ErrorIs(t, err, errorvals.ErrAudienceNotAllowed)
+	}
+	require.Equal(t, int32(2), calls.Load())
+}
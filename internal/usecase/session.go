@@ -2,29 +2,45 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
 	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
+	"github.com/dnonakolesax/noted-auth/internal/useragent"
 )
 
 type SessionUsecase struct {
 	HTTPClientGet    *httpclient.HTTPClient
 	HTTPClientDelete *httpclient.HTTPClient
+	cache            IntrospectionCache
+	parseUserAgent   bool
 	logger           *slog.Logger
 }
 
 func NewSessionUsecase(httpClient *httpclient.HTTPClient, httpClientd *httpclient.HTTPClient,
-	logger *slog.Logger) *SessionUsecase {
+	cache IntrospectionCache, parseUserAgent bool, logger *slog.Logger) *SessionUsecase {
 	return &SessionUsecase{
 		HTTPClientGet:    httpClient,
 		HTTPClientDelete: httpClientd,
+		cache:            cache,
+		parseUserAgent:   parseUserAgent,
 		logger:           logger,
 	}
 }
 
-func (su *SessionUsecase) Get(ctx context.Context, token string) ([]byte, error) {
+// Get возвращает устройства и сессии пользователя в собственном формате, не завязанном на JSON keycloak.
+// userAgent — User-Agent вызывающего, им уточняется текущее устройство, если включён разбор UA.
+func (su *SessionUsecase) Get(ctx context.Context, token string, userAgent string) (model.Sessions, error) {
 	sessionsResponse, err := su.HTTPClientGet.Get(context.TODO(), token)
 	defer func() {
 		if sessionsResponse != nil {
@@ -34,26 +50,128 @@ func (su *SessionUsecase) Get(ctx context.Context, token string) ([]byte, error)
 
 	if err != nil {
 		su.logger.ErrorContext(ctx, "Error getting sessions", slog.String(consts.ErrorLoggerKey, err.Error()))
-		return nil, err
+		return model.Sessions{}, err
 	}
 
 	if sessionsResponse == nil {
 		su.logger.ErrorContext(ctx, "session body nil")
-		return nil, err
+		return model.Sessions{}, fmt.Errorf("%w: empty sessions response", errorvals.ErrUpstreamUnavailable)
+	}
+
+	if sessionsResponse.StatusCode != http.StatusOK {
+		su.logger.ErrorContext(ctx, "Unexpected sessions status code", slog.Int("status", sessionsResponse.StatusCode))
+		if sessionsResponse.StatusCode == http.StatusUnauthorized {
+			return model.Sessions{}, fmt.Errorf("%w: sessions status code: %d", errorvals.ErrTokenInvalid,
+				sessionsResponse.StatusCode)
+		}
+		return model.Sessions{}, fmt.Errorf("sessions status code: %d", sessionsResponse.StatusCode)
 	}
 
 	body, err := io.ReadAll(sessionsResponse.Body)
 
 	if err != nil {
 		su.logger.ErrorContext(ctx, "Error reading response body", slog.String(consts.ErrorLoggerKey, err.Error()))
-		return nil, err
+		return model.Sessions{}, err
 	}
 
-	return body, nil
+	var devices model.KeycloakDevices
+	err = devices.UnmarshalJSON(body)
+
+	if err != nil {
+		su.logger.ErrorContext(ctx, "Error decoding sessions", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.Sessions{}, err
+	}
+
+	currentSID := currentSessionID(ctx)
+	sessions := model.Sessions{Version: model.SessionsVersion, Devices: make([]model.Device, 0, len(devices))}
+
+	for _, kcDevice := range devices {
+		device := su.toDevice(kcDevice, currentSID)
+
+		if device.Current && su.parseUserAgent && userAgent != "" {
+			ua := useragent.Parse(userAgent)
+			device.Browser, device.BrowserVersion = ua.Browser, ua.BrowserVersion
+			device.OS, device.OSVersion = ua.OS, ua.OSVersion
+			device.DeviceClass = ua.DeviceClass
+		}
+
+		sessions.Devices = append(sessions.Devices, device)
+	}
+
+	return sessions, nil
 }
 
-func (su *SessionUsecase) Delete(ctx context.Context, token string, id string) error {
-	deleteResponse, err := su.HTTPClientDelete.Delete(context.TODO(), token, id)
+func (su *SessionUsecase) toDevice(kcDevice model.KeycloakDevice, currentSID string) model.Device {
+	browser, browserVersion, _ := strings.Cut(kcDevice.Browser, "/")
+	device := model.Device{
+		ID:             kcDevice.ID,
+		IPAddress:      kcDevice.IPAddress,
+		OS:             kcDevice.OS,
+		OSVersion:      kcDevice.OSVersion,
+		Browser:        browser,
+		BrowserVersion: browserVersion,
+		DeviceClass:    useragent.DeviceClassDesktop,
+		LastAccess:     kcDevice.LastAccess,
+		Sessions:       make([]model.Session, 0, len(kcDevice.Sessions)),
+	}
+
+	switch {
+	case kcDevice.Mobile:
+		device.DeviceClass = useragent.DeviceClassMobile
+	case kcDevice.OS == "" || kcDevice.OS == "Other":
+		device.DeviceClass = useragent.DeviceClassUnknown
+	}
+
+	for _, kcSession := range kcDevice.Sessions {
+		session := model.Session{
+			ID:         kcSession.ID,
+			IPAddress:  kcSession.IPAddress,
+			Started:    kcSession.Started,
+			LastAccess: kcSession.LastAccess,
+			Expires:    kcSession.Expires,
+			Current:    currentSID != "" && kcSession.ID == currentSID,
+			Clients:    make([]model.SessionClient, 0, len(kcSession.Clients)),
+		}
+
+		for _, client := range kcSession.Clients {
+			session.Clients = append(session.Clients, model.SessionClient{ClientID: client.ClientID, Name: client.ClientName})
+		}
+
+		device.Current = device.Current || session.Current
+		device.Sessions = append(device.Sessions, session)
+	}
+
+	return device
+}
+
+// Delete удаляет одну сессию по id.
+func (su *SessionUsecase) Delete(ctx context.Context, token string, id string) (model.SessionsRevoked, error) {
+	if id == "" {
+		return model.SessionsRevoked{}, fmt.Errorf("%w: empty session id", errorvals.ErrInvalidArgument)
+	}
+
+	err := su.deleteOne(ctx, token, id)
+
+	if err != nil {
+		return model.SessionsRevoked{}, err
+	}
+
+	return model.SessionsRevoked{Revoked: []string{id}, CurrentRevoked: id == currentSessionID(ctx)}, nil
+}
+
+// DeleteAll удаляет все сессии пользователя, при keepCurrent — кроме сессии, которой выдан token.
+// Список сессий берётся до удаления, чтобы вернуть, что именно было отозвано.
+func (su *SessionUsecase) DeleteAll(ctx context.Context, token string, keepCurrent bool) (model.SessionsRevoked,
+	error) {
+	sessions, err := su.Get(ctx, token, "")
+
+	if err != nil {
+		return model.SessionsRevoked{}, err
+	}
+
+	// keycloak: current=true — удалить и текущую сессию тоже
+	deleteResponse, err := su.HTTPClientDelete.DeleteAll(ctx, token,
+		url.Values{"current": {strconv.FormatBool(!keepCurrent)}})
 	defer func() {
 		if deleteResponse != nil {
 			_ = deleteResponse.Body.Close()
@@ -61,9 +179,108 @@ func (su *SessionUsecase) Delete(ctx context.Context, token string, id string) e
 	}()
 
 	if err != nil {
-		su.logger.ErrorContext(ctx, "Error deleting response", slog.String(consts.ErrorLoggerKey, err.Error()))
+		su.logger.ErrorContext(ctx, "Error deleting sessions", slog.String(consts.ErrorLoggerKey, err.Error()),
+			principal.LogAttr(ctx))
+		return model.SessionsRevoked{}, err
+	}
+
+	revoked := model.SessionsRevoked{Revoked: make([]string, 0)}
+
+	for _, device := range sessions.Devices {
+		for _, session := range device.Sessions {
+			if keepCurrent && session.Current {
+				continue
+			}
+
+			su.purgeCachedSession(ctx, session.ID)
+			revoked.Revoked = append(revoked.Revoked, session.ID)
+			revoked.CurrentRevoked = revoked.CurrentRevoked || session.Current
+		}
+	}
+
+	su.logger.InfoContext(ctx, "Sessions deleted", slog.Int("count", len(revoked.Revoked)),
+		slog.Bool("keep_current", keepCurrent), principal.LogAttr(ctx))
+
+	return revoked, nil
+}
+
+// DeleteByClient удаляет сессии, в которых участвует клиент clientID. В account API нет удаления
+// по клиенту, поэтому сессии удаляются по одной; неудавшиеся попадают в Failed, остальные не откатываются.
+func (su *SessionUsecase) DeleteByClient(ctx context.Context, token string, clientID string) (model.SessionsRevoked,
+	error) {
+	if clientID == "" {
+		return model.SessionsRevoked{}, fmt.Errorf("%w: empty client id", errorvals.ErrInvalidArgument)
+	}
+
+	sessions, err := su.Get(ctx, token, "")
+
+	if err != nil {
+		return model.SessionsRevoked{}, err
+	}
+
+	revoked := model.SessionsRevoked{Revoked: make([]string, 0)}
+
+	for _, device := range sessions.Devices {
+		for _, session := range device.Sessions {
+			if !slices.ContainsFunc(session.Clients, func(c model.SessionClient) bool { return c.ClientID == clientID }) {
+				continue
+			}
+
+			err = su.deleteOne(ctx, token, session.ID)
+			if err != nil {
+				revoked.Failed = append(revoked.Failed, session.ID)
+				continue
+			}
+
+			revoked.Revoked = append(revoked.Revoked, session.ID)
+			revoked.CurrentRevoked = revoked.CurrentRevoked || session.Current
+		}
+	}
+
+	su.logger.InfoContext(ctx, "Client sessions deleted", slog.String("client", clientID),
+		slog.Int("count", len(revoked.Revoked)), slog.Int("failed", len(revoked.Failed)), principal.LogAttr(ctx))
+
+	return revoked, nil
+}
+
+func (su *SessionUsecase) deleteOne(ctx context.Context, token string, id string) error {
+	deleteResponse, err := su.HTTPClientDelete.Delete(ctx, token, id)
+	defer func() {
+		if deleteResponse != nil {
+			_ = deleteResponse.Body.Close()
+		}
+	}()
+
+	if err != nil {
+		su.logger.ErrorContext(ctx, "Error deleting response", slog.String(consts.ErrorLoggerKey, err.Error()),
+			principal.LogAttr(ctx))
 		return err
 	}
+	su.logger.InfoContext(ctx, "Session deleted", slog.String("session", id), principal.LogAttr(ctx))
+
+	su.purgeCachedSession(ctx, id)
 
 	return nil
 }
+
+func (su *SessionUsecase) purgeCachedSession(ctx context.Context, id string) {
+	if su.cache == nil {
+		return
+	}
+
+	// иначе токены удалённой сессии будут считаться активными до истечения записи в кэше
+	err := su.cache.PurgeSession(ctx, id)
+
+	if err != nil {
+		su.logger.ErrorContext(ctx, "Error purging session from introspection cache",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+	}
+}
+
+func currentSessionID(ctx context.Context) string {
+	if p, ok := principal.FromContext(ctx); ok {
+		return p.SessionID
+	}
+
+	return ""
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/httpclient"
+	"github.com/dnonakolesax/noted-auth/internal/metrics"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
+	"github.com/dnonakolesax/noted-auth/internal/useragent"
+)
+
+const devicesJSON = `[
+  {"id":"d1","ipAddress":"10.0.0.1","os":"Windows","osVersion":"10","browser":"Chrome/120.0.0","device":"Other",
+   "lastAccess":1700000100,"current":true,"mobile":false,
+   "sessions":[{"id":"s1","ipAddress":"10.0.0.1","started":1700000000,"lastAccess":1700000100,"expires":1700036000,
+     "browser":"Chrome/120.0.0","current":true,"clients":[{"clientId":"noted","clientName":"Noted"}]}]},
+  {"id":"d2","ipAddress":"10.0.0.2","os":"Android","osVersion":"14","browser":"Firefox/121.0","device":"Pixel",
+   "lastAccess":1700000050,"mobile":true,
+   "sessions":[{"id":"s2","ipAddress":"10.0.0.2","started":1700000000,"lastAccess":1700000050,"expires":1700036000,
+     "browser":"Firefox/121.0","clients":[]}]}
+]`
+
+// sessionsEndpoint — account API keycloak: GET .../sessions/devices и DELETE .../sessions[/{id}].
+type sessionsEndpoint struct {
+	mu      sync.Mutex
+	deleted []string
+}
+
+func (se *sessionsEndpoint) deletedRequests() []string {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	return slices.Clone(se.deleted)
+}
+
+func newSessionsEndpoint(t *testing.T) (*httpclient.HTTPClient, *httpclient.HTTPClient, *sessionsEndpoint) {
+	t.Helper()
+
+	se := &sessionsEndpoint{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case http.MethodDelete:
+			se.mu.Lock()
+			se.deleted = append(se.deleted, r.URL.RequestURI())
+			se.mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(devicesJSON))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	newClient := func(endpoint string) *httpclient.HTTPClient {
+		hc, err := httpclient.NewWithRetry(endpoint, &configs.HTTPClientConfig{
+			DialTimeout:     time.Second,
+			MaxIdleConns:    1,
+			IdleConnTimeout: time.Second,
+			RequestTimeout:  time.Second,
+			RetryPolicy:     configs.HTTPRetryPolicyConfig{MaxAttempts: 1, RetryOnStatus: map[int]bool{}},
+		}, metrics.NewHTTPRequestMetrics(prometheus.NewRegistry(), "test"), &atomic.Bool{}, testLogger())
+		require.NoError(t, err)
+		return hc
+	}
+
+	return newClient(srv.URL + "/account/sessions/devices"), newClient(srv.URL + "/account/sessions"), se
+}
+
+func TestSessionUsecase_Get_NormalizesAndMarksCurrent(t *testing.T) {
+	t.Parallel()
+
+	get, del, _ := newSessionsEndpoint(t)
+	su := NewSessionUsecase(get, del, nil, true, testLogger())
+	// текущей считается сессия вызывающего, а не поле current из ответа keycloak
+	ctx := principal.WithPrincipal(context.Background(), principal.Principal{Subject: "u1", SessionID: "s2"})
+	ua := "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) " +
+		"Chrome/120.0.0.0 Mobile Safari/537.36"
+
+	sessions, err := su.Get(ctx, "at", ua)
+	require.NoError(t, err)
+
+	require.Equal(t, model.SessionsVersion, sessions.Version)
+	require.Len(t, sessions.Devices, 2)
+
+	desktop := sessions.Devices[0]
+	require.False(t, desktop.Current)
+	require.False(t, desktop.Sessions[0].Current)
+	require.Equal(t, "Chrome", desktop.Browser)
+	require.Equal(t, "120.0.0", desktop.BrowserVersion)
+	require.Equal(t, useragent.DeviceClassDesktop, desktop.DeviceClass)
+	require.Equal(t, []model.SessionClient{{ClientID: "noted", Name: "Noted"}}, desktop.Sessions[0].Clients)
+
+	phone := sessions.Devices[1]
+	require.True(t, phone.Current)
+	require.True(t, phone.Sessions[0].Current)
+	// текущее устройство уточнено по User-Agent запроса
+	require.Equal(t, "Chrome", phone.Browser)
+	require.Equal(t, useragent.DeviceClassMobile, phone.DeviceClass)
+}
+
+func TestSessionUsecase_DeleteAll_KeepsCurrent(t *testing.T) {
+	t.Parallel()
+
+	get, del, se := newSessionsEndpoint(t)
+	su := NewSessionUsecase(get, del, nil, false, testLogger())
+	ctx := principal.WithPrincipal(context.Background(), principal.Principal{Subject: "u1", SessionID: "s1"})
+
+	revoked, err := su.DeleteAll(ctx, "at", true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"s2"}, revoked.Revoked)
+	require.False(t, revoked.CurrentRevoked)
+	require.Equal(t, []string{"/account/sessions?current=false"}, se.deletedRequests())
+}
+
+func TestSessionUsecase_DeleteByClient(t *testing.T) {
+	t.Parallel()
+
+	get, del, se := newSessionsEndpoint(t)
+	su := NewSessionUsecase(get, del, nil, false, testLogger())
+	ctx := principal.WithPrincipal(context.Background(), principal.Principal{Subject: "u1", SessionID: "s1"})
+
+	revoked, err := su.DeleteByClient(ctx, "at", "noted")
+	require.NoError(t, err)
+	require.Equal(t, []string{"s1"}, revoked.Revoked)
+	require.True(t, revoked.CurrentRevoked)
+	require.Equal(t, []string{"/account/sessions/s1"}, se.deletedRequests())
+
+	_, err = su.DeleteByClient(ctx, "at", "")
+	require.ErrorIs(t, err, errorvals.ErrInvalidArgument)
+}
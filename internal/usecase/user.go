@@ -2,26 +2,37 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"log/slog"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/dnonakolesax/noted-auth/internal/configs"
 	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
 	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/principal"
 )
 
 type repo interface {
 	GetUser(ctx context.Context, userID string) (model.User, error)
 	IDByName(ctx context.Context, login string) (model.UserID, error)
+	GetUsers(ctx context.Context, userIDs []string) (map[string]model.User, error)
+	SearchUsers(ctx context.Context, prefix string, after string, limit int) ([]model.FoundUser, error)
 }
 
 type UserUsecase struct {
-	userRepo repo
-	logger   *slog.Logger
+	userRepo      repo
+	serviceConfig configs.ServiceConfig
+	logger        *slog.Logger
 }
 
-func NewUserUsecase(userRepo repo, logger *slog.Logger) *UserUsecase {
+func NewUserUsecase(userRepo repo, serviceConfig configs.ServiceConfig, logger *slog.Logger) *UserUsecase {
 	return &UserUsecase{
-		userRepo: userRepo,
-		logger:   logger,
+		userRepo:      userRepo,
+		serviceConfig: serviceConfig,
+		logger:        logger,
 	}
 }
 
@@ -30,19 +41,110 @@ func (uu *UserUsecase) Get(ctx context.Context, userID string) (model.User, erro
 
 	if err != nil {
 		uu.logger.ErrorContext(ctx, "Error getting user",
-			slog.String(consts.ErrorLoggerKey, err.Error()), slog.String("ID", userID))
+			slog.String(consts.ErrorLoggerKey, err.Error()), slog.String("ID", userID), principal.LogAttr(ctx))
 		return model.User{}, err
 	}
 
 	return user, nil
 }
 
+// GetBatch ищет пользователей по списку id. Повторы схлопываются, ненайденные id
+// перечисляются в Missing в порядке запроса, а не валят весь батч.
+func (uu *UserUsecase) GetBatch(ctx context.Context, userIDs []string) (model.UsersBatch, error) {
+	ids := make([]string, 0, len(userIDs))
+	seen := make(map[string]struct{}, len(userIDs))
+
+	for _, id := range userIDs {
+		if id == "" {
+			return model.UsersBatch{}, fmt.Errorf("%w: empty user id", errorvals.ErrInvalidArgument)
+		}
+
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return model.UsersBatch{}, fmt.Errorf("%w: no user ids", errorvals.ErrInvalidArgument)
+	}
+
+	if len(ids) > uu.serviceConfig.UsersBatchLimit {
+		return model.UsersBatch{}, fmt.Errorf("%w: too many user ids: %d > %d", errorvals.ErrInvalidArgument,
+			len(ids), uu.serviceConfig.UsersBatchLimit)
+	}
+
+	users, err := uu.userRepo.GetUsers(ctx, ids)
+
+	if err != nil {
+		uu.logger.ErrorContext(ctx, "Error getting users",
+			slog.String(consts.ErrorLoggerKey, err.Error()), slog.Int("count", len(ids)), principal.LogAttr(ctx))
+		return model.UsersBatch{}, err
+	}
+
+	missing := make([]string, 0)
+
+	for _, id := range ids {
+		if _, ok := users[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	return model.UsersBatch{Users: users, Missing: missing}, nil
+}
+
+// Search ищет пользователей по префиксу. limit 0 — размер страницы по умолчанию, больше максимума
+// урезается. cursor — непрозрачная строка из NextCursor предыдущей страницы.
+func (uu *UserUsecase) Search(ctx context.Context, query string, limit int, cursor string) (model.UsersPage, error) {
+	query = strings.TrimSpace(query)
+
+	if utf8.RuneCountInString(query) < uu.serviceConfig.UsersSearchMinQuery {
+		return model.UsersPage{}, fmt.Errorf("%w: query must be at least %d characters", errorvals.ErrInvalidArgument,
+			uu.serviceConfig.UsersSearchMinQuery)
+	}
+
+	switch {
+	case limit < 0:
+		return model.UsersPage{}, fmt.Errorf("%w: negative limit", errorvals.ErrInvalidArgument)
+	case limit == 0:
+		limit = uu.serviceConfig.UsersSearchLimit
+	case limit > uu.serviceConfig.UsersSearchMaxLimit:
+		limit = uu.serviceConfig.UsersSearchMaxLimit
+	}
+
+	after, err := base64.RawURLEncoding.DecodeString(cursor)
+
+	if err != nil {
+		return model.UsersPage{}, fmt.Errorf("%w: malformed cursor", errorvals.ErrInvalidArgument)
+	}
+
+	// запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	users, err := uu.userRepo.SearchUsers(ctx, query, string(after), limit+1)
+
+	if err != nil {
+		uu.logger.ErrorContext(ctx, "Error searching users",
+			slog.String(consts.ErrorLoggerKey, err.Error()), principal.LogAttr(ctx))
+		return model.UsersPage{}, err
+	}
+
+	page := model.UsersPage{Users: users}
+
+	if len(users) > limit {
+		page.Users = users[:limit]
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(page.Users[limit-1].Login))
+	}
+
+	return page, nil
+}
+
 func (uu *UserUsecase) GetByUsername(ctx context.Context, username string) (model.UserID, error) {
 	user, err := uu.userRepo.IDByName(ctx, username)
 
 	if err != nil {
 		uu.logger.ErrorContext(ctx, "Error getting user",
-			slog.String(consts.ErrorLoggerKey, err.Error()), slog.String("LOGIN", username))
+			slog.String(consts.ErrorLoggerKey, err.Error()), slog.String("LOGIN", username),
+			principal.LogAttr(ctx))
 		return model.UserID{}, err
 	}
 
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/configs"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+// userRepoStub отдаёт отсортированных по логину пользователей, как search_users.sql.
+type userRepoStub struct {
+	users []model.FoundUser
+}
+
+func (r *userRepoStub) GetUser(context.Context, string) (model.User, error) {
+	return model.User{}, errorvals.ErrObjectNotFoundInRepoError
+}
+
+func (r *userRepoStub) IDByName(context.Context, string) (model.UserID, error) {
+	return model.UserID{}, errorvals.ErrObjectNotFoundInRepoError
+}
+
+func (r *userRepoStub) GetUsers(_ context.Context, ids []string) (map[string]model.User, error) {
+	users := map[string]model.User{}
+	for _, u := range r.users {
+		for _, id := range ids {
+			if u.ID == id {
+				users[id] = model.User{Login: u.Login}
+			}
+		}
+	}
+	return users, nil
+}
+
+func (r *userRepoStub) SearchUsers(_ context.Context, _ string, after string, limit int) ([]model.FoundUser, error) {
+	found := make([]model.FoundUser, 0, limit)
+	for _, u := range r.users {
+		if u.Login > after && len(found) < limit {
+			found = append(found, u)
+		}
+	}
+	return found, nil
+}
+
+func newUserUsecase() *UserUsecase {
+	repo := &userRepoStub{users: []model.FoundUser{
+		{ID: "1", Login: "ann"}, {ID: "2", Login: "anna"}, {ID: "3", Login: "annie"},
+	}}
+	cfg := configs.ServiceConfig{UsersBatchLimit: 2, UsersSearchLimit: 2, UsersSearchMaxLimit: 10, UsersSearchMinQuery: 2}
+
+	return NewUserUsecase(repo, cfg, testLogger())
+}
+
+func TestUserUsecase_Search_Paginates(t *testing.T) {
+	uu := newUserUsecase()
+
+	page, err := uu.Search(context.Background(), "an", 0, "")
+	require.NoError(t, err)
+	require.Len(t, page.Users, 2)
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = uu.Search(context.Background(), "an", 0, page.NextCursor)
+	require.NoError(t, err)
+	require.Equal(t, []model.FoundUser{{ID: "3", Login: "annie"}}, page.Users)
+	require.Empty(t, page.NextCursor)
+
+	_, err = uu.Search(context.Background(), " a ", 0, "")
+	require.ErrorIs(t, err, errorvals.ErrInvalidArgument)
+
+	_, err = uu.Search(context.Background(), "an", 0, "!!!")
+	require.ErrorIs(t, err, errorvals.ErrInvalidArgument)
+}
+
+func TestUserUsecase_GetBatch_ReportsMissing(t *testing.T) {
+	uu := newUserUsecase()
+
+	batch, err := uu.GetBatch(context.Background(), []string{"1", "42", "1"})
+	require.NoError(t, err)
+	require.Contains(t, batch.Users, "1")
+	require.Equal(t, []string{"42"}, batch.Missing)
+
+	_, err = uu.GetBatch(context.Background(), []string{"1", "2", "3"})
+	require.ErrorIs(t, err, errorvals.ErrInvalidArgument)
+}
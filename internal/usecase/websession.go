@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/dnonakolesax/noted-auth/internal/consts"
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+	"github.com/dnonakolesax/noted-auth/internal/rnd"
+)
+
+type WebSessionStore interface {
+	Save(ctx context.Context, id string, tokens model.TokenDTO, ttl time.Duration) error
+	Get(ctx context.Context, id string) (model.TokenDTO, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type tokenResolver interface {
+	GetUserID(ctx context.Context, at string, rt string) (model.TokenGRPCDTO, error)
+	ForgetRefresh(ctx context.Context, rt string)
+}
+
+// WebSessionUsecase — серверные сессии (BFF): браузер знает только случайный id,
+// токены лежат в WebSessionStore и обновляются при разрешении сессии.
+type WebSessionUsecase struct {
+	store      WebSessionStore
+	tokens     tokenResolver
+	idLength   uint
+	defaultTTL time.Duration
+	// refreshes склеивает параллельные запросы одной сессии: refresh token одноразовый,
+	// второй параллельный refresh получил бы отказ от keycloak
+	refreshes singleflight.Group
+	logger    *slog.Logger
+}
+
+func NewWebSessionUsecase(store WebSessionStore, tokens tokenResolver, idLength uint, defaultTTL time.Duration,
+	logger *slog.Logger) *WebSessionUsecase {
+	return &WebSessionUsecase{
+		store:      store,
+		tokens:     tokens,
+		idLength:   idLength,
+		defaultTTL: defaultTTL,
+		logger:     logger,
+	}
+}
+
+// TTL сессии совпадает со временем жизни refresh token.
+func (wu *WebSessionUsecase) ttl(tokens model.TokenDTO) time.Duration {
+	if tokens.RefreshExp > 0 {
+		return time.Duration(tokens.RefreshExp) * time.Second
+	}
+	return wu.defaultTTL
+}
+
+func (wu *WebSessionUsecase) Create(ctx context.Context, tokens model.TokenDTO) (string, error) {
+	raw, err := rnd.GenRandomString(wu.idLength)
+
+	if err != nil {
+		wu.logger.ErrorContext(ctx, "Failed to create crypto-random string (session id)",
+			slog.String(consts.ErrorLoggerKey, err.Error()))
+		return "", err
+	}
+
+	id := base64.RawURLEncoding.EncodeToString(raw)
+	err = wu.store.Save(ctx, id, tokens, wu.ttl(tokens))
+
+	if err != nil {
+		wu.logger.ErrorContext(ctx, "Failed to save session", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (wu *WebSessionUsecase) Resolve(ctx context.Context, id string) (model.WebSession, error) {
+	res, err, _ := wu.refreshes.Do(id, func() (any, error) {
+		return wu.resolve(ctx, id)
+	})
+
+	if err != nil {
+		return model.WebSession{}, err
+	}
+
+	session, _ := res.(model.WebSession)
+	return session, nil
+}
+
+func (wu *WebSessionUsecase) resolve(ctx context.Context, id string) (model.WebSession, error) {
+	tokens, err := wu.store.Get(ctx, id)
+
+	if err != nil {
+		return model.WebSession{}, err
+	}
+
+	dto, err := wu.tokens.GetUserID(ctx, tokens.AccessToken, tokens.RefreshToken)
+
+	if errors.Is(err, errorvals.ErrRefreshTokenReused) {
+		// сессия keycloak уже завершена, хранить её токены незачем
+		if derr := wu.store.Delete(ctx, id); derr != nil {
+			wu.logger.WarnContext(ctx, "Failed to delete revoked session",
+				slog.String(consts.ErrorLoggerKey, derr.Error()))
+		}
+	}
+
+	if err != nil {
+		return model.WebSession{}, err
+	}
+
+	session := model.WebSession{UserID: dto.UserID, Tokens: tokens, Claims: dto.Claims}
+
+	if dto.AccessToken != "" && dto.RefreshToken != "" {
+		session.Tokens = dto.ToTokenDTO()
+		session.Refreshed = true
+
+		// старый refresh token keycloak уже мог отозвать, поэтому несохранённая сессия — ошибка
+		err = wu.store.Save(ctx, id, session.Tokens, wu.ttl(session.Tokens))
+
+		if err != nil {
+			wu.logger.ErrorContext(ctx, "Failed to save refreshed session",
+				slog.String(consts.ErrorLoggerKey, err.Error()))
+			// в хранилище остался старый refresh token, его повтор — не кража
+			wu.tokens.ForgetRefresh(ctx, tokens.RefreshToken)
+			return model.WebSession{}, err
+		}
+	}
+
+	return session, nil
+}
+
+// Destroy удаляет сессию и возвращает её токены, id token нужен для ссылки на logout.
+func (wu *WebSessionUsecase) Destroy(ctx context.Context, id string) (model.TokenDTO, error) {
+	tokens, err := wu.store.Get(ctx, id)
+
+	if err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	err = wu.store.Delete(ctx, id)
+
+	if err != nil {
+		wu.logger.ErrorContext(ctx, "Failed to delete session", slog.String(consts.ErrorLoggerKey, err.Error()))
+		return model.TokenDTO{}, err
+	}
+
+	return tokens, nil
+}
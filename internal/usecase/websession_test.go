@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnonakolesax/noted-auth/internal/errorvals"
+	"github.com/dnonakolesax/noted-auth/internal/model"
+)
+
+type webSessionStoreStub struct {
+	mu       sync.Mutex
+	sessions map[string]model.TokenDTO
+	ttls     map[string]time.Duration
+	saveErr  error
+}
+
+func newWebSessionStoreStub() *webSessionStoreStub {
+	return &webSessionStoreStub{sessions: map[string]model.TokenDTO{}, ttls: map[string]time.Duration{}}
+}
+
+func (s *webSessionStoreStub) Save(_ context.Context, id string, tokens model.TokenDTO, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.sessions[id] = tokens
+	s.ttls[id] = ttl
+	return nil
+}
+
+func (s *webSessionStoreStub) Get(_ context.Context, id string) (model.TokenDTO, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, ok := s.sessions[id]
+	if !ok {
+		return model.TokenDTO{}, errorvals.ErrObjectNotFoundInRepoError
+	}
+	return tokens, nil
+}
+
+func (s *webSessionStoreStub) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+type tokenResolverStub struct {
+	calls   atomic.Int32
+	delay   time.Duration
+	refresh bool
+	err     error
+	// forgotten — refresh token, переданные в ForgetRefresh
+	forgotten []string
+}
+
+func (r *tokenResolverStub) ForgetRefresh(_ context.Context, rt string) {
+	r.forgotten = append(r.forgotten, rt)
+}
+
+func (r *tokenResolverStub) GetUserID(_ context.Context, at string, _ string) (model.TokenGRPCDTO, error) {
+	r.calls.Add(1)
+	time.Sleep(r.delay)
+	if r.err != nil {
+		return model.TokenGRPCDTO{}, r.err
+	}
+	if !r.refresh {
+		return model.TokenGRPCDTO{UserID: "user-" + at}, nil
+	}
+	return model.TokenGRPCDTO{
+		UserID:       "user-" + at,
+		AccessToken:  at + "-new",
+		RefreshToken: "rt-new",
+		IDToken:      "idt-new",
+		RefreshExp:   120,
+	}, nil
+}
+
+func TestWebSessionUsecase_CreateResolveDestroy(t *testing.T) {
+	t.Parallel()
+
+	store := newWebSessionStoreStub()
+	wu := NewWebSessionUsecase(store, &tokenResolverStub{}, 32, time.Hour, testLogger())
+	ctx := context.Background()
+
+	id, err := wu.Create(ctx, model.TokenDTO{AccessToken: "at", RefreshToken: "rt", IDToken: "idt", RefreshExp: 60})
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	require.Equal(t, time.Minute, store.ttls[id])
+
+	other, err := wu.Create(ctx, model.TokenDTO{AccessToken: "at2"})
+	require.NoError(t, err)
+	require.NotEqual(t, id, other)
+	require.Equal(t, time.Hour, store.ttls[other])
+
+	session, err := wu.Resolve(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "user-at", session.UserID)
+	require.Equal(t, "at", session.Tokens.AccessToken)
+	require.False(t, session.Refreshed)
+
+	tokens, err := wu.Destroy(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "idt", tokens.IDToken)
+
+	_, err = wu.Resolve(ctx, id)
+	require.ErrorIs(t, err, errorvals.ErrObjectNotFoundInRepoError)
+}
+
+func TestWebSessionUsecase_Resolve_RefreshSavesNewTokens(t *testing.T) {
+	t.Parallel()
+
+	store := newWebSessionStoreStub()
+	resolver := &tokenResolverStub{refresh: true, delay: 50 * time.Millisecond}
+	wu := NewWebSessionUsecase(store, resolver, 32, time.Hour, testLogger())
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, "sid", model.TokenDTO{AccessToken: "at", RefreshToken: "rt"}, time.Minute))
+
+	// параллельные запросы одной сессии обновляют токены один раз
+	wg := sync.WaitGroup{}
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			session, err := wu.Resolve(ctx, "sid")
+			assert.NoError(t, err)
+			assert.True(t, session.Refreshed)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), resolver.calls.Load())
+
+	saved, err := store.Get(ctx, "sid")
+	require.NoError(t, err)
+	require.Equal(t, "at-new", saved.AccessToken)
+	require.Equal(t, "rt-new", saved.RefreshToken)
+	require.Equal(t, 2*time.Minute, store.ttls["sid"])
+}
+
+func TestWebSessionUsecase_Resolve_ResolverErrorPropagates(t *testing.T) {
+	t.Parallel()
+
+	store := newWebSessionStoreStub()
+	errKC := errors.New("keycloak down")
+	wu := NewWebSessionUsecase(store, &tokenResolverStub{err: errKC}, 32, time.Hour, testLogger())
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, "sid", model.TokenDTO{AccessToken: "at"}, time.Minute))
+
+	_, err := wu.Resolve(ctx, "sid")
+	require.ErrorIs(t, err, errKC)
+}
+
+func TestWebSessionUsecase_Resolve_SaveFailureForgetsRefresh(t *testing.T) {
+	t.Parallel()
+
+	store := newWebSessionStoreStub()
+	resolver := &tokenResolverStub{refresh: true}
+	wu := NewWebSessionUsecase(store, resolver, 32, time.Hour, testLogger())
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, "sid", model.TokenDTO{AccessToken: "at", RefreshToken: "rt"}, time.Minute))
+	store.saveErr = errors.New("redis down")
+
+	_, err := wu.Resolve(ctx, "sid")
+	require.Error(t, err)
+	require.Equal(t, []string{"rt"}, resolver.forgotten)
+}
@@ -0,0 +1,153 @@
+package useragent
+
+import (
+	"strings"
+)
+
+const (
+	DeviceClassDesktop = "desktop"
+	DeviceClassMobile  = "mobile"
+	DeviceClassTablet  = "tablet"
+	DeviceClassBot     = "bot"
+	DeviceClassUnknown = "unknown"
+)
+
+const unknown = "Other"
+
+// Info — то, что удаётся достать из User-Agent. Пустые версии — версия не найдена.
+type Info struct {
+	Browser        string
+	BrowserVersion string
+	OS             string
+	OSVersion      string
+	DeviceClass    string
+}
+
+type token struct {
+	marker string
+	name   string
+}
+
+// browsers проверяются по порядку: Edge и Opera тоже пишут Chrome, Chrome пишет Safari.
+//
+//nolint:gochecknoglobals // таблица соответствий
+var browsers = []token{
+	{"Edg/", "Edge"},
+	{"EdgA/", "Edge"},
+	{"EdgiOS/", "Edge"},
+	{"OPR/", "Opera"},
+	{"YaBrowser/", "Yandex Browser"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"Firefox/", "Firefox"},
+	{"FxiOS/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+	{"Version/", "Safari"},
+}
+
+//nolint:gochecknoglobals // таблица соответствий
+var bots = []string{"bot", "crawler", "spider", "curl/", "wget/", "python-requests", "go-http-client"}
+
+// Parse разбирает User-Agent эвристически: без базы сигнатур, только распространённые браузеры и ОС.
+func Parse(ua string) Info {
+	info := Info{Browser: unknown, OS: unknown, DeviceClass: DeviceClassUnknown}
+
+	if ua == "" {
+		return info
+	}
+
+	lower := strings.ToLower(ua)
+	for _, bot := range bots {
+		if strings.Contains(lower, bot) {
+			info.DeviceClass = DeviceClassBot
+			return info
+		}
+	}
+
+	info.Browser, info.BrowserVersion = parseBrowser(ua)
+	info.OS, info.OSVersion = parseOS(ua)
+	info.DeviceClass = deviceClass(ua, info.OS)
+
+	return info
+}
+
+func parseBrowser(ua string) (string, string) {
+	for _, b := range browsers {
+		if b.name == "Safari" && !strings.Contains(ua, "Safari/") {
+			continue
+		}
+
+		if _, rest, found := strings.Cut(ua, b.marker); found {
+			return b.name, versionPrefix(rest)
+		}
+	}
+
+	return unknown, ""
+}
+
+func parseOS(ua string) (string, string) {
+	switch {
+	case strings.Contains(ua, "Windows NT "):
+		return "Windows", windowsVersion(after(ua, "Windows NT "))
+	case strings.Contains(ua, "Android"):
+		return "Android", versionPrefix(after(ua, "Android "))
+	case strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPad") || strings.Contains(ua, "iPod"):
+		return "iOS", strings.ReplaceAll(versionPrefix(after(ua, "OS ")), "_", ".")
+	case strings.Contains(ua, "Mac OS X"):
+		return "macOS", strings.ReplaceAll(versionPrefix(after(ua, "Mac OS X ")), "_", ".")
+	case strings.Contains(ua, "CrOS"):
+		return "ChromeOS", ""
+	case strings.Contains(ua, "Linux"):
+		return "Linux", ""
+	default:
+		return unknown, ""
+	}
+}
+
+func deviceClass(ua string, os string) string {
+	switch {
+	case strings.Contains(ua, "iPad") || strings.Contains(ua, "Tablet"):
+		return DeviceClassTablet
+	// Android-планшеты не пишут Mobile
+	case os == "Android" && !strings.Contains(ua, "Mobile"):
+		return DeviceClassTablet
+	case strings.Contains(ua, "Mobi") || strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPod"):
+		return DeviceClassMobile
+	case os == unknown:
+		return DeviceClassUnknown
+	default:
+		return DeviceClassDesktop
+	}
+}
+
+// windowsVersion переводит версию ядра в маркетинговую; Windows 11 тоже пишет NT 10.0.
+func windowsVersion(nt string) string {
+	switch versionPrefix(nt) {
+	case "10.0":
+		return "10"
+	case "6.3":
+		return "8.1"
+	case "6.2":
+		return "8"
+	case "6.1":
+		return "7"
+	default:
+		return ""
+	}
+}
+
+func after(s string, marker string) string {
+	_, rest, _ := strings.Cut(s, marker)
+	return rest
+}
+
+// versionPrefix возвращает ведущие цифры, точки и подчёркивания.
+func versionPrefix(s string) string {
+	end := 0
+
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || s[end] == '_') {
+		end++
+	}
+
+	return strings.TrimRight(s[:end], "._")
+}
@@ -0,0 +1,47 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		ua   string
+		want Info
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) " +
+				"Chrome/120.0.0.0 Safari/537.36",
+			Info{"Chrome", "120.0.0.0", "Windows", "10", DeviceClassDesktop},
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) " +
+				"Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			Info{"Edge", "120.0.2210.91", "Windows", "10", DeviceClassDesktop},
+		},
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) " +
+				"Version/17.1.2 Mobile/15E148 Safari/604.1",
+			Info{"Safari", "17.1.2", "iOS", "17.1.2", DeviceClassMobile},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 13; SM-X200) AppleWebKit/537.36 (KHTML, like Gecko) " +
+				"Chrome/119.0.0.0 Safari/537.36",
+			Info{"Chrome", "119.0.0.0", "Android", "13", DeviceClassTablet},
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:121.0) Gecko/20100101 Firefox/121.0",
+			Info{"Firefox", "121.0", "macOS", "10.15", DeviceClassDesktop},
+		},
+		{"curl/8.4.0", Info{"Other", "", "Other", "", DeviceClassBot}},
+		{"", Info{"Other", "", "Other", "", DeviceClassUnknown}},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.want, Parse(c.ua), c.ua)
+	}
+}